@@ -14,7 +14,7 @@ var (
 // DownHandler registers a manual_http_status that always returns an Error
 func DownHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method == "POST" {
-		updater.Update(errors.New("Manual Check"))
+		updater.Update(health.Result{Error: errors.New("Manual Check"), Message: "Manual Check"})
 	} else {
 		w.WriteHeader(http.StatusNotFound)
 	}
@@ -23,7 +23,7 @@ func DownHandler(w http.ResponseWriter, r *http.Request) {
 // UpHandler registers a manual_http_status that always returns nil
 func UpHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method == "POST" {
-		updater.Update(nil)
+		updater.Update(health.Result{})
 	} else {
 		w.WriteHeader(http.StatusNotFound)
 	}
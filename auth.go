@@ -0,0 +1,116 @@
+package health
+
+import (
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"net/netip"
+)
+
+// authorizer decides whether a request may be served. It returns 0 if it may,
+// otherwise the status code to respond with.
+type authorizer func(w http.ResponseWriter, r *http.Request) int
+
+// WithBasicAuth requires requests to carry the given basic auth credentials.
+// Other requests are rejected with 401 Unauthorized.
+func WithBasicAuth(username, password string) HandlerOption {
+	return func(c *handlerConfig) {
+		c.authorizers = append(c.authorizers, func(w http.ResponseWriter, r *http.Request) int {
+			u, p, ok := r.BasicAuth()
+			if !ok || !equal(u, username) || !equal(p, password) {
+				w.Header().Set("WWW-Authenticate", `Basic realm="health"`)
+				return http.StatusUnauthorized
+			}
+			return 0
+		})
+	}
+}
+
+// WithBearerToken requires requests to carry token in a bearer Authorization
+// header. Other requests are rejected with 401 Unauthorized.
+func WithBearerToken(token string) HandlerOption {
+	return func(c *handlerConfig) {
+		c.authorizers = append(c.authorizers, func(w http.ResponseWriter, r *http.Request) int {
+			if !equal(r.Header.Get("Authorization"), "Bearer "+token) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="health"`)
+				return http.StatusUnauthorized
+			}
+			return 0
+		})
+	}
+}
+
+// WithIPAllowlist only serves requests whose remote address is in one of the
+// prefixes. Other requests are rejected with 403 Forbidden. The address is
+// taken from the connection, headers set by proxies are not considered.
+func WithIPAllowlist(prefixes ...netip.Prefix) HandlerOption {
+	return func(c *handlerConfig) {
+		c.authorizers = append(c.authorizers, func(w http.ResponseWriter, r *http.Request) int {
+			host, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				host = r.RemoteAddr
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil {
+				return http.StatusForbidden
+			}
+			addr = addr.Unmap()
+			for _, prefix := range prefixes {
+				if prefix.Contains(addr) {
+					return 0
+				}
+			}
+			return http.StatusForbidden
+		})
+	}
+}
+
+// WithAuthorizer only serves requests for which authorize returns true. Other
+// requests are rejected with 403 Forbidden.
+func WithAuthorizer(authorize func(*http.Request) bool) HandlerOption {
+	return func(c *handlerConfig) {
+		c.authorizers = append(c.authorizers, func(w http.ResponseWriter, r *http.Request) int {
+			if !authorize(r) {
+				return http.StatusForbidden
+			}
+			return 0
+		})
+	}
+}
+
+// WithMinimalResponse makes the handler respond with the status code and the
+// aggregate status only, leaving out the checks. Combined with ForKind it
+// makes an endpoint that is safe to expose without authentication, e.g. to
+// Kubernetes probes.
+func WithMinimalResponse() HandlerOption {
+	return func(c *handlerConfig) {
+		c.minimal = true
+	}
+}
+
+// Authorized wraps handler so that it only serves the requests accepted by
+// the authorizers set with opts, like WithBasicAuth and WithBearerToken, to
+// protect endpoints next to the ones of the registry. Other options are
+// ignored.
+func Authorized(handler http.Handler, opts ...HandlerOption) http.Handler {
+	return protected(func(w http.ResponseWriter, r *http.Request, _ bool) {
+		handler.ServeHTTP(w, r)
+	}, opts)
+}
+
+// authorize runs the authorizers of the handler, responding to r if one of
+// them rejects it. It reports whether r may be served.
+func (c *handlerConfig) authorize(w http.ResponseWriter, r *http.Request) bool {
+	for _, authorize := range c.authorizers {
+		if status := authorize(w, r); status != 0 {
+			http.Error(w, http.StatusText(status), status)
+			return false
+		}
+	}
+	return true
+}
+
+// equal compares a and b in constant time.
+func equal(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
@@ -0,0 +1,66 @@
+package health
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+// TestHandlerAuth ensures requests are rejected unless all authorizers accept
+// them.
+func TestHandlerAuth(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("check", CheckFunc(func() Result { return Result{} }))
+
+	for _, tc := range []struct {
+		name     string
+		opts     []HandlerOption
+		prepare  func(*http.Request)
+		expected int
+	}{
+		{"none", nil, func(*http.Request) {}, http.StatusOK},
+		{"basic missing", []HandlerOption{WithBasicAuth("user", "secret")}, func(*http.Request) {}, http.StatusUnauthorized},
+		{"basic wrong", []HandlerOption{WithBasicAuth("user", "secret")}, func(r *http.Request) { r.SetBasicAuth("user", "guess") }, http.StatusUnauthorized},
+		{"basic", []HandlerOption{WithBasicAuth("user", "secret")}, func(r *http.Request) { r.SetBasicAuth("user", "secret") }, http.StatusOK},
+		{"bearer wrong", []HandlerOption{WithBearerToken("token")}, func(r *http.Request) { r.Header.Set("Authorization", "Bearer guess") }, http.StatusUnauthorized},
+		{"bearer", []HandlerOption{WithBearerToken("token")}, func(r *http.Request) { r.Header.Set("Authorization", "Bearer token") }, http.StatusOK},
+		{"ip denied", []HandlerOption{WithIPAllowlist(netip.MustParsePrefix("10.0.0.0/8"))}, func(r *http.Request) { r.RemoteAddr = "192.168.1.1:1234" }, http.StatusForbidden},
+		{"ip", []HandlerOption{WithIPAllowlist(netip.MustParsePrefix("10.0.0.0/8"))}, func(r *http.Request) { r.RemoteAddr = "10.1.2.3:1234" }, http.StatusOK},
+		{"authorizer denied", []HandlerOption{WithAuthorizer(func(*http.Request) bool { return false })}, func(*http.Request) {}, http.StatusForbidden},
+		{"all required", []HandlerOption{WithBearerToken("token"), WithAuthorizer(func(*http.Request) bool { return false })}, func(r *http.Request) { r.Header.Set("Authorization", "Bearer token") }, http.StatusForbidden},
+	} {
+		req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+		if err != nil {
+			t.Fatalf("Failed to create request.")
+		}
+		tc.prepare(req)
+		recorder := httptest.NewRecorder()
+		registry.Handler(tc.opts...).ServeHTTP(recorder, req)
+		if recorder.Code != tc.expected {
+			t.Errorf("%s: expected status %d, got %d", tc.name, tc.expected, recorder.Code)
+		}
+		if recorder.Code == http.StatusUnauthorized && recorder.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("%s: expected a WWW-Authenticate header", tc.name)
+		}
+	}
+}
+
+// TestHandlerMinimalResponse ensures minimal responses leave out the checks.
+func TestHandlerMinimalResponse(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("check", CheckFunc(func() Result {
+		return Result{Error: errors.New("dial postgres://user:secret@db"), Message: "dial postgres://user:secret@db"}
+	}))
+
+	req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request.")
+	}
+	recorder := httptest.NewRecorder()
+	registry.Handler(WithMinimalResponse()).ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusServiceUnavailable || recorder.Body.String() != StatusFail+"\n" {
+		t.Errorf("unexpected minimal response: %d %q", recorder.Code, recorder.Body.String())
+	}
+}
@@ -0,0 +1,58 @@
+package health
+
+import "time"
+
+// Availability is the health of a check accounted since it was registered,
+// for dashboards showing the availability of dependencies from the service's
+// perspective. Checks are assumed healthy until they first run, and in
+// between runs keep the state of the last one.
+type Availability struct {
+	// Since is when the check was registered.
+	Since time.Time `json:"since"`
+	// HealthySeconds and UnhealthySeconds are the cumulative time the check
+	// was healthy and unhealthy.
+	HealthySeconds   float64 `json:"healthy_seconds"`
+	UnhealthySeconds float64 `json:"unhealthy_seconds"`
+	// Flaps is the number of times the check changed between healthy and
+	// unhealthy.
+	Flaps int `json:"flaps"`
+}
+
+// WithAvailability makes the handler report the Availability of every check
+// in JSON responses.
+func WithAvailability() HandlerOption {
+	return func(c *handlerConfig) {
+		c.availability = true
+	}
+}
+
+// account attributes the time since the last run to the state of that run,
+// records the state of the run ending at now and returns the availability of
+// the check.
+func (rc *registeredCheck) account(now time.Time, healthy bool) Availability {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if rc.accountedAt.IsZero() {
+		rc.accountedAt = rc.registeredAt
+	}
+	if elapsed := now.Sub(rc.accountedAt); elapsed > 0 {
+		if rc.accountedUnhealthy {
+			rc.unhealthyTime += elapsed
+		} else {
+			rc.healthyTime += elapsed
+		}
+		rc.accountedAt = now
+	}
+	if healthy == rc.accountedUnhealthy {
+		rc.flaps++
+	}
+	rc.accountedUnhealthy = !healthy
+
+	return Availability{
+		Since:            rc.registeredAt,
+		HealthySeconds:   rc.healthyTime.Seconds(),
+		UnhealthySeconds: rc.unhealthyTime.Seconds(),
+		Flaps:            rc.flaps,
+	}
+}
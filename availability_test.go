@@ -0,0 +1,58 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestAvailability ensures the time spent in each state and the flaps are
+// accounted.
+func TestAvailability(t *testing.T) {
+	start := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: start}
+	registry := NewRegistry(WithClock(clock))
+	updater := NewStatusUpdater()
+	registry.Register("check", updater)
+
+	clock.Add(10 * time.Second)
+	registry.CheckStatus()
+	updater.Update(Result{Error: errors.New("failing")})
+	clock.Add(20 * time.Second)
+	registry.CheckStatus()
+	clock.Add(30 * time.Second)
+	registry.CheckStatus()
+	updater.Update(Result{})
+	clock.Add(40 * time.Second)
+	availability := registry.CheckStatus()["check"].Availability
+
+	// healthy for the first 10+20 seconds, as the failure was only
+	// observed at the second run
+	expected := Availability{Since: start, HealthySeconds: 30, UnhealthySeconds: 70, Flaps: 2}
+	if availability == nil || *availability != expected {
+		t.Errorf("expected %+v, got %+v", expected, availability)
+	}
+
+	get := func(opts ...HandlerOption) Status {
+		req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+		if err != nil {
+			t.Fatalf("Failed to create request.")
+		}
+		recorder := httptest.NewRecorder()
+		registry.Handler(opts...).ServeHTTP(recorder, req)
+		var status Status
+		if err := json.Unmarshal(recorder.Body.Bytes(), &status); err != nil {
+			t.Fatalf("error decoding response: %v", err)
+		}
+		return status
+	}
+	if status := get(); status["check"].Availability != nil {
+		t.Errorf("expected no availability without WithAvailability, got %+v", status["check"].Availability)
+	}
+	if status := get(WithAvailability()); status["check"].Availability == nil || status["check"].Availability.Flaps != 2 {
+		t.Errorf("unexpected availability: %+v", status["check"].Availability)
+	}
+}
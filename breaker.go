@@ -0,0 +1,162 @@
+package health
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultBreakerCooldown is how long a breaker stays open before it lets a
+// trial call through, unless set with WithBreakerCooldown.
+const DefaultBreakerCooldown = 5 * time.Second
+
+// BreakerState is the state of a Breaker.
+type BreakerState string
+
+const (
+	// BreakerClosed lets every call through, the check is healthy.
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen rejects calls, the check is failing.
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen lets a single trial call through, the check is failing
+	// but the cooldown has passed.
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// WithBreakerCooldown sets how long the breakers of the registry stay open
+// before they let a trial call through. The default is
+// DefaultBreakerCooldown.
+func WithBreakerCooldown(cooldown time.Duration) RegistryOption {
+	return func(registry *Registry) {
+		registry.breakerCooldown = cooldown
+	}
+}
+
+// Breaker is a circuit breaker driven by the results of a check, letting
+// application code skip calls to a dependency its check already reports as
+// failing. It opens when the last run of the check failed. Once the cooldown
+// has passed, it lets a single trial call through, whose outcome is reported
+// with Success or Failure: a success closes the breaker until the check fails
+// again, a failure keeps it open for another cooldown.
+type Breaker struct {
+	registry *Registry
+	name     string
+
+	mu sync.Mutex
+	// openedAt is when the breaker opened or the last trial call started or
+	// failed.
+	openedAt time.Time
+	// trial is set while a trial call is in progress. A trial call that is
+	// never reported is given up after the cooldown.
+	trial bool
+	// closedAt is the number of consecutive failures of the check when a
+	// trial call succeeded, closing the breaker until the check fails again.
+	closedAt int
+}
+
+// Breaker returns the circuit breaker of the named check, creating it on
+// first use. Breakers of checks that are not registered are always closed.
+func (registry *Registry) Breaker(name string) *Breaker {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if registry.breakers == nil {
+		registry.breakers = make(map[string]*Breaker)
+	}
+	b, ok := registry.breakers[name]
+	if !ok {
+		b = &Breaker{registry: registry, name: name, closedAt: -1}
+		registry.breakers[name] = b
+	}
+	return b
+}
+
+// BreakerFor returns the circuit breaker of the named check of the default
+// registry.
+func BreakerFor(name string) *Breaker {
+	return Default().Breaker(name)
+}
+
+// Allow reports whether a call to the dependency should be attempted. In
+// the half-open state it returns true once, for the trial call.
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state() {
+	case BreakerClosed:
+		return true
+	case BreakerHalfOpen:
+		b.trial, b.openedAt = true, b.registry.clock.Now()
+		return true
+	}
+	return false
+}
+
+// State returns the state of the breaker.
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state()
+}
+
+// Success reports that the trial call succeeded, closing the breaker until
+// the check fails again. It does nothing outside of a trial call.
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.trial {
+		b.trial = false
+		b.closedAt = b.failures()
+	}
+}
+
+// Failure reports that the trial call failed, keeping the breaker open for
+// another cooldown. It does nothing outside of a trial call.
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.trial {
+		b.trial = false
+		b.openedAt = b.registry.clock.Now()
+	}
+}
+
+// state returns the state of the breaker, opening it when the check fails.
+// b.mu must be held.
+func (b *Breaker) state() BreakerState {
+	failures := b.failures()
+	if failures == 0 || failures == b.closedAt {
+		b.openedAt, b.trial = time.Time{}, false
+		if failures == 0 {
+			b.closedAt = -1
+		}
+		return BreakerClosed
+	}
+	b.closedAt = -1
+
+	now := b.registry.clock.Now()
+	if b.openedAt.IsZero() {
+		b.openedAt = now
+	}
+	cooldown := b.registry.breakerCooldown
+	if cooldown <= 0 {
+		cooldown = DefaultBreakerCooldown
+	}
+	if now.Sub(b.openedAt) < cooldown {
+		return BreakerOpen
+	}
+	return BreakerHalfOpen
+}
+
+// failures returns the consecutive failures of the last runs of the check,
+// zero if it is healthy, disabled or not registered.
+func (b *Breaker) failures() int {
+	rc := b.registry.lookup(b.name)
+	if rc == nil {
+		return 0
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if !rc.unhealthy || rc.disabled {
+		return 0
+	}
+	return max(rc.failures, 1)
+}
@@ -0,0 +1,69 @@
+package health
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestBreaker ensures breakers follow the results of their check, letting a
+// trial call through once the cooldown has passed.
+func TestBreaker(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	registry := NewRegistry(WithClock(clock), WithBreakerCooldown(time.Second))
+	updater := NewStatusUpdater()
+	registry.Register("database", updater)
+	breaker := registry.Breaker("database")
+	if registry.Breaker("database") != breaker {
+		t.Errorf("expected the same breaker for the same check")
+	}
+
+	registry.CheckStatus()
+	if !breaker.Allow() || breaker.State() != BreakerClosed {
+		t.Errorf("expected the breaker of a healthy check to be closed")
+	}
+
+	updater.Update(Result{Error: errors.New("down")})
+	registry.CheckStatus()
+	if breaker.Allow() || breaker.State() != BreakerOpen {
+		t.Errorf("expected the breaker of a failing check to be open")
+	}
+
+	// a failed trial keeps the breaker open for another cooldown
+	clock.Add(time.Second)
+	if breaker.State() != BreakerHalfOpen || !breaker.Allow() {
+		t.Errorf("expected a trial call after the cooldown")
+	}
+	if breaker.Allow() {
+		t.Errorf("expected a single trial call")
+	}
+	breaker.Failure()
+	clock.Add(time.Second / 2)
+	if breaker.Allow() {
+		t.Errorf("expected the breaker to stay open after a failed trial")
+	}
+
+	// a successful trial closes the breaker until the check fails again
+	clock.Add(time.Second / 2)
+	if !breaker.Allow() {
+		t.Errorf("expected a trial call after the cooldown")
+	}
+	breaker.Success()
+	if !breaker.Allow() || breaker.State() != BreakerClosed {
+		t.Errorf("expected the breaker to close after a successful trial")
+	}
+	registry.CheckStatus()
+	if breaker.Allow() {
+		t.Errorf("expected the breaker to open when the check fails again")
+	}
+
+	updater.Update(Result{})
+	registry.CheckStatus()
+	if !breaker.Allow() {
+		t.Errorf("expected the breaker to close when the check recovers")
+	}
+
+	if !registry.Breaker("missing").Allow() {
+		t.Errorf("expected the breaker of a missing check to be closed")
+	}
+}
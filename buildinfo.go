@@ -0,0 +1,74 @@
+package health
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+// BuildInfo identifies the build of the service, so its health can be
+// correlated with deployments. It is reported in StatusEnvelope responses.
+type BuildInfo struct {
+	Version   string `json:"version,omitempty"`
+	Commit    string `json:"commit,omitempty"`
+	BuildTime string `json:"build_time,omitempty"`
+}
+
+var (
+	buildInfoMu  sync.Mutex
+	buildInfo    BuildInfo
+	buildInfoSet bool
+)
+
+// SetBuildInfo sets the build information reported by the handlers, usually
+// from variables set with -ldflags at build time. Without it, the information
+// recorded by the go command is used, see ReadBuildInfo.
+func SetBuildInfo(version, commit, buildTime string) {
+	buildInfoMu.Lock()
+	defer buildInfoMu.Unlock()
+	buildInfo = BuildInfo{
+		Version:   version,
+		Commit:    commit,
+		BuildTime: buildTime,
+	}
+	buildInfoSet = true
+}
+
+// ReadBuildInfo returns the module version and the VCS revision and time the
+// go command embedded in the running binary. Fields it can not find are left
+// empty.
+func ReadBuildInfo() BuildInfo {
+	var info BuildInfo
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	if bi.Main.Version != "(devel)" {
+		info.Version = bi.Main.Version
+	}
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Commit = setting.Value
+		case "vcs.time":
+			info.BuildTime = setting.Value
+		}
+	}
+	return info
+}
+
+// currentBuildInfo returns the build information set with SetBuildInfo, or
+// read from the binary the first time it is needed. It returns nil if none is
+// known.
+func currentBuildInfo() *BuildInfo {
+	buildInfoMu.Lock()
+	defer buildInfoMu.Unlock()
+	if !buildInfoSet {
+		buildInfo = ReadBuildInfo()
+		buildInfoSet = true
+	}
+	if buildInfo == (BuildInfo{}) {
+		return nil
+	}
+	info := buildInfo
+	return &info
+}
@@ -0,0 +1,59 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestBuildInfo ensures the build information is reported in envelopes and
+// health+json responses.
+func TestBuildInfo(t *testing.T) {
+	defer func() {
+		buildInfoMu.Lock()
+		buildInfoSet = false
+		buildInfoMu.Unlock()
+	}()
+	SetBuildInfo("1.4.2", "8d8623e", "2015-01-01T00:00:00Z")
+
+	registry := NewRegistry()
+	registry.Register("ok", CheckFunc(func() Result { return Result{} }))
+
+	get := func(accept string, body any, opts ...HandlerOption) {
+		req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+		if err != nil {
+			t.Fatalf("Failed to create request.")
+		}
+		req.Header.Set("Accept", accept)
+		recorder := httptest.NewRecorder()
+		registry.Handler(opts...).ServeHTTP(recorder, req)
+		if err := json.Unmarshal(recorder.Body.Bytes(), body); err != nil {
+			t.Fatalf("error decoding response: %v", err)
+		}
+	}
+
+	var envelope StatusEnvelope
+	get("application/json", &envelope, WithEnvelope())
+	expected := BuildInfo{Version: "1.4.2", Commit: "8d8623e", BuildTime: "2015-01-01T00:00:00Z"}
+	if envelope.Build == nil || *envelope.Build != expected {
+		t.Errorf("expected build %+v, got %+v", expected, envelope.Build)
+	}
+
+	var resp HealthJSONResponse
+	get(HealthJSONContentType, &resp)
+	if resp.Version != "1.4.2" || resp.ReleaseID != "8d8623e" {
+		t.Errorf("expected the build's version and commit, got %q and %q", resp.Version, resp.ReleaseID)
+	}
+	get(HealthJSONContentType, &resp, WithReleaseInfo("2", "2.0.0"))
+	if resp.Version != "2" || resp.ReleaseID != "2.0.0" {
+		t.Errorf("expected the release info to take precedence, got %q and %q", resp.Version, resp.ReleaseID)
+	}
+
+	SetBuildInfo("", "", "")
+	envelope = StatusEnvelope{}
+	get("application/json", &envelope, WithEnvelope())
+	if envelope.Build != nil {
+		t.Errorf("expected no build without build information, got %+v", envelope.Build)
+	}
+}
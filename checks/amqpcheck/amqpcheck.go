@@ -0,0 +1,91 @@
+// Package amqpcheck checks that an AMQP connection, e.g. to RabbitMQ, is open
+// and, optionally, that queues exist.
+//
+// The checker accepts small interfaces rather than a specific client, so
+// using it adds no dependency. The Connection and Channel types of
+// github.com/rabbitmq/amqp091-go implement Connection as they are:
+//
+//	health.Register("rabbitmq", amqpcheck.New(conn))
+//
+// Checking queues needs a QueueInspector, which wraps a channel in a few
+// lines:
+//
+//	type inspector struct{ ch *amqp.Channel }
+//
+//	func (i inspector) InspectQueue(name string) (int, error) {
+//		q, err := i.ch.QueueDeclarePassive(name, true, false, false, false, nil)
+//		return q.Messages, err
+//	}
+package amqpcheck
+
+import (
+	"errors"
+
+	"github.com/docker/distribution/health"
+)
+
+// Connection is an AMQP connection or channel.
+type Connection interface {
+	IsClosed() bool
+}
+
+// QueueInspector looks up queues on the broker.
+type QueueInspector interface {
+	// InspectQueue returns the number of messages ready in the queue, or an
+	// error if it does not exist.
+	InspectQueue(name string) (int, error)
+}
+
+// checker checks an AMQP connection.
+type checker struct {
+	conn      Connection
+	inspector QueueInspector
+	queues    []string
+}
+
+// Option configures a checker created by New.
+type Option func(*checker)
+
+// WithQueues makes the check fail unless inspector finds all the queues. The
+// number of messages ready in each queue is reported in the Result details.
+func WithQueues(inspector QueueInspector, queues ...string) Option {
+	return func(c *checker) {
+		c.inspector = inspector
+		c.queues = queues
+	}
+}
+
+// New returns a checker that fails when conn is closed.
+func New(conn Connection, opts ...Option) health.Checker {
+	c := &checker{conn: conn}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Check implements the Checker interface
+func (c *checker) Check() health.Result {
+	if c.conn.IsClosed() {
+		return failure("amqp connection closed")
+	}
+	if len(c.queues) == 0 {
+		return health.Result{}
+	}
+
+	details := make(map[string]any, len(c.queues))
+	for _, queue := range c.queues {
+		messages, err := c.inspector.InspectQueue(queue)
+		if err != nil {
+			return failure("amqp queue " + queue + " unavailable: " + err.Error())
+		}
+		details[queue] = messages
+	}
+	return health.Result{Details: details}
+}
+
+// failure returns an unhealthy Result carrying msg as both the error and the
+// message.
+func failure(msg string) health.Result {
+	return health.Result{Error: errors.New(msg), Message: msg}
+}
@@ -0,0 +1,50 @@
+package amqpcheck
+
+import (
+	"errors"
+	"testing"
+)
+
+type fakeConn struct {
+	closed bool
+}
+
+func (c fakeConn) IsClosed() bool {
+	return c.closed
+}
+
+// fakeInspector knows queues by their number of messages.
+type fakeInspector map[string]int
+
+func (i fakeInspector) InspectQueue(name string) (int, error) {
+	messages, ok := i[name]
+	if !ok {
+		return 0, errors.New("NOT_FOUND - no queue '" + name + "'")
+	}
+	return messages, nil
+}
+
+func TestChecker(t *testing.T) {
+	inspector := fakeInspector{"jobs": 3}
+
+	for _, tc := range []struct {
+		name    string
+		conn    Connection
+		opts    []Option
+		healthy bool
+	}{
+		{"open", fakeConn{}, nil, true},
+		{"closed", fakeConn{closed: true}, nil, false},
+		{"queue", fakeConn{}, []Option{WithQueues(inspector, "jobs")}, true},
+		{"missing queue", fakeConn{}, []Option{WithQueues(inspector, "jobs", "mail")}, false},
+	} {
+		res := New(tc.conn, tc.opts...).Check()
+		if healthy := res.Error == nil; healthy != tc.healthy {
+			t.Errorf("%s: expected healthy to be %v, got result %+v", tc.name, tc.healthy, res)
+		}
+	}
+
+	if res := New(fakeConn{}, WithQueues(inspector, "jobs")).Check(); res.Details["jobs"] != 3 {
+		t.Errorf("expected queue length in details, got %+v", res)
+	}
+}
@@ -0,0 +1,34 @@
+package checks
+
+import (
+	"context"
+
+	"github.com/docker/distribution/health"
+)
+
+// BucketClient is the minimal interface of an object storage client needed
+// to check a bucket. It is usually a small adapter, e.g. for the AWS SDK:
+//
+//	type s3Client struct{ *s3.Client }
+//
+//	func (c s3Client) HeadBucket(ctx context.Context, bucket string) error {
+//		_, err := c.Client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: &bucket})
+//		return err
+//	}
+type BucketClient interface {
+	// HeadBucket returns an error if the bucket can not be accessed, e.g.
+	// because it does not exist, the credentials are rejected or the
+	// service can not be reached.
+	HeadBucket(ctx context.Context, bucket string) error
+}
+
+// Bucket checks that bucket is reachable and accessible with the credentials
+// of client, verifying an object storage dependency like S3, GCS or MinIO.
+func Bucket(client BucketClient, bucket string) health.CheckerWithContext {
+	return health.CheckFuncWithContext(func(ctx context.Context) health.Result {
+		if err := client.HeadBucket(ctx, bucket); err != nil {
+			return failureErr("bucket "+bucket+" unavailable: "+err.Error(), err)
+		}
+		return health.Result{}
+	})
+}
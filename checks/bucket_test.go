@@ -0,0 +1,30 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// fakeBucketClient knows a single bucket.
+type fakeBucketClient struct {
+	bucket string
+}
+
+func (c fakeBucketClient) HeadBucket(ctx context.Context, bucket string) error {
+	if bucket != c.bucket {
+		return errors.New("NotFound")
+	}
+	return nil
+}
+
+func TestBucket(t *testing.T) {
+	client := fakeBucketClient{bucket: "uploads"}
+
+	if res := Bucket(client, "uploads").Check(context.Background()); res.Error != nil {
+		t.Errorf("unexpected result for existing bucket: %+v", res)
+	}
+	if res := Bucket(client, "backups").Check(context.Background()); res.Error == nil || res.Message != "bucket backups unavailable: NotFound" {
+		t.Errorf("unexpected result for missing bucket: %+v", res)
+	}
+}
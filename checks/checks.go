@@ -14,24 +14,24 @@ import (
 // FileChecker checks the existence of a file and returns an error
 // if the file exists.
 func FileChecker(f string) health.Checker {
-	return health.CheckFunc(func() error {
+	return health.CheckFunc(func() health.Result {
 		if _, err := os.Stat(f); err == nil {
-			return errors.New("file exists")
+			return failure("file exists")
 		}
-		return nil
+		return health.Result{}
 	})
 }
 
 // HTTPChecker does a HEAD request and verifies that the HTTP status code
 // returned matches statusCode.
 func HTTPChecker(r string, statusCode int, timeout time.Duration, headers http.Header) health.Checker {
-	return health.CheckFunc(func() error {
+	return health.CheckFunc(func() health.Result {
 		client := http.Client{
 			Timeout: timeout,
 		}
 		req, err := http.NewRequest("HEAD", r, nil)
 		if err != nil {
-			return errors.New("error creating request: " + r)
+			return failure("error creating request: " + r)
 		}
 		for headerName, headerValues := range headers {
 			for _, headerValue := range headerValues {
@@ -40,23 +40,51 @@ func HTTPChecker(r string, statusCode int, timeout time.Duration, headers http.H
 		}
 		response, err := client.Do(req)
 		if err != nil {
-			return errors.New("error while checking: " + r)
+			return failureErr("error while checking: "+r, err)
 		}
 		if response.StatusCode != statusCode {
-			return errors.New("downstream service returned unexpected status: " + strconv.Itoa(response.StatusCode))
+			return statusFailure(response.StatusCode)
 		}
-		return nil
+		return health.Result{}
 	})
 }
 
 // TCPChecker attempts to open a TCP connection.
 func TCPChecker(addr string, timeout time.Duration) health.Checker {
-	return health.CheckFunc(func() error {
+	return health.CheckFunc(func() health.Result {
 		conn, err := net.DialTimeout("tcp", addr, timeout)
 		if err != nil {
-			return errors.New("connection to " + addr + " failed")
+			return failureErr("connection to "+addr+" failed", err)
 		}
 		conn.Close()
-		return nil
+		return health.Result{}
 	})
 }
+
+// failure returns an unhealthy Result carrying msg as both the error and the
+// message.
+func failure(msg string) health.Result {
+	return health.Result{Error: errors.New(msg), Message: msg}
+}
+
+// failureCode returns an unhealthy Result carrying msg, with code.
+func failureCode(code health.Code, msg string) health.Result {
+	res := failure(msg)
+	res.Code = code
+	return res
+}
+
+// failureErr returns an unhealthy Result carrying msg, with the code of err.
+func failureErr(msg string, err error) health.Result {
+	return failureCode(health.CodeOf(err), msg)
+}
+
+// statusFailure returns an unhealthy Result for an HTTP response with an
+// unexpected status code.
+func statusFailure(statusCode int) health.Result {
+	code := health.CodeUnavailable
+	if statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden {
+		code = health.CodeAuth
+	}
+	return failureCode(code, "downstream service returned unexpected status: "+strconv.Itoa(statusCode))
+}
@@ -5,21 +5,21 @@ import (
 )
 
 func TestFileChecker(t *testing.T) {
-	if err := FileChecker("/tmp").Check(); err == nil {
+	if err := FileChecker("/tmp").Check().Error; err == nil {
 		t.Errorf("/tmp was expected as exists")
 	}
 
-	if err := FileChecker("NoSuchFileFromMoon").Check(); err != nil {
+	if err := FileChecker("NoSuchFileFromMoon").Check().Error; err != nil {
 		t.Errorf("NoSuchFileFromMoon was expected as not exists, error:%v", err)
 	}
 }
 
 func TestHTTPChecker(t *testing.T) {
-	if err := HTTPChecker("https://www.google.cybertron", 200, 0, nil).Check(); err == nil {
+	if err := HTTPChecker("https://www.google.cybertron", 200, 0, nil).Check().Error; err == nil {
 		t.Errorf("Google on Cybertron was expected as not exists")
 	}
 
-	if err := HTTPChecker("https://www.google.pt", 200, 0, nil).Check(); err != nil {
+	if err := HTTPChecker("https://www.google.pt", 200, 0, nil).Check().Error; err != nil {
 		t.Errorf("Google at Portugal was expected as exists, error:%v", err)
 	}
 }
@@ -0,0 +1,108 @@
+package checks
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/docker/distribution/health"
+)
+
+// ntpEpochOffset is the number of seconds between the NTP epoch, 1900, and
+// the Unix epoch.
+const ntpEpochOffset = 2208988800
+
+// NTPSkew checks that the local clock is within maxSkew of the clock of the
+// NTP server at addr, e.g. "pool.ntp.org:123". Clock skew breaks token
+// validation and TLS. The Result message contains the measured offset.
+func NTPSkew(addr string, maxSkew, timeout time.Duration) health.Checker {
+	return health.CheckFunc(func() health.Result {
+		offset, err := ntpOffset(addr, timeout)
+		if err != nil {
+			return failureErr("querying "+addr+" failed: "+err.Error(), err)
+		}
+		return skewResult(offset, maxSkew)
+	})
+}
+
+// HTTPDateSkew checks that the local clock is within maxSkew of the Date
+// header returned by a HEAD request to url, for environments where NTP is
+// not reachable. The header has a resolution of one second, so maxSkew should
+// be larger than that.
+func HTTPDateSkew(url string, maxSkew, timeout time.Duration) health.Checker {
+	client := &http.Client{
+		Timeout: timeout,
+	}
+	return health.CheckFunc(func() health.Result {
+		start := time.Now()
+		response, err := client.Head(url)
+		if err != nil {
+			return failureErr("error while checking: "+url, err)
+		}
+		response.Body.Close()
+		end := time.Now()
+
+		date, err := http.ParseTime(response.Header.Get("Date"))
+		if err != nil {
+			return failure("invalid Date header from " + url)
+		}
+		// The header truncates the server's time to the second, assume it
+		// was taken halfway through the request and the second.
+		local := start.Add(end.Sub(start) / 2)
+		return skewResult(date.Add(500*time.Millisecond).Sub(local), maxSkew)
+	})
+}
+
+// skewResult fails if offset exceeds maxSkew in either direction.
+func skewResult(offset, maxSkew time.Duration) health.Result {
+	if offset > maxSkew || offset < -maxSkew {
+		return failureCode(health.CodeThreshold, "clock skew "+offset.String()+" exceeds "+maxSkew.String())
+	}
+	return health.Result{Message: "clock offset " + offset.String()}
+}
+
+// ntpOffset returns the offset of the clock of the NTP server at addr from
+// the local clock, using a single SNTP request.
+func ntpOffset(addr string, timeout time.Duration) (time.Duration, error) {
+	conn, err := net.DialTimeout("udp", addr, timeout)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return 0, err
+	}
+
+	// Leap indicator 0, version 3, client mode.
+	req := make([]byte, 48)
+	req[0] = 0x1b
+	sent := time.Now()
+	if _, err := conn.Write(req); err != nil {
+		return 0, err
+	}
+	resp := make([]byte, 48)
+	n, err := conn.Read(resp)
+	if err != nil {
+		return 0, err
+	}
+	received := time.Now()
+	if n < 48 {
+		return 0, errors.New("short NTP response")
+	}
+	if mode := resp[0] & 0x7; mode != 4 {
+		return 0, errors.New("unexpected NTP response mode")
+	}
+
+	serverReceived := ntpTime(resp[32:40])
+	serverSent := ntpTime(resp[40:48])
+	return (serverReceived.Sub(sent) + serverSent.Sub(received)) / 2, nil
+}
+
+// ntpTime decodes a 64 bit NTP timestamp.
+func ntpTime(b []byte) time.Time {
+	seconds := int64(binary.BigEndian.Uint32(b[:4])) - ntpEpochOffset
+	fraction := int64(binary.BigEndian.Uint32(b[4:]))
+	return time.Unix(seconds, fraction*int64(time.Second)>>32)
+}
@@ -0,0 +1,67 @@
+package checks
+
+import (
+	"encoding/binary"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// ntpServer serves SNTP responses from a clock offset from the local one.
+func ntpServer(t *testing.T, offset time.Duration) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 48)
+		for {
+			_, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			now := time.Now().Add(offset)
+			seconds := uint32(now.Unix() + ntpEpochOffset)
+			fraction := uint32(uint64(now.Nanosecond()) << 32 / uint64(time.Second))
+
+			resp := make([]byte, 48)
+			resp[0] = 0x1c // version 3, server mode
+			for _, i := range []int{32, 40} {
+				binary.BigEndian.PutUint32(resp[i:], seconds)
+				binary.BigEndian.PutUint32(resp[i+4:], fraction)
+			}
+			conn.WriteTo(resp, addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func TestNTPSkew(t *testing.T) {
+	if res := NTPSkew(ntpServer(t, 0), time.Second, time.Second).Check(); res.Error != nil {
+		t.Errorf("unexpected result for synchronized clock: %+v", res)
+	}
+	if res := NTPSkew(ntpServer(t, time.Minute), time.Second, time.Second).Check(); res.Error == nil {
+		t.Errorf("skewed clock was expected to fail")
+	}
+}
+
+func TestHTTPDateSkew(t *testing.T) {
+	server := func(offset time.Duration) string {
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Date", time.Now().Add(offset).UTC().Format(http.TimeFormat))
+		}))
+		t.Cleanup(s.Close)
+		return s.URL
+	}
+
+	if res := HTTPDateSkew(server(0), 2*time.Second, time.Second).Check(); res.Error != nil {
+		t.Errorf("unexpected result for synchronized clock: %+v", res)
+	}
+	if res := HTTPDateSkew(server(-time.Hour), 2*time.Second, time.Second).Check(); res.Error == nil {
+		t.Errorf("skewed clock was expected to fail")
+	}
+}
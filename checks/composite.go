@@ -0,0 +1,72 @@
+package checks
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/docker/distribution/health"
+)
+
+// All combines checks into a check that is healthy only if all of them are.
+// All checks are run, the message lists the ones that failed by their
+// position.
+func All(checks ...health.Checker) health.Checker {
+	return health.CheckFunc(func() health.Result {
+		var failures []string
+		for i, check := range checks {
+			if res := check.Check(); res.Error != nil {
+				failures = append(failures, describe(i, res))
+			}
+		}
+		if len(failures) > 0 {
+			return failure(strconv.Itoa(len(failures)) + " of " + strconv.Itoa(len(checks)) + " checks failed: " + strings.Join(failures, "; "))
+		}
+		return health.Result{}
+	})
+}
+
+// Any combines checks into a check that is healthy if at least one of them
+// is. All checks are run, the message lists the ones that failed by their
+// position.
+func Any(checks ...health.Checker) health.Checker {
+	return health.CheckFunc(func() health.Result {
+		var failures []string
+		for i, check := range checks {
+			if res := check.Check(); res.Error != nil {
+				failures = append(failures, describe(i, res))
+			}
+		}
+		if len(failures) == len(checks) {
+			return failure("all checks failed: " + strings.Join(failures, "; "))
+		}
+		if len(failures) > 0 {
+			return health.Result{Message: strconv.Itoa(len(failures)) + " of " + strconv.Itoa(len(checks)) + " checks failed: " + strings.Join(failures, "; ")}
+		}
+		return health.Result{}
+	})
+}
+
+// FirstSuccessful runs checks in order until one is healthy and returns its
+// result. If none are, the message lists why each failed.
+func FirstSuccessful(checks ...health.Checker) health.Checker {
+	return health.CheckFunc(func() health.Result {
+		var failures []string
+		for i, check := range checks {
+			res := check.Check()
+			if res.Error == nil {
+				return res
+			}
+			failures = append(failures, describe(i, res))
+		}
+		return failure("all checks failed: " + strings.Join(failures, "; "))
+	})
+}
+
+// describe describes the failed result of the i-th sub-check.
+func describe(i int, res health.Result) string {
+	msg := res.Message
+	if msg == "" {
+		msg = res.Error.Error()
+	}
+	return "check " + strconv.Itoa(i+1) + ": " + msg
+}
@@ -0,0 +1,64 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/docker/distribution/health"
+)
+
+var (
+	pass = health.CheckFunc(func() health.Result {
+		return health.Result{Message: "passed"}
+	})
+	fail = health.CheckFunc(func() health.Result {
+		return failure("broken")
+	})
+)
+
+func TestAll(t *testing.T) {
+	if res := All(pass, pass).Check(); res.Error != nil {
+		t.Errorf("all passing checks were expected to pass, error:%v", res.Error)
+	}
+
+	res := All(pass, fail, fail).Check()
+	if res.Error == nil {
+		t.Errorf("a failing check was expected to fail All")
+	}
+	if expected := "2 of 3 checks failed: check 2: broken; check 3: broken"; res.Message != expected {
+		t.Errorf("unexpected message: %q != %q", res.Message, expected)
+	}
+}
+
+func TestAny(t *testing.T) {
+	if res := Any(fail, pass).Check(); res.Error != nil {
+		t.Errorf("a passing check was expected to pass Any, error:%v", res.Error)
+	}
+
+	res := Any(fail, fail).Check()
+	if res.Error == nil {
+		t.Errorf("all failing checks were expected to fail Any")
+	}
+	if expected := "all checks failed: check 1: broken; check 2: broken"; res.Message != expected {
+		t.Errorf("unexpected message: %q != %q", res.Message, expected)
+	}
+}
+
+func TestFirstSuccessful(t *testing.T) {
+	ran := false
+	never := health.CheckFunc(func() health.Result {
+		ran = true
+		return health.Result{}
+	})
+
+	res := FirstSuccessful(fail, pass, never).Check()
+	if res.Error != nil || res.Message != "passed" {
+		t.Errorf("unexpected result: %+v", res)
+	}
+	if ran {
+		t.Errorf("checks after the first successful one were not expected to run")
+	}
+
+	if res := FirstSuccessful(fail, fail).Check(); res.Error == nil {
+		t.Errorf("all failing checks were expected to fail FirstSuccessful")
+	}
+}
@@ -0,0 +1,57 @@
+package checks
+
+import (
+	"strconv"
+
+	"github.com/docker/distribution/health"
+)
+
+// diskStats describes the usage of a filesystem.
+type diskStats struct {
+	// blocksUsed and blocksAvail count blocks in use and available to
+	// unprivileged users.
+	blocksUsed, blocksAvail uint64
+	// inodes and inodesFree count the total and free inodes.
+	inodes, inodesFree uint64
+}
+
+// DiskUsage checks the space used on the filesystem backing path and returns
+// an error if more than maxUsedPercent of it is used.
+func DiskUsage(path string, maxUsedPercent float64) health.Checker {
+	return health.CheckFunc(func() health.Result {
+		st, err := statfs(path)
+		if err != nil {
+			return failure("error reading filesystem stats of " + path + ": " + err.Error())
+		}
+		return usageResult("disk", percent(st.blocksUsed, st.blocksUsed+st.blocksAvail), maxUsedPercent)
+	})
+}
+
+// InodeUsage checks the inodes used on the filesystem backing path and
+// returns an error if more than maxUsedPercent of them are used.
+func InodeUsage(path string, maxUsedPercent float64) health.Checker {
+	return health.CheckFunc(func() health.Result {
+		st, err := statfs(path)
+		if err != nil {
+			return failure("error reading filesystem stats of " + path + ": " + err.Error())
+		}
+		return usageResult("inode", percent(st.inodes-st.inodesFree, st.inodes), maxUsedPercent)
+	})
+}
+
+// percent returns used as a percentage of total.
+func percent(used, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(used) / float64(total) * 100
+}
+
+// usageResult compares the used percentage against the threshold.
+func usageResult(what string, used, max float64) health.Result {
+	msg := what + " usage at " + strconv.FormatFloat(used, 'f', 1, 64) + "%"
+	if used > max {
+		return failureCode(health.CodeThreshold, msg+", above "+strconv.FormatFloat(max, 'f', 1, 64)+"%")
+	}
+	return health.Result{Message: msg}
+}
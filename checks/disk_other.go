@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !freebsd
+
+package checks
+
+import "errors"
+
+// statfs is not supported on this platform.
+func statfs(path string) (diskStats, error) {
+	return diskStats{}, errors.New("disk usage checks are not supported on this platform")
+}
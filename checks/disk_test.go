@@ -0,0 +1,27 @@
+//go:build linux || darwin || freebsd
+
+package checks
+
+import (
+	"testing"
+)
+
+func TestDiskUsage(t *testing.T) {
+	if res := DiskUsage("/", 100).Check(); res.Error != nil {
+		t.Errorf("disk usage can not be above 100%%, error:%v", res.Error)
+	}
+
+	if res := DiskUsage("/", -1).Check(); res.Error == nil {
+		t.Errorf("disk usage was expected to be above -1%%")
+	}
+
+	if res := DiskUsage("NoSuchFileFromMoon", 100).Check(); res.Error == nil {
+		t.Errorf("disk usage of a missing path was expected to fail")
+	}
+}
+
+func TestInodeUsage(t *testing.T) {
+	if res := InodeUsage("/", 100).Check(); res.Error != nil {
+		t.Errorf("inode usage can not be above 100%%, error:%v", res.Error)
+	}
+}
@@ -0,0 +1,19 @@
+//go:build linux || darwin || freebsd
+
+package checks
+
+import "syscall"
+
+// statfs returns the usage of the filesystem backing path.
+func statfs(path string) (diskStats, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return diskStats{}, err
+	}
+	return diskStats{
+		blocksUsed:  uint64(st.Blocks) - uint64(st.Bfree),
+		blocksAvail: uint64(st.Bavail),
+		inodes:      uint64(st.Files),
+		inodesFree:  uint64(st.Ffree),
+	}, nil
+}
@@ -0,0 +1,34 @@
+package checks
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/docker/distribution/health"
+)
+
+// DNSResolve checks that hostname resolves to at least one address using the
+// default resolver.
+func DNSResolve(hostname string, timeout time.Duration) health.Checker {
+	return DNSResolveWithResolver(net.DefaultResolver, hostname, timeout)
+}
+
+// DNSResolveWithResolver checks that hostname resolves to at least one
+// address using resolver.
+func DNSResolveWithResolver(resolver *net.Resolver, hostname string, timeout time.Duration) health.Checker {
+	return health.CheckFunc(func() health.Result {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		addrs, err := resolver.LookupHost(ctx, hostname)
+		if err != nil {
+			return failureErr("resolving "+hostname+" failed: "+err.Error(), err)
+		}
+		if len(addrs) == 0 {
+			return failureCode(health.CodeDNS, "resolving "+hostname+" returned no addresses")
+		}
+		return health.Result{Message: hostname + " resolved to " + strconv.Itoa(len(addrs)) + " addresses"}
+	})
+}
@@ -0,0 +1,29 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestDNSResolve(t *testing.T) {
+	if res := DNSResolve("localhost", time.Second).Check(); res.Error != nil {
+		t.Errorf("localhost was expected to resolve, error:%v", res.Error)
+	}
+
+	if res := DNSResolve("does.not.exist.invalid", time.Second).Check(); res.Error == nil {
+		t.Errorf("does.not.exist.invalid was expected not to resolve")
+	}
+
+	broken := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			return nil, errors.New("dns server unreachable")
+		},
+	}
+	if res := DNSResolveWithResolver(broken, "www.example.com", time.Second).Check(); res.Error == nil {
+		t.Errorf("resolving with a broken resolver was expected to fail")
+	}
+}
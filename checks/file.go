@@ -0,0 +1,36 @@
+package checks
+
+import (
+	"os"
+	"time"
+
+	"github.com/docker/distribution/health"
+)
+
+// FileExists checks that the file at path exists. Unlike FileChecker, a
+// missing file is unhealthy.
+func FileExists(path string) health.Checker {
+	return health.CheckFunc(func() health.Result {
+		if _, err := os.Stat(path); err != nil {
+			return failure("file check failed: " + err.Error())
+		}
+		return health.Result{}
+	})
+}
+
+// FileTouchedWithin checks that the file at path was modified within maxAge,
+// e.g. a heartbeat file written by a sidecar or a marker touched by a cron
+// job. The Result message contains the age of the file.
+func FileTouchedWithin(path string, maxAge time.Duration) health.Checker {
+	return health.CheckFunc(func() health.Result {
+		info, err := os.Stat(path)
+		if err != nil {
+			return failure("file check failed: " + err.Error())
+		}
+		age := time.Since(info.ModTime()).Truncate(time.Millisecond)
+		if age > maxAge {
+			return failureCode(health.CodeThreshold, path+" last modified "+age.String()+" ago, more than "+maxAge.String())
+		}
+		return health.Result{Message: "last modified " + age.String() + " ago"}
+	})
+}
@@ -0,0 +1,38 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileCheckers(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "heartbeat")
+	missing := filepath.Join(dir, "missing")
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if res := FileExists(path).Check(); res.Error != nil {
+		t.Errorf("unexpected result for existing file: %+v", res)
+	}
+	if res := FileExists(missing).Check(); res.Error == nil {
+		t.Errorf("missing file was expected to fail")
+	}
+
+	if res := FileTouchedWithin(path, time.Minute).Check(); res.Error != nil {
+		t.Errorf("unexpected result for fresh file: %+v", res)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if res := FileTouchedWithin(path, time.Minute).Check(); res.Error == nil {
+		t.Errorf("stale file was expected to fail")
+	}
+	if res := FileTouchedWithin(missing, time.Minute).Check(); res.Error == nil {
+		t.Errorf("missing file was expected to fail")
+	}
+}
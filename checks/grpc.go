@@ -0,0 +1,51 @@
+package checks
+
+import (
+	"context"
+	"time"
+
+	"github.com/docker/distribution/health"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCHealth checks a gRPC backend with the standard health service
+// (grpc.health.v1.Health), asking for the status of service, or of the whole
+// server if service is empty. The check fails unless the backend reports
+// SERVING within timeout. A timeout of zero or less only observes the
+// context of the check. conn is usually a *grpc.ClientConn.
+func GRPCHealth(conn grpc.ClientConnInterface, service string, timeout time.Duration) health.CheckerWithContext {
+	client := healthpb.NewHealthClient(conn)
+	return health.CheckFuncWithContext(func(ctx context.Context) health.Result {
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			return failureCode(grpcCode(err), "health check failed: "+err.Error())
+		}
+		if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			return failureCode(health.CodeUnavailable, "service is "+resp.GetStatus().String())
+		}
+		return health.Result{}
+	})
+}
+
+// grpcCode classifies the error of a gRPC call by its status code.
+func grpcCode(err error) health.Code {
+	switch status.Code(err) {
+	case codes.DeadlineExceeded:
+		return health.CodeTimeout
+	case codes.Canceled:
+		return health.CodeCancelled
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return health.CodeAuth
+	case codes.Unavailable:
+		return health.CodeUnavailable
+	}
+	return health.CodeOf(err)
+}
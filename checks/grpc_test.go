@@ -0,0 +1,46 @@
+package checks
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestGRPCHealth(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error listening: %v", err)
+	}
+	srv := grpc.NewServer()
+	status := grpchealth.NewServer()
+	healthpb.RegisterHealthServer(srv, status)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("error dialing: %v", err)
+	}
+	defer conn.Close()
+
+	status.SetServingStatus("storage", healthpb.HealthCheckResponse_SERVING)
+	if res := GRPCHealth(conn, "storage", time.Second).Check(context.Background()); res.Error != nil {
+		t.Errorf("expected serving service to be healthy, got %+v", res)
+	}
+
+	status.SetServingStatus("storage", healthpb.HealthCheckResponse_NOT_SERVING)
+	if res := GRPCHealth(conn, "storage", time.Second).Check(context.Background()); res.Error == nil || res.Message != "service is NOT_SERVING" {
+		t.Errorf("expected not serving service to fail, got %+v", res)
+	}
+
+	if res := GRPCHealth(conn, "unknown", time.Second).Check(context.Background()); res.Error == nil || !strings.Contains(res.Message, "NotFound") {
+		t.Errorf("expected unknown service to fail, got %+v", res)
+	}
+}
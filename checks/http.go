@@ -0,0 +1,147 @@
+package checks
+
+import (
+	"bytes"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/docker/distribution/health"
+)
+
+// maxBodySize limits how much of a response body HTTPGetCheck reads when
+// matching on the body.
+const maxBodySize = 1 << 20
+
+// httpCheck holds the configuration of a checker created by HTTPGetCheck.
+type httpCheck struct {
+	url         string
+	statusCodes []int
+	contains    []byte
+	match       *regexp.Regexp
+	header      http.Header
+	tlsConfig   *tls.Config
+	timeout     time.Duration
+}
+
+// HTTPCheckOption configures a checker created by HTTPGetCheck.
+type HTTPCheckOption func(*httpCheck)
+
+// WithStatusCodes sets the status codes considered healthy. By default any
+// 2xx status code is.
+func WithStatusCodes(codes ...int) HTTPCheckOption {
+	return func(c *httpCheck) {
+		c.statusCodes = codes
+	}
+}
+
+// WithBodyContains requires the response body to contain substr.
+func WithBodyContains(substr string) HTTPCheckOption {
+	return func(c *httpCheck) {
+		c.contains = []byte(substr)
+	}
+}
+
+// WithBodyMatch requires the response body to match re.
+func WithBodyMatch(re *regexp.Regexp) HTTPCheckOption {
+	return func(c *httpCheck) {
+		c.match = re
+	}
+}
+
+// WithHeader adds a header to the request.
+func WithHeader(key, value string) HTTPCheckOption {
+	return func(c *httpCheck) {
+		c.header.Add(key, value)
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used for https URLs.
+func WithTLSConfig(config *tls.Config) HTTPCheckOption {
+	return func(c *httpCheck) {
+		c.tlsConfig = config
+	}
+}
+
+// WithTimeout limits the time the whole request, including reading the body,
+// may take. A zero timeout means no timeout.
+func WithTimeout(timeout time.Duration) HTTPCheckOption {
+	return func(c *httpCheck) {
+		c.timeout = timeout
+	}
+}
+
+// HTTPGetCheck does a GET request to url and verifies the response according
+// to the options.
+func HTTPGetCheck(url string, opts ...HTTPCheckOption) health.Checker {
+	c := &httpCheck{
+		url:    url,
+		header: make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	client := &http.Client{
+		Timeout: c.timeout,
+	}
+	if c.tlsConfig != nil {
+		client.Transport = &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: c.tlsConfig,
+		}
+	}
+
+	return health.CheckFunc(func() health.Result {
+		req, err := http.NewRequest("GET", c.url, nil)
+		if err != nil {
+			return failure("error creating request: " + c.url)
+		}
+		for headerName, headerValues := range c.header {
+			for _, headerValue := range headerValues {
+				req.Header.Add(headerName, headerValue)
+			}
+		}
+
+		response, err := client.Do(req)
+		if err != nil {
+			return failureErr("error while checking: "+c.url, err)
+		}
+		defer response.Body.Close()
+
+		if !c.expectedStatus(response.StatusCode) {
+			return statusFailure(response.StatusCode)
+		}
+
+		if c.contains == nil && c.match == nil {
+			return health.Result{}
+		}
+		body, err := io.ReadAll(io.LimitReader(response.Body, maxBodySize))
+		if err != nil {
+			return failure("error reading response body: " + c.url)
+		}
+		if c.contains != nil && !bytes.Contains(body, c.contains) {
+			return failure("response body does not contain " + strconv.Quote(string(c.contains)))
+		}
+		if c.match != nil && !c.match.Match(body) {
+			return failure("response body does not match " + c.match.String())
+		}
+		return health.Result{}
+	})
+}
+
+// expectedStatus reports whether code is a healthy status code.
+func (c *httpCheck) expectedStatus(code int) bool {
+	if len(c.statusCodes) == 0 {
+		return code >= 200 && code < 300
+	}
+	for _, expected := range c.statusCodes {
+		if code == expected {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package checks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/docker/distribution/health"
+)
+
+func TestHTTPGetCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"status":"ok","version":"1.2.3"}`))
+	}))
+	defer server.Close()
+
+	auth := WithHeader("Authorization", "Bearer token")
+
+	for _, tc := range []struct {
+		name    string
+		opts    []HTTPCheckOption
+		healthy bool
+		code    health.Code
+	}{
+		{"no header", nil, false, health.CodeAuth},
+		{"unauthorized expected", []HTTPCheckOption{WithStatusCodes(http.StatusUnauthorized)}, true, ""},
+		{"header", []HTTPCheckOption{auth}, true, ""},
+		{"contains", []HTTPCheckOption{auth, WithBodyContains(`"status":"ok"`)}, true, ""},
+		{"does not contain", []HTTPCheckOption{auth, WithBodyContains(`"status":"fail"`)}, false, ""},
+		{"matches", []HTTPCheckOption{auth, WithBodyMatch(regexp.MustCompile(`"version":"1\.\d+`))}, true, ""},
+		{"does not match", []HTTPCheckOption{auth, WithBodyMatch(regexp.MustCompile(`"version":"2\.`))}, false, ""},
+		{"timeout", []HTTPCheckOption{auth, WithTimeout(time.Second)}, true, ""},
+	} {
+		res := HTTPGetCheck(server.URL, tc.opts...).Check()
+		if healthy := res.Error == nil; healthy != tc.healthy {
+			t.Errorf("%s: expected healthy to be %v, got result %+v", tc.name, tc.healthy, res)
+		}
+		if res.Code != tc.code {
+			t.Errorf("%s: expected code %q, got %q", tc.name, tc.code, res.Code)
+		}
+	}
+
+	addr := server.Listener.Addr().String()
+	server.Close()
+	if res := HTTPGetCheck("http://" + addr).Check(); res.Code != health.CodeConnRefused {
+		t.Errorf("expected code %q for a closed server, got %+v", health.CodeConnRefused, res)
+	}
+}
@@ -0,0 +1,107 @@
+// Package kafkacheck checks the connectivity to a Kafka cluster and,
+// optionally, that consumer groups keep up with the topics they consume. A
+// consumer that is connected but hopelessly behind is effectively unhealthy.
+//
+// The checker talks to Kafka through the small Client interface, so the
+// package does not force a client library on its users. Implementing it on
+// top of e.g. franz-go's kadm.Client or sarama's ClusterAdmin takes a few
+// lines.
+//
+//	health.RegisterWithContext("kafka", kafkacheck.New(client,
+//	  kafkacheck.WithConsumerLag("billing", 10000, "invoices", "payments")))
+package kafkacheck
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/distribution/health"
+)
+
+// Client is the subset of a Kafka client the checker needs.
+type Client interface {
+	// Ping verifies the brokers can be reached, e.g. by requesting the
+	// cluster metadata.
+	Ping(ctx context.Context) error
+	// Lag returns the lag of the consumer group on each of the topics,
+	// summed over their partitions.
+	Lag(ctx context.Context, group string, topics ...string) (map[string]int64, error)
+}
+
+// lagLimit is the maximum lag of a consumer group on its topics.
+type lagLimit struct {
+	group  string
+	maxLag int64
+	topics []string
+}
+
+// checker checks a Kafka cluster through a Client.
+type checker struct {
+	client Client
+	limits []lagLimit
+}
+
+// Option configures a checker created by New.
+type Option func(*checker)
+
+// WithConsumerLag makes the check fail if the lag of the consumer group on
+// any of the topics exceeds maxLag messages.
+func WithConsumerLag(group string, maxLag int64, topics ...string) Option {
+	return func(c *checker) {
+		c.limits = append(c.limits, lagLimit{group: group, maxLag: maxLag, topics: topics})
+	}
+}
+
+// New returns a checker that pings the brokers through client and checks the
+// consumer lag limits set with WithConsumerLag. The lag of every topic is
+// reported in the Result details, keyed by "group/topic".
+func New(client Client, opts ...Option) health.CheckerWithContext {
+	c := &checker{client: client}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Check implements the CheckerWithContext interface
+func (c *checker) Check(ctx context.Context) health.Result {
+	if err := c.client.Ping(ctx); err != nil {
+		return failure("kafka brokers unreachable: " + err.Error())
+	}
+	if len(c.limits) == 0 {
+		return health.Result{}
+	}
+
+	details := make(map[string]any)
+	var behind []string
+	for _, limit := range c.limits {
+		lags, err := c.client.Lag(ctx, limit.group, limit.topics...)
+		if err != nil {
+			return failure("error fetching lag of consumer group " + limit.group + ": " + err.Error())
+		}
+		for topic, lag := range lags {
+			key := limit.group + "/" + topic
+			details[key] = lag
+			if lag > limit.maxLag {
+				behind = append(behind, key+" lag "+strconv.FormatInt(lag, 10)+" exceeds "+strconv.FormatInt(limit.maxLag, 10))
+			}
+		}
+	}
+
+	if len(behind) > 0 {
+		sort.Strings(behind)
+		res := failure("consumers behind: " + strings.Join(behind, "; "))
+		res.Details = details
+		return res
+	}
+	return health.Result{Details: details}
+}
+
+// failure returns an unhealthy Result carrying msg as both the error and the
+// message.
+func failure(msg string) health.Result {
+	return health.Result{Error: errors.New(msg), Message: msg}
+}
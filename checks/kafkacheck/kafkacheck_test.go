@@ -0,0 +1,58 @@
+package kafkacheck
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// fakeClient is a Client with fixed responses.
+type fakeClient struct {
+	pingErr error
+	lags    map[string]map[string]int64
+}
+
+func (c fakeClient) Ping(ctx context.Context) error {
+	return c.pingErr
+}
+
+func (c fakeClient) Lag(ctx context.Context, group string, topics ...string) (map[string]int64, error) {
+	lags, ok := c.lags[group]
+	if !ok {
+		return nil, errors.New("unknown group")
+	}
+	res := make(map[string]int64)
+	for _, topic := range topics {
+		res[topic] = lags[topic]
+	}
+	return res, nil
+}
+
+func TestChecker(t *testing.T) {
+	client := fakeClient{lags: map[string]map[string]int64{
+		"billing": {"invoices": 10, "payments": 5000},
+	}}
+
+	for _, tc := range []struct {
+		name    string
+		client  Client
+		opts    []Option
+		healthy bool
+	}{
+		{"connected", client, nil, true},
+		{"unreachable", fakeClient{pingErr: errors.New("connection refused")}, nil, false},
+		{"within lag", client, []Option{WithConsumerLag("billing", 100, "invoices")}, true},
+		{"behind", client, []Option{WithConsumerLag("billing", 100, "invoices", "payments")}, false},
+		{"unknown group", client, []Option{WithConsumerLag("shipping", 100, "orders")}, false},
+	} {
+		res := New(tc.client, tc.opts...).Check(context.Background())
+		if healthy := res.Error == nil; healthy != tc.healthy {
+			t.Errorf("%s: expected healthy to be %v, got result %+v", tc.name, tc.healthy, res)
+		}
+	}
+
+	res := New(client, WithConsumerLag("billing", 100, "invoices", "payments")).Check(context.Background())
+	if res.Message != "consumers behind: billing/payments lag 5000 exceeds 100" || res.Details["billing/invoices"] != int64(10) {
+		t.Errorf("unexpected result: %+v", res)
+	}
+}
@@ -0,0 +1,21 @@
+package checks
+
+import (
+	"context"
+	"time"
+
+	"github.com/docker/distribution/health"
+)
+
+// Pinger checks a client exposing a Ping method, like a MongoDB client, an
+// Elasticsearch client or a pgx pool, by pinging it with the context of the
+// check. The Result message contains the latency of the ping.
+func Pinger(p interface{ Ping(context.Context) error }) health.CheckerWithContext {
+	return health.CheckFuncWithContext(func(ctx context.Context) health.Result {
+		start := time.Now()
+		if err := p.Ping(ctx); err != nil {
+			return failureErr("ping failed: "+err.Error(), err)
+		}
+		return health.Result{Message: "ping took " + time.Since(start).String()}
+	})
+}
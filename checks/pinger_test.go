@@ -0,0 +1,33 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// pingFunc implements the Ping method with a function.
+type pingFunc func(context.Context) error
+
+func (f pingFunc) Ping(ctx context.Context) error {
+	return f(ctx)
+}
+
+func TestPinger(t *testing.T) {
+	up := pingFunc(func(ctx context.Context) error { return nil })
+	down := pingFunc(func(ctx context.Context) error { return errors.New("server selection timeout") })
+
+	if res := Pinger(up).Check(context.Background()); res.Error != nil || !strings.HasPrefix(res.Message, "ping took ") {
+		t.Errorf("unexpected ping result: %+v", res)
+	}
+	if res := Pinger(down).Check(context.Background()); res.Error == nil || res.Message != "ping failed: server selection timeout" {
+		t.Errorf("unexpected ping result: %+v", res)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if res := Pinger(pingFunc(func(ctx context.Context) error { return ctx.Err() })).Check(ctx); res.Error == nil {
+		t.Errorf("expected the context of the check to be passed to Ping")
+	}
+}
@@ -0,0 +1,35 @@
+package checks
+
+import (
+	"runtime"
+	"strconv"
+
+	"github.com/docker/distribution/health"
+)
+
+// GoroutineCount returns an error if more than maxGoroutines goroutines
+// exist.
+func GoroutineCount(maxGoroutines int) health.Checker {
+	return health.CheckFunc(func() health.Result {
+		n := runtime.NumGoroutine()
+		msg := strconv.Itoa(n) + " goroutines"
+		if n > maxGoroutines {
+			return failureCode(health.CodeThreshold, msg+", above "+strconv.Itoa(maxGoroutines))
+		}
+		return health.Result{Message: msg}
+	})
+}
+
+// HeapAlloc returns an error if more than maxBytes of heap objects are
+// allocated. Note that reading the memory statistics briefly stops the world.
+func HeapAlloc(maxBytes uint64) health.Checker {
+	return health.CheckFunc(func() health.Result {
+		var m runtime.MemStats
+		runtime.ReadMemStats(&m)
+		msg := strconv.FormatUint(m.HeapAlloc, 10) + " bytes allocated on the heap"
+		if m.HeapAlloc > maxBytes {
+			return failureCode(health.CodeThreshold, msg+", above "+strconv.FormatUint(maxBytes, 10))
+		}
+		return health.Result{Message: msg}
+	})
+}
@@ -0,0 +1,26 @@
+package checks
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGoroutineCount(t *testing.T) {
+	if res := GoroutineCount(math.MaxInt32).Check(); res.Error != nil {
+		t.Errorf("goroutine count was expected to be healthy, error:%v", res.Error)
+	}
+
+	if res := GoroutineCount(0).Check(); res.Error == nil {
+		t.Errorf("goroutine count was expected to be above 0")
+	}
+}
+
+func TestHeapAlloc(t *testing.T) {
+	if res := HeapAlloc(math.MaxUint64).Check(); res.Error != nil {
+		t.Errorf("heap allocation was expected to be healthy, error:%v", res.Error)
+	}
+
+	if res := HeapAlloc(0).Check(); res.Error == nil {
+		t.Errorf("heap allocation was expected to be above 0")
+	}
+}
@@ -0,0 +1,50 @@
+package checks
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/docker/distribution/health"
+)
+
+// DatabasePing checks the connectivity to a database by pinging it with the
+// context of the check, limited to timeout. The Result message contains the
+// latency of the ping.
+func DatabasePing(db *sql.DB, timeout time.Duration) health.CheckerWithContext {
+	return health.CheckFuncWithContext(func(ctx context.Context) health.Result {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		start := time.Now()
+		if err := db.PingContext(ctx); err != nil {
+			return failureErr("database ping failed: "+err.Error(), err)
+		}
+		return health.Result{Message: "ping took " + time.Since(start).String()}
+	})
+}
+
+// DatabaseQuery checks a database by running a validation query such as
+// "SELECT 1" with the context of the check, limited to timeout. The Result
+// message contains the latency of the query.
+func DatabaseQuery(db *sql.DB, query string, timeout time.Duration) health.CheckerWithContext {
+	return health.CheckFuncWithContext(func(ctx context.Context) health.Result {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		start := time.Now()
+		rows, err := db.QueryContext(ctx, query)
+		if err != nil {
+			return failureErr("database query failed: "+err.Error(), err)
+		}
+		for rows.Next() {
+		}
+		if err := rows.Close(); err != nil {
+			return failureErr("database query failed: "+err.Error(), err)
+		}
+		if err := rows.Err(); err != nil {
+			return failureErr("database query failed: "+err.Error(), err)
+		}
+		return health.Result{Message: "query took " + time.Since(start).String()}
+	})
+}
@@ -0,0 +1,98 @@
+package checks
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/distribution/health"
+)
+
+// fakeDriver is a database/sql driver whose connections fail to ping and
+// query when the DSN is "down".
+type fakeDriver struct{}
+
+func (fakeDriver) Open(dsn string) (driver.Conn, error) {
+	return fakeConn{down: dsn == "down"}, nil
+}
+
+type fakeConn struct {
+	down bool
+}
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c fakeConn) Close() error {
+	return nil
+}
+
+func (c fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c fakeConn) Ping(ctx context.Context) error {
+	if c.down {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (c fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if c.down {
+		return nil, errors.New("connection refused")
+	}
+	return fakeRows{}, nil
+}
+
+type fakeRows struct{}
+
+func (fakeRows) Columns() []string              { return []string{"1"} }
+func (fakeRows) Close() error                   { return nil }
+func (fakeRows) Next(dest []driver.Value) error { return io.EOF }
+
+func init() {
+	sql.Register("fake", fakeDriver{})
+}
+
+func TestDatabaseCheckers(t *testing.T) {
+	up, err := sql.Open("fake", "up")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer up.Close()
+	down, err := sql.Open("fake", "down")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer down.Close()
+
+	if res := DatabasePing(up, time.Second).Check(context.Background()); res.Error != nil || !strings.HasPrefix(res.Message, "ping took ") {
+		t.Errorf("unexpected ping result: %+v", res)
+	}
+	if res := DatabasePing(down, time.Second).Check(context.Background()); res.Error == nil {
+		t.Errorf("ping of a down database was expected to fail")
+	}
+
+	if res := DatabaseQuery(up, "SELECT 1", time.Second).Check(context.Background()); res.Error != nil || !strings.HasPrefix(res.Message, "query took ") {
+		t.Errorf("unexpected query result: %+v", res)
+	}
+	if res := DatabaseQuery(down, "SELECT 1", time.Second).Check(context.Background()); res.Error == nil {
+		t.Errorf("query of a down database was expected to fail")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if res := DatabasePing(up, time.Second).Check(ctx); res.Code != health.CodeCancelled {
+		t.Errorf("ping with a cancelled context was expected to fail, got %+v", res)
+	}
+	if res := DatabaseQuery(up, "SELECT 1", time.Second).Check(ctx); res.Code != health.CodeCancelled {
+		t.Errorf("query with a cancelled context was expected to fail, got %+v", res)
+	}
+}
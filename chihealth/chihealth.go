@@ -0,0 +1,47 @@
+// Package chihealth mounts the status handlers of a health.Registry on chi
+// routers.
+//
+//	r := chi.NewRouter()
+//	r.Mount("/", chihealth.Router(registry))
+package chihealth
+
+import (
+	"net/http"
+
+	"github.com/docker/distribution/health"
+	"github.com/go-chi/chi/v5"
+)
+
+// Status returns a handler serving the status of all checks in the registry,
+// see Registry.Handler.
+func Status(registry *health.Registry, opts ...health.HandlerOption) http.Handler {
+	return registry.Handler(opts...)
+}
+
+// Liveness returns a handler serving the status of the Liveness checks in
+// the registry.
+func Liveness(registry *health.Registry, opts ...health.HandlerOption) http.Handler {
+	return Status(registry, append(opts, health.ForKind(health.Liveness))...)
+}
+
+// Readiness returns a handler serving the status of the Readiness checks in
+// the registry.
+func Readiness(registry *health.Registry, opts ...health.HandlerOption) http.Handler {
+	return Status(registry, append(opts, health.ForKind(health.Readiness))...)
+}
+
+// RegisterRoutes mounts the status handlers of the registry on router at
+// health.HealthzPath, health.ReadyzPath and health.LivezPath.
+func RegisterRoutes(router chi.Router, registry *health.Registry, opts ...health.HandlerOption) {
+	router.Method(http.MethodGet, health.HealthzPath, Status(registry, opts...))
+	router.Method(http.MethodGet, health.ReadyzPath, Readiness(registry, opts...))
+	router.Method(http.MethodGet, health.LivezPath, Liveness(registry, opts...))
+}
+
+// Router returns a router serving the status handlers of the registry, see
+// RegisterRoutes, to be mounted on another router.
+func Router(registry *health.Registry, opts ...health.HandlerOption) chi.Router {
+	router := chi.NewRouter()
+	RegisterRoutes(router, registry, opts...)
+	return router
+}
@@ -0,0 +1,40 @@
+package chihealth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/docker/distribution/health"
+	"github.com/go-chi/chi/v5"
+)
+
+func TestRegisterRoutes(t *testing.T) {
+	registry := health.NewRegistry()
+	registry.Register("process", health.CheckFunc(func() health.Result {
+		return health.Result{}
+	}), health.WithKind(health.Liveness))
+	registry.Register("database", health.CheckFunc(func() health.Result {
+		return health.Result{Error: errors.New("connection refused")}
+	}))
+
+	router := chi.NewRouter()
+	router.Mount("/", Router(registry))
+
+	for path, expected := range map[string]int{
+		health.HealthzPath: http.StatusServiceUnavailable,
+		health.ReadyzPath:  http.StatusServiceUnavailable,
+		health.LivezPath:   http.StatusOK,
+	} {
+		req, err := http.NewRequest("GET", "https://fakeurl.com"+path, nil)
+		if err != nil {
+			t.Fatalf("Failed to create request.")
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		if recorder.Code != expected {
+			t.Errorf("%s: expected %d, got %d", path, expected, recorder.Code)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package health
+
+import "time"
+
+// Clock tells the time and creates timers. It can be replaced with WithClock
+// and WithPeriodicClock to control time in tests, without sleeping.
+type Clock interface {
+	Now() time.Time
+	// NewTimer returns a Timer that fires once d has passed.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a timer created by a Clock, like a time.Timer.
+type Timer interface {
+	// C returns the channel the time is sent on when the timer fires.
+	C() <-chan time.Time
+	// Stop prevents the timer from firing. It returns false if the timer
+	// already fired or was stopped.
+	Stop() bool
+	// Reset makes the timer fire once d has passed. It returns false if the
+	// timer already fired or was stopped.
+	Reset(d time.Duration) bool
+}
+
+// realClock is the Clock of the time package.
+type realClock struct{}
+
+// Now implements the Clock interface
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// NewTimer implements the Clock interface
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+// realTimer adapts a time.Timer to the Timer interface.
+type realTimer struct {
+	*time.Timer
+}
+
+// C implements the Timer interface
+func (t realTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
@@ -0,0 +1,120 @@
+package health
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock that only moves when told to.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{clock: c, c: make(chan time.Time, 1), deadline: c.now.Add(d), active: true}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+// Add moves the clock forward, firing the timers that are due.
+func (c *fakeClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	for _, t := range c.timers {
+		if t.active && !t.deadline.After(c.now) {
+			t.active = false
+			t.c <- c.now
+		}
+	}
+}
+
+// waitForTimers waits until n timers are active.
+func (c *fakeClock) waitForTimers(t *testing.T, n int) {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		c.mu.Lock()
+		active := 0
+		for _, timer := range c.timers {
+			if timer.active {
+				active++
+			}
+		}
+		c.mu.Unlock()
+		if active >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d timers", n)
+}
+
+type fakeTimer struct {
+	clock    *fakeClock
+	c        chan time.Time
+	deadline time.Time
+	active   bool
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	active := t.active
+	t.active = false
+	return active
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	active := t.active
+	t.active = true
+	t.deadline = t.clock.now.Add(d)
+	return active
+}
+
+// TestPeriodicCheckerClock ensures periodic checkers are timed by their
+// clock.
+func TestPeriodicCheckerClock(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)}
+	registry := NewRegistry(WithClock(clock))
+	runs := make(chan struct{}, 1)
+	registry.RegisterPeriodicFunc("check", time.Minute, func() Result {
+		runs <- struct{}{}
+		return Result{}
+	})
+	defer registry.Close()
+
+	clock.waitForTimers(t, 1)
+	select {
+	case <-runs:
+		t.Fatalf("check ran before its period passed")
+	default:
+	}
+
+	clock.Add(time.Minute)
+	select {
+	case <-runs:
+	case <-time.After(time.Second):
+		t.Fatalf("check did not run after its period passed")
+	}
+	clock.waitForTimers(t, 1)
+	if checked := registry.CheckStatus()["check"].LastChecked; !checked.Equal(clock.Now()) {
+		t.Errorf("expected the run to be timestamped by the clock, got %v", checked)
+	}
+}
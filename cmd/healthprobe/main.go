@@ -0,0 +1,142 @@
+// Command healthprobe queries a health status endpoint, prints a summary of
+// the checks and exits with status 1 if the service is unhealthy, or 2 if the
+// endpoint can not be queried. It is meant for Docker HEALTHCHECK commands
+// and smoke tests:
+//
+//	HEALTHCHECK CMD ["healthprobe", "-timeout", "3s", "http://localhost:8080/debug/health"]
+//
+// With -nagios it behaves as a Nagios plugin instead, printing the status
+// with perfdata and exiting with the OK, WARNING, CRITICAL or UNKNOWN exit
+// codes, see health.Status.Nagios.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/docker/distribution/health"
+)
+
+// Exit codes of the probe.
+const (
+	exitHealthy   = 0
+	exitUnhealthy = 1
+	exitError     = 2
+)
+
+// maxBodySize limits how much of the response is read.
+const maxBodySize = 1 << 20
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run probes the endpoint given in args and returns the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("healthprobe", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	timeout := flags.Duration("timeout", 5*time.Second, "timeout of the request")
+	quiet := flags.Bool("quiet", false, "only print failing checks")
+	nagios := flags.Bool("nagios", false, "follow the Nagios plugin conventions")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: healthprobe [flags] url")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitError
+	}
+	url := flags.Arg(0)
+
+	code, overall, checks, err := probe(url, *timeout)
+	if *nagios {
+		if err != nil {
+			fmt.Fprintf(stdout, "HEALTH UNKNOWN - %v\n", err)
+			return health.NagiosUnknown
+		}
+		output, exit := checks.Nagios()
+		fmt.Fprintln(stdout, output)
+		return exit
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "healthprobe: %v\n", err)
+		return exitError
+	}
+
+	healthy := code >= 200 && code < 300 && overall != health.StatusFail
+	if !*quiet || !healthy {
+		fmt.Fprintf(stdout, "%s: %s (HTTP %d)\n", url, overall, code)
+	}
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		check := checks[name]
+		status := check.Status()
+		if *quiet && status == health.StatusPass {
+			continue
+		}
+		mark := "ok  "
+		switch status {
+		case health.StatusWarn:
+			mark = "WARN"
+		case health.StatusFail:
+			mark = "FAIL"
+		}
+		line := fmt.Sprintf("  %s %s", mark, name)
+		if check.Message != "" {
+			line += ": " + check.Message
+		}
+		fmt.Fprintln(stdout, line)
+	}
+
+	if !healthy {
+		return exitUnhealthy
+	}
+	return exitHealthy
+}
+
+// probe requests the status at url. It returns the status code, the
+// aggregate status and the checks of the response, which may be a bare
+// health.Status or a health.StatusEnvelope.
+func probe(url string, timeout time.Duration) (int, string, health.Status, error) {
+	client := &http.Client{Timeout: timeout}
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return 0, "", nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, "", nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return 0, "", nil, err
+	}
+
+	var envelope health.StatusEnvelope
+	if err := json.Unmarshal(body, &envelope); err == nil && envelope.Status != "" {
+		return resp.StatusCode, envelope.Status, envelope.Checks, nil
+	}
+	var checks health.Status
+	if err := json.Unmarshal(body, &checks); err != nil {
+		return 0, "", nil, errors.New("unexpected response: HTTP " + resp.Status)
+	}
+
+	return resp.StatusCode, checks.Overall(), checks, nil
+}
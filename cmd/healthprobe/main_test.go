@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/docker/distribution/health"
+)
+
+func TestRun(t *testing.T) {
+	registry := health.NewRegistry()
+	db := health.NewStatusUpdater()
+	registry.Register("db", db)
+	registry.Register("cache", health.CheckFunc(func() health.Result {
+		return health.Result{Error: errors.New("timeout"), Message: "timeout"}
+	}), health.WithSeverity(health.Warning))
+	registry.Register("replica", health.CheckFunc(func() health.Result {
+		return health.Result{Degraded: true, Message: "lagging"}
+	}))
+
+	bare := httptest.NewServer(registry.Handler())
+	defer bare.Close()
+	envelope := httptest.NewServer(registry.Handler(health.WithEnvelope()))
+	defer envelope.Close()
+
+	for _, url := range []string{bare.URL, envelope.URL} {
+		db.Update(health.Result{})
+		var stdout, stderr bytes.Buffer
+		if code := run([]string{url}, &stdout, &stderr); code != exitHealthy {
+			t.Errorf("%s: expected exit code %d, got %d: %s%s", url, exitHealthy, code, stdout.String(), stderr.String())
+		}
+		if !strings.Contains(stdout.String(), "warn (HTTP 200)") || !strings.Contains(stdout.String(), "WARN cache: timeout") || !strings.Contains(stdout.String(), "WARN replica: lagging") {
+			t.Errorf("%s: unexpected summary %q", url, stdout.String())
+		}
+
+		db.Update(health.Result{Error: errors.New("connection refused"), Message: "connection refused"})
+		stdout.Reset()
+		if code := run([]string{"-quiet", url}, &stdout, &stderr); code != exitUnhealthy {
+			t.Errorf("%s: expected exit code %d, got %d", url, exitUnhealthy, code)
+		}
+		if !strings.Contains(stdout.String(), "FAIL db: connection refused") {
+			t.Errorf("%s: unexpected summary %q", url, stdout.String())
+		}
+	}
+
+	notJSON := httptest.NewServer(http.NotFoundHandler())
+	defer notJSON.Close()
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{notJSON.URL}, &stdout, &stderr); code != exitError {
+		t.Errorf("expected exit code %d for invalid response, got %d", exitError, code)
+	}
+	if code := run(nil, &stdout, &stderr); code != exitError {
+		t.Errorf("expected exit code %d without url, got %d", exitError, code)
+	}
+}
+
+func TestRunNagios(t *testing.T) {
+	registry := health.NewRegistry()
+	db := health.NewStatusUpdater()
+	registry.Register("db", db)
+	server := httptest.NewServer(registry.Handler())
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-nagios", server.URL}, &stdout, &stderr); code != health.NagiosOK {
+		t.Errorf("expected exit code %d, got %d: %s", health.NagiosOK, code, stdout.String())
+	}
+	if !strings.HasPrefix(stdout.String(), "HEALTH OK - 1 checks passing | 'db'=") {
+		t.Errorf("unexpected output %q", stdout.String())
+	}
+
+	db.Update(health.Result{Error: errors.New("connection refused"), Message: "connection refused"})
+	stdout.Reset()
+	if code := run([]string{"-nagios", server.URL}, &stdout, &stderr); code != health.NagiosCritical {
+		t.Errorf("expected exit code %d, got %d: %s", health.NagiosCritical, code, stdout.String())
+	}
+	if !strings.Contains(stdout.String(), "\nCRITICAL db: connection refused") {
+		t.Errorf("unexpected output %q", stdout.String())
+	}
+
+	notJSON := httptest.NewServer(http.NotFoundHandler())
+	defer notJSON.Close()
+	stdout.Reset()
+	if code := run([]string{"-nagios", notJSON.URL}, &stdout, &stderr); code != health.NagiosUnknown {
+		t.Errorf("expected exit code %d for invalid response, got %d", health.NagiosUnknown, code)
+	}
+	if !strings.HasPrefix(stdout.String(), "HEALTH UNKNOWN - ") {
+		t.Errorf("unexpected output %q", stdout.String())
+	}
+}
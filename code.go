@@ -0,0 +1,43 @@
+package health
+
+import "github.com/docker/distribution/health/healthapi"
+
+// Code is the machine readable reason of a failing or degraded result, see
+// healthapi.Code.
+type Code = healthapi.Code
+
+// Codes of failing and degraded results.
+const (
+	CodeTimeout     = healthapi.CodeTimeout
+	CodeCancelled   = healthapi.CodeCancelled
+	CodeConnRefused = healthapi.CodeConnRefused
+	CodeDNS         = healthapi.CodeDNS
+	CodeTLS         = healthapi.CodeTLS
+	CodeAuth        = healthapi.CodeAuth
+	CodeUnavailable = healthapi.CodeUnavailable
+	CodeThreshold   = healthapi.CodeThreshold
+	CodePanic       = healthapi.CodePanic
+	CodeSkipped     = healthapi.CodeSkipped
+	CodeDegraded    = healthapi.CodeDegraded
+	CodeUnknown     = healthapi.CodeUnknown
+)
+
+// CodeOf classifies err, see healthapi.CodeOf.
+func CodeOf(err error) Code {
+	return healthapi.CodeOf(err)
+}
+
+// classify fills in the Code of failing and degraded results that do not
+// have one.
+func classify(res Result) Result {
+	if res.Code != "" {
+		return res
+	}
+	switch res.State() {
+	case StateUnhealthy:
+		res.Code = CodeOf(res.Error)
+	case StateDegraded:
+		res.Code = CodeDegraded
+	}
+	return res
+}
@@ -0,0 +1,79 @@
+package health
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestCodeOf ensures errors are classified by their cause.
+func TestCodeOf(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		code Code
+	}{
+		{nil, ""},
+		{errors.New("boom"), CodeUnknown},
+		{context.DeadlineExceeded, CodeTimeout},
+		{fmt.Errorf("query: %w", context.Canceled), CodeCancelled},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, CodeConnRefused},
+		{&net.DNSError{Err: "no such host", Name: "db", IsNotFound: true}, CodeDNS},
+		{&net.DNSError{Err: "i/o timeout", Name: "db", IsTimeout: true}, CodeTimeout},
+		{fmt.Errorf("get: %w", x509.UnknownAuthorityError{}), CodeTLS},
+		{os.ErrDeadlineExceeded, CodeTimeout},
+	} {
+		if code := CodeOf(tc.err); code != tc.code {
+			t.Errorf("CodeOf(%v) = %q, expected %q", tc.err, code, tc.code)
+		}
+	}
+}
+
+// TestCode ensures the registry fills in the codes of failing and degraded
+// checks, keeping the ones set by checkers.
+func TestCode(t *testing.T) {
+	registry := NewRegistry(WithDefaultTimeout(10 * time.Millisecond))
+	registry.Register("custom", CheckFunc(func() Result {
+		return Result{Error: errors.New("rejected"), Code: CodeAuth}
+	}))
+	registry.Register("unknown", CheckFunc(func() Result {
+		return Result{Error: errors.New("boom")}
+	}))
+	registry.Register("degraded", CheckFunc(func() Result {
+		return Result{Degraded: true}
+	}))
+	registry.Register("healthy", CheckFunc(func() Result {
+		return Result{}
+	}))
+	registry.Register("panic", CheckFunc(func() Result {
+		panic("boom")
+	}))
+	registry.RegisterWithContext("slow", CheckFuncWithContext(func(ctx context.Context) Result {
+		<-ctx.Done()
+		time.Sleep(time.Millisecond)
+		return Result{}
+	}))
+	registry.Register("dependent", CheckFunc(func() Result {
+		return Result{}
+	}), WithDependencies("unknown"))
+
+	status := registry.CheckStatus()
+	for name, code := range map[string]Code{
+		"custom":    CodeAuth,
+		"unknown":   CodeUnknown,
+		"degraded":  CodeDegraded,
+		"healthy":   "",
+		"panic":     CodePanic,
+		"slow":      CodeTimeout,
+		"dependent": CodeSkipped,
+	} {
+		if status[name].Code != code {
+			t.Errorf("%s: expected code %q, got %q", name, code, status[name].Code)
+		}
+	}
+}
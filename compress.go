@@ -0,0 +1,170 @@
+package health
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DefaultCompressionMinSize is the size of the smallest response compressed
+// by handlers created with WithCompression and a minSize of zero. Smaller
+// responses are not worth the overhead.
+const DefaultCompressionMinSize = 1024
+
+// Encoder compresses responses with a content coding.
+type Encoder struct {
+	// Name is the content coding, as listed in Accept-Encoding headers.
+	Name string
+	// NewWriter returns a writer compressing to w. Closing it must flush
+	// the compressed data without closing w.
+	NewWriter func(w io.Writer) io.WriteCloser
+}
+
+// Gzip compresses responses with gzip.
+var Gzip = Encoder{
+	Name: "gzip",
+	NewWriter: func(w io.Writer) io.WriteCloser {
+		return gzip.NewWriter(w)
+	},
+}
+
+// WithCompression compresses responses of at least minSize bytes, or
+// DefaultCompressionMinSize if minSize is zero, for clients accepting it,
+// negotiated with the Accept-Encoding header. Responses are compressed with
+// Gzip, or with one of encoders, preferred in the order given. The package
+// does not depend on a brotli implementation, bring your own:
+//
+//	health.WithCompression(0, health.Encoder{
+//		Name: "br",
+//		NewWriter: func(w io.Writer) io.WriteCloser {
+//			return brotli.NewWriter(w)
+//		},
+//	})
+func WithCompression(minSize int, encoders ...Encoder) HandlerOption {
+	return func(c *handlerConfig) {
+		if minSize == 0 {
+			minSize = DefaultCompressionMinSize
+		}
+		c.compressMinSize = minSize
+		c.encoders = append(append([]Encoder(nil), encoders...), Gzip)
+	}
+}
+
+// acceptEncoding returns the encoder the request's Accept-Encoding header
+// prefers, or false if it does not accept any. Ties are broken by the order
+// of the encoders.
+func acceptEncoding(r *http.Request, encoders []Encoder) (Encoder, bool) {
+	header := strings.Join(r.Header.Values("Accept-Encoding"), ",")
+	if header == "" {
+		return Encoder{}, false
+	}
+
+	var (
+		best  Encoder
+		bestQ float64
+	)
+	for _, encoder := range encoders {
+		// an explicit entry for the coding takes precedence over "*"
+		q, wildcard := -1.0, 0.0
+		for _, part := range strings.Split(header, ",") {
+			coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+			coding = strings.ToLower(strings.TrimSpace(coding))
+			if coding != encoder.Name && coding != "*" {
+				continue
+			}
+			pq := 1.0
+			if key, v, _ := strings.Cut(params, "="); strings.TrimSpace(key) == "q" {
+				var err error
+				if pq, err = strconv.ParseFloat(strings.TrimSpace(v), 64); err != nil {
+					continue
+				}
+			}
+			if coding == "*" {
+				wildcard = pq
+			} else {
+				q = pq
+			}
+		}
+		if q < 0 {
+			q = wildcard
+		}
+		if q > bestQ {
+			best, bestQ = encoder, q
+		}
+	}
+	return best, bestQ > 0
+}
+
+// compressWriter is an http.ResponseWriter compressing the response if its
+// first write is large enough. The status code is held back until then, as
+// compressing changes the headers.
+type compressWriter struct {
+	http.ResponseWriter
+	encoder Encoder
+	accept  bool
+	minSize int
+
+	status      int
+	wroteHeader bool
+	w           io.WriteCloser
+}
+
+// newCompressWriter returns a writer compressing the response to r with the
+// preferred of encoders. Close must be called once the response is written.
+func newCompressWriter(w http.ResponseWriter, r *http.Request, encoders []Encoder, minSize int) *compressWriter {
+	w.Header().Add("Vary", "Accept-Encoding")
+	encoder, accept := acceptEncoding(r, encoders)
+	return &compressWriter{
+		ResponseWriter: w,
+		encoder:        encoder,
+		accept:         accept,
+		minSize:        minSize,
+		status:         http.StatusOK,
+	}
+}
+
+// WriteHeader implements http.ResponseWriter
+func (cw *compressWriter) WriteHeader(status int) {
+	if !cw.wroteHeader {
+		cw.status = status
+	}
+}
+
+// Write implements http.ResponseWriter
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.start(len(p))
+	}
+	if cw.w != nil {
+		return cw.w.Write(p)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+// start writes the headers, compressing the response if its first write of
+// n bytes is large enough.
+func (cw *compressWriter) start(n int) {
+	cw.wroteHeader = true
+	h := cw.Header()
+	if cw.accept && n >= cw.minSize && h.Get("Content-Encoding") == "" {
+		h.Del("Content-Length")
+		h.Set("Content-Encoding", cw.encoder.Name)
+		cw.w = cw.encoder.NewWriter(cw.ResponseWriter)
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+}
+
+// Close flushes the compressed response, or writes the status code of an
+// empty one.
+func (cw *compressWriter) Close() error {
+	if !cw.wroteHeader {
+		cw.wroteHeader = true
+		cw.ResponseWriter.WriteHeader(cw.status)
+	}
+	if cw.w != nil {
+		return cw.w.Close()
+	}
+	return nil
+}
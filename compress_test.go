@@ -0,0 +1,116 @@
+package health
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestCompression ensures large responses are compressed for clients
+// accepting it.
+func TestCompression(t *testing.T) {
+	registry := NewRegistry()
+	for i := 0; i < 50; i++ {
+		registry.Register("check"+strconv.Itoa(i), NewStatusUpdater())
+	}
+
+	get := func(handler http.Handler, acceptEncoding string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+		if err != nil {
+			t.Errorf("Failed to create request.")
+		}
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	handler := registry.Handler(WithCompression(0))
+	recorder := get(handler, "br;q=1.0, gzip;q=0.8")
+	if recorder.Code != http.StatusOK || recorder.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected a gzip response, got %d %v", recorder.Code, recorder.Header())
+	}
+	if recorder.Header().Get("Content-Length") != "" {
+		t.Errorf("expected the length of the uncompressed body to be dropped")
+	}
+	if vary := recorder.Header().Get("Vary"); vary != "Accept-Encoding" {
+		t.Errorf("unexpected Vary header: %q", vary)
+	}
+	zr, err := gzip.NewReader(recorder.Body)
+	if err != nil {
+		t.Fatalf("error reading gzip response: %v", err)
+	}
+	var status Status
+	if err := json.NewDecoder(zr).Decode(&status); err != nil || len(status) != 50 {
+		t.Errorf("unexpected response: %v %v", status, err)
+	}
+
+	for _, acceptEncoding := range []string{"", "identity", "gzip;q=0", "*;q=0"} {
+		recorder = get(handler, acceptEncoding)
+		if recorder.Header().Get("Content-Encoding") != "" || recorder.Header().Get("Content-Length") == "" {
+			t.Errorf("%q: expected an uncompressed response, got %v", acceptEncoding, recorder.Header())
+		}
+	}
+
+	small := NewRegistry()
+	small.Register("check", NewStatusUpdater())
+	recorder = get(small.Handler(WithCompression(0)), "gzip")
+	if recorder.Header().Get("Content-Encoding") != "" || !strings.Contains(recorder.Body.String(), `"check"`) {
+		t.Errorf("expected small responses not to be compressed, got %v", recorder.Header())
+	}
+}
+
+// upperEncoder is an Encoder turning responses to upper case.
+type upperEncoder struct {
+	w io.Writer
+}
+
+func (e upperEncoder) Write(p []byte) (int, error) {
+	return e.w.Write([]byte(strings.ToUpper(string(p))))
+}
+
+func (e upperEncoder) Close() error {
+	return nil
+}
+
+// TestCompressionEncoders ensures custom encoders are negotiated in order of
+// preference.
+func TestCompressionEncoders(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("check", NewStatusUpdater())
+	upper := Encoder{
+		Name:      "upper",
+		NewWriter: func(w io.Writer) io.WriteCloser { return upperEncoder{w} },
+	}
+	handler := registry.Handler(WithCompression(1, upper))
+
+	for acceptEncoding, expected := range map[string]string{
+		"gzip, upper":         "upper",
+		"*":                   "upper",
+		"gzip, upper;q=0.5":   "gzip",
+		"*, upper;q=0":        "gzip",
+		"deflate, compress":   "",
+		"GZIP;q=0.9, upper;q": "gzip",
+	} {
+		req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+		if err != nil {
+			t.Errorf("Failed to create request.")
+		}
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		if got := recorder.Header().Get("Content-Encoding"); got != expected {
+			t.Errorf("%q: unexpected encoding %q, expected %q", acceptEncoding, got, expected)
+		}
+		if expected == "upper" && !strings.Contains(recorder.Body.String(), `"CHECK"`) {
+			t.Errorf("%q: unexpected body %s", acceptEncoding, recorder.Body.String())
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package consul
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Agent is the subset of the Consul agent API the Syncer needs. HTTPAgent
+// implements it on top of the HTTP API.
+type Agent interface {
+	// Register registers the service with its checks, replacing a previous
+	// registration with the same service ID.
+	Register(ctx context.Context, registration Registration) error
+	// Deregister removes the service and its checks.
+	Deregister(ctx context.Context, serviceID string) error
+	// UpdateTTL sets the status of a TTL check, one of the Status constants,
+	// with output as its output.
+	UpdateTTL(ctx context.Context, checkID, status, output string) error
+}
+
+// Statuses of Consul checks.
+const (
+	StatusPassing  = "passing"
+	StatusWarning  = "warning"
+	StatusCritical = "critical"
+)
+
+// Registration is a service registration.
+type Registration struct {
+	ID      string
+	Name    string
+	Address string
+	Port    int
+	Tags    []string
+	Checks  []Check
+}
+
+// Check is a check of a service registration: a TTL check if TTL is set, an
+// HTTP check otherwise.
+type Check struct {
+	ID   string
+	Name string
+	TTL  time.Duration
+
+	HTTP     string
+	Interval time.Duration
+	Timeout  time.Duration
+
+	// DeregisterAfter removes the service if the check stays critical for
+	// that long. Zero disables it.
+	DeregisterAfter time.Duration
+}
+
+// HTTPAgent is an Agent talking to the HTTP API of a Consul agent.
+type HTTPAgent struct {
+	// Address is the base URL of the agent, like "http://127.0.0.1:8500".
+	Address string
+	// Token is the ACL token sent with requests, if any.
+	Token string
+	// Client sends the requests. A nil client uses http.DefaultClient.
+	Client *http.Client
+}
+
+// agentCheck is a check in the format of the agent API.
+type agentCheck struct {
+	CheckID                        string `json:"CheckID"`
+	Name                           string `json:"Name"`
+	TTL                            string `json:"TTL,omitempty"`
+	HTTP                           string `json:"HTTP,omitempty"`
+	Interval                       string `json:"Interval,omitempty"`
+	Timeout                        string `json:"Timeout,omitempty"`
+	DeregisterCriticalServiceAfter string `json:"DeregisterCriticalServiceAfter,omitempty"`
+}
+
+// Register implements the Agent interface
+func (a *HTTPAgent) Register(ctx context.Context, registration Registration) error {
+	body := struct {
+		ID      string       `json:"ID"`
+		Name    string       `json:"Name"`
+		Address string       `json:"Address,omitempty"`
+		Port    int          `json:"Port,omitempty"`
+		Tags    []string     `json:"Tags,omitempty"`
+		Checks  []agentCheck `json:"Checks,omitempty"`
+	}{
+		ID:      registration.ID,
+		Name:    registration.Name,
+		Address: registration.Address,
+		Port:    registration.Port,
+		Tags:    registration.Tags,
+	}
+	for _, check := range registration.Checks {
+		body.Checks = append(body.Checks, agentCheck{
+			CheckID:                        check.ID,
+			Name:                           check.Name,
+			TTL:                            duration(check.TTL),
+			HTTP:                           check.HTTP,
+			Interval:                       duration(check.Interval),
+			Timeout:                        duration(check.Timeout),
+			DeregisterCriticalServiceAfter: duration(check.DeregisterAfter),
+		})
+	}
+	return a.put(ctx, "/v1/agent/service/register", body)
+}
+
+// Deregister implements the Agent interface
+func (a *HTTPAgent) Deregister(ctx context.Context, serviceID string) error {
+	return a.put(ctx, "/v1/agent/service/deregister/"+url.PathEscape(serviceID), nil)
+}
+
+// UpdateTTL implements the Agent interface
+func (a *HTTPAgent) UpdateTTL(ctx context.Context, checkID, status, output string) error {
+	return a.put(ctx, "/v1/agent/check/update/"+url.PathEscape(checkID), struct {
+		Status string `json:"Status"`
+		Output string `json:"Output"`
+	}{status, output})
+}
+
+// put sends body as JSON to path on the agent.
+func (a *HTTPAgent) put(ctx context.Context, path string, body any) error {
+	var p []byte
+	if body != nil {
+		var err error
+		if p, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, "PUT", strings.TrimSuffix(a.Address, "/")+path, bytes.NewReader(p))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if a.Token != "" {
+		req.Header.Set("X-Consul-Token", a.Token)
+	}
+
+	client := a.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.New("consul returned unexpected status: " + strconv.Itoa(resp.StatusCode))
+	}
+	return nil
+}
+
+// duration formats d for the agent API, empty if it is not set.
+func duration(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return d.String()
+}
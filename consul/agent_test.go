@@ -0,0 +1,57 @@
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPAgent(t *testing.T) {
+	type request struct {
+		method, path, token string
+		body                map[string]any
+	}
+	requests := make(chan request, 3)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		requests <- request{r.Method, r.URL.Path, r.Header.Get("X-Consul-Token"), body}
+	}))
+	defer server.Close()
+	agent := &HTTPAgent{Address: server.URL, Token: "secret"}
+
+	err := agent.Register(context.Background(), Registration{
+		ID:     "billing-1",
+		Name:   "billing",
+		Checks: []Check{{ID: "billing-1:db", Name: "db", TTL: 30 * time.Second}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req := <-requests
+	if req.method != "PUT" || req.path != "/v1/agent/service/register" || req.token != "secret" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+	checks, _ := req.body["Checks"].([]any)
+	if req.body["ID"] != "billing-1" || len(checks) != 1 || checks[0].(map[string]any)["TTL"] != "30s" {
+		t.Errorf("unexpected registration: %v", req.body)
+	}
+
+	if err := agent.UpdateTTL(context.Background(), "billing-1:db", StatusCritical, "down"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req = <-requests
+	if req.path != "/v1/agent/check/update/billing-1:db" || req.body["Status"] != StatusCritical || req.body["Output"] != "down" {
+		t.Errorf("unexpected update: %+v", req)
+	}
+
+	if err := agent.Deregister(context.Background(), "billing-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req = <-requests; req.path != "/v1/agent/service/deregister/billing-1" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+}
@@ -0,0 +1,238 @@
+// Package consul keeps the registration of a service in Consul in sync with
+// its health.Registry. By default every check of the registry is registered
+// as a TTL check, updated from the results of the registry. With
+// WithHTTPCheck, a single HTTP check pointing at the status endpoint of the
+// service is registered instead, and Consul polls it.
+//
+//	syncer, err := consul.New(health.DefaultRegistry, &consul.HTTPAgent{Address: "http://127.0.0.1:8500"},
+//	  consul.Registration{ID: "billing-1", Name: "billing", Port: 8080})
+//	if err != nil {
+//	  return err
+//	}
+//	go syncer.Run(ctx)
+package consul
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/docker/distribution/health"
+)
+
+// DefaultTTL is the TTL of the checks registered without a WithTTL option.
+const DefaultTTL = 30 * time.Second
+
+// Syncer registers a service and its checks with a Consul agent and keeps
+// their status up to date.
+type Syncer struct {
+	registry        *health.Registry
+	agent           Agent
+	service         Registration
+	ttl             time.Duration
+	interval        time.Duration
+	httpURL         string
+	httpInterval    time.Duration
+	deregisterAfter time.Duration
+	logger          health.Logger
+
+	mu sync.Mutex
+	// registered are the names of the checks registered as TTL checks.
+	registered []string
+}
+
+// Option configures a Syncer created by New.
+type Option func(*Syncer)
+
+// WithTTL sets the TTL of the checks. Consul marks checks that are not
+// updated within their TTL as critical, e.g. when the service died.
+func WithTTL(ttl time.Duration) Option {
+	return func(s *Syncer) {
+		s.ttl = ttl
+	}
+}
+
+// WithUpdateInterval sets how often Run updates the checks. The default is
+// a third of the TTL.
+func WithUpdateInterval(interval time.Duration) Option {
+	return func(s *Syncer) {
+		s.interval = interval
+	}
+}
+
+// WithHTTPCheck registers a single HTTP check polling url, the status
+// endpoint of the service, every interval instead of TTL checks.
+func WithHTTPCheck(url string, interval time.Duration) Option {
+	return func(s *Syncer) {
+		s.httpURL = url
+		s.httpInterval = interval
+	}
+}
+
+// WithDeregisterAfter makes Consul deregister the service once one of its
+// checks has been critical for d.
+func WithDeregisterAfter(d time.Duration) Option {
+	return func(s *Syncer) {
+		s.deregisterAfter = d
+	}
+}
+
+// WithLogger sets the logger failed updates are logged to. By default
+// health.DefaultLogger is used.
+func WithLogger(logger health.Logger) Option {
+	return func(s *Syncer) {
+		s.logger = logger
+	}
+}
+
+// New returns a Syncer registering service with agent, with checks for the
+// checks in registry. Checks already set on service are registered too. It
+// does nothing until Register or Run is called. It fails if the TTL or the
+// update interval is not positive.
+func New(registry *health.Registry, agent Agent, service Registration, opts ...Option) (*Syncer, error) {
+	s := &Syncer{
+		registry: registry,
+		agent:    agent,
+		service:  service,
+		ttl:      DefaultTTL,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.ttl <= 0 {
+		return nil, errors.New("consul: the TTL must be positive")
+	}
+	if s.interval == 0 {
+		s.interval = s.ttl / 3
+	}
+	if s.interval <= 0 {
+		return nil, errors.New("consul: the update interval must be positive")
+	}
+	return s, nil
+}
+
+// CheckID returns the ID of the Consul check of the named check.
+func (s *Syncer) CheckID(name string) string {
+	return s.service.ID + ":" + name
+}
+
+// Register registers the service with its checks, and sets the status of
+// the TTL checks.
+func (s *Syncer) Register(ctx context.Context) error {
+	if s.httpURL != "" {
+		return s.agent.Register(ctx, s.registration(nil))
+	}
+	return s.sync(ctx, true)
+}
+
+// Sync evaluates the checks of the registry and updates their status. The
+// service is registered again if checks were added or removed since.
+func (s *Syncer) Sync(ctx context.Context) error {
+	if s.httpURL != "" {
+		return nil
+	}
+	return s.sync(ctx, false)
+}
+
+// Deregister removes the service and its checks.
+func (s *Syncer) Deregister(ctx context.Context) error {
+	return s.agent.Deregister(ctx, s.service.ID)
+}
+
+// Run registers the service and updates its checks until ctx is done, then
+// deregisters it. Failures are logged and retried on the next update.
+func (s *Syncer) Run(ctx context.Context) {
+	if err := s.Register(ctx); err != nil {
+		s.log().Printf("consul: error registering service %s: %v", s.service.ID, err)
+	}
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if err := s.Deregister(context.WithoutCancel(ctx)); err != nil {
+				s.log().Printf("consul: error deregistering service %s: %v", s.service.ID, err)
+			}
+			return
+		case <-ticker.C:
+		}
+		if err := s.sync(ctx, false); err != nil {
+			s.log().Printf("consul: error updating service %s: %v", s.service.ID, err)
+		}
+	}
+}
+
+// sync updates the TTL checks, registering the service first if register is
+// set or the checks changed.
+func (s *Syncer) sync(ctx context.Context, register bool) error {
+	checks := s.registry.CheckStatusContext(ctx).Redacted()
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if register || !slices.Equal(names, s.registered) {
+		if err := s.agent.Register(ctx, s.registration(names)); err != nil {
+			return err
+		}
+		s.registered = names
+	}
+	for _, name := range names {
+		check := checks[name]
+		if err := s.agent.UpdateTTL(ctx, s.CheckID(name), status(check), check.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// registration returns the registration of the service with TTL checks for
+// the named checks, or its HTTP check.
+func (s *Syncer) registration(names []string) Registration {
+	r := s.service
+	r.Checks = append([]Check(nil), s.service.Checks...)
+	if s.httpURL != "" {
+		r.Checks = append(r.Checks, Check{
+			ID:              s.CheckID("health"),
+			Name:            "health",
+			HTTP:            s.httpURL,
+			Interval:        s.httpInterval,
+			DeregisterAfter: s.deregisterAfter,
+		})
+		return r
+	}
+	for _, name := range names {
+		r.Checks = append(r.Checks, Check{
+			ID:              s.CheckID(name),
+			Name:            name,
+			TTL:             s.ttl,
+			DeregisterAfter: s.deregisterAfter,
+		})
+	}
+	return r
+}
+
+// status returns the Consul status of check.
+func status(check health.HealthCheck) string {
+	switch check.Status() {
+	case health.StatusPass:
+		return StatusPassing
+	case health.StatusWarn:
+		return StatusWarning
+	}
+	return StatusCritical
+}
+
+// log returns the logger of the syncer.
+func (s *Syncer) log() health.Logger {
+	if s.logger != nil {
+		return s.logger
+	}
+	return health.DefaultLogger()
+}
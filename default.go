@@ -0,0 +1,37 @@
+package health
+
+import (
+	"net/http"
+	"sync"
+)
+
+// defaultMu guards DefaultRegistry against SetDefaultRegistry.
+var defaultMu sync.RWMutex
+
+// Default returns the default registry.
+func Default() *Registry {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return DefaultRegistry
+}
+
+// SetDefaultRegistry makes registry the default registry and returns the
+// previous one. Tests can use it to isolate the checks they register through
+// the package level functions:
+//
+//	defer health.SetDefaultRegistry(health.SetDefaultRegistry(health.NewRegistry()))
+func SetDefaultRegistry(registry *Registry) *Registry {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	previous := DefaultRegistry
+	DefaultRegistry = registry
+	return previous
+}
+
+// MustRegisterDefaultEndpoint mounts StatusHandler at DefaultPath on
+// http.DefaultServeMux. Importing the package does not, so it never mutates
+// the default mux unless asked to. Like http.Handle, it panics if the path is
+// already registered.
+func MustRegisterDefaultEndpoint() {
+	http.HandleFunc(DefaultPath, StatusHandler)
+}
@@ -0,0 +1,50 @@
+package health
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestSetDefaultRegistry ensures the package level functions use the
+// replaced registry.
+func TestSetDefaultRegistry(t *testing.T) {
+	registry := NewRegistry()
+	previous := SetDefaultRegistry(registry)
+	defer SetDefaultRegistry(previous)
+
+	Register("isolated", CheckFunc(func() Result { return Result{} }))
+	if len(registry.CheckStatus()) != 1 || Default() != registry {
+		t.Errorf("expected the check to be registered in the replaced registry")
+	}
+	if _, ok := previous.CheckStatus()["isolated"]; ok {
+		t.Errorf("expected the previous registry not to be modified")
+	}
+}
+
+// TestMustRegisterDefaultEndpoint ensures the endpoint is only mounted when
+// asked to.
+func TestMustRegisterDefaultEndpoint(t *testing.T) {
+	get := func() int {
+		req, err := http.NewRequest("GET", "https://fakeurl.com"+DefaultPath, nil)
+		if err != nil {
+			t.Fatalf("Failed to create request.")
+		}
+		recorder := httptest.NewRecorder()
+		http.DefaultServeMux.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	defer SetDefaultRegistry(SetDefaultRegistry(NewRegistry()))
+
+	// The mux outlives repeated runs of the test.
+	if _, pattern := http.DefaultServeMux.Handler(httptest.NewRequest("GET", DefaultPath, nil)); pattern != DefaultPath {
+		if code := get(); code != http.StatusNotFound {
+			t.Errorf("expected no endpoint before registering it, got %d", code)
+		}
+		MustRegisterDefaultEndpoint()
+	}
+	if code := get(); code != http.StatusOK {
+		t.Errorf("expected the endpoint to be registered, got %d", code)
+	}
+}
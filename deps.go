@@ -0,0 +1,94 @@
+package health
+
+import (
+	"fmt"
+	"sort"
+)
+
+// WithDependencies declares that the check depends on the named checks. When
+// one of them fails, the check is not run and is reported as skipped.
+// Dependencies that are not evaluated together with the check, because they
+// are not registered or are filtered out, are ignored. So are dependencies
+// closing a cycle.
+func WithDependencies(names ...string) CheckOption {
+	return func(rc *registeredCheck) {
+		rc.deps = names
+	}
+}
+
+// RegisterWithDeps associates the checker with the provided name, depending
+// on the named checks. See WithDependencies.
+func (registry *Registry) RegisterWithDeps(name string, check Checker, deps ...string) {
+	registry.Register(name, check, WithDependencies(deps...))
+}
+
+// RegisterWithDeps associates the checker with the provided name in the
+// default registry, depending on the named checks.
+func RegisterWithDeps(name string, check Checker, deps ...string) {
+	Default().RegisterWithDeps(name, check, deps...)
+}
+
+// skipped returns the status of the check when it is not run.
+func (rc *registeredCheck) skipped(reason string) HealthCheck {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	check := HealthCheck{
+		Healthy:   false,
+		Message:   "skipped: " + reason,
+		Severity:  rc.severity,
+		State:     StateUnhealthy,
+		Code:      CodeSkipped,
+		Metadata:  rc.metadata,
+		sensitive: rc.sensitive,
+		weight:    rc.weight,
+		err:       fmt.Errorf("%w: %s", ErrSkipped, reason),
+		failures:  rc.failures,
+	}
+	if !rc.lastSuccess.IsZero() {
+		lastSuccess := rc.lastSuccess
+		check.LastSuccess = &lastSuccess
+	}
+	return check
+}
+
+// dependencyEdges returns the dependencies each check has to wait for. Only
+// dependencies among checks are kept, and edges closing a cycle are dropped so
+// waiting on them can not deadlock.
+func dependencyEdges(checks map[string]*registeredCheck) map[string][]string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	var (
+		state = make(map[string]int, len(checks))
+		edges = make(map[string][]string)
+		visit func(name string)
+	)
+	visit = func(name string) {
+		state[name] = visiting
+		for _, dep := range checks[name].deps {
+			if _, ok := checks[dep]; !ok || state[dep] == visiting {
+				continue
+			}
+			if state[dep] == unvisited {
+				visit(dep)
+			}
+			edges[name] = append(edges[name], dep)
+		}
+		state[name] = visited
+	}
+
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if state[name] == unvisited {
+			visit(name)
+		}
+	}
+	return edges
+}
@@ -0,0 +1,50 @@
+package health
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestDependencies ensures checks are skipped while a dependency fails.
+func TestDependencies(t *testing.T) {
+	registry := NewRegistry(WithMaxConcurrency(1))
+	database := NewStatusUpdater()
+	apiRuns := 0
+	registry.Register("database", database)
+	registry.RegisterWithDeps("api", CheckFunc(func() Result {
+		apiRuns++
+		return Result{}
+	}), "database", "not_registered")
+	registry.RegisterWithDeps("frontend", CheckFunc(func() Result {
+		return Result{}
+	}), "api")
+
+	status := registry.CheckStatus()
+	if !status["api"].Healthy || !status["frontend"].Healthy || apiRuns != 1 {
+		t.Errorf("unexpected status with healthy dependencies: %v", status)
+	}
+
+	database.Update(Result{Error: errors.New("failing")})
+	status = registry.CheckStatus()
+	if check := status["api"]; check.Healthy || check.Message != "skipped: dependency database failing" {
+		t.Errorf("unexpected api status: %+v", check)
+	}
+	if check := status["frontend"]; check.Healthy || check.Message != "skipped: dependency api failing" {
+		t.Errorf("unexpected frontend status: %+v", check)
+	}
+	if apiRuns != 1 {
+		t.Errorf("api check was not expected to run while its dependency fails")
+	}
+}
+
+// TestDependencyCycle ensures cyclic dependencies don't deadlock.
+func TestDependencyCycle(t *testing.T) {
+	registry := NewRegistry()
+	pass := CheckFunc(func() Result { return Result{} })
+	registry.RegisterWithDeps("a", pass, "b")
+	registry.RegisterWithDeps("b", pass, "a")
+
+	if status := registry.CheckStatus(); !status["a"].Healthy || !status["b"].Healthy {
+		t.Errorf("unexpected status: %v", status)
+	}
+}
@@ -0,0 +1,60 @@
+package health
+
+// DisabledMessage is the message of disabled checks.
+const DisabledMessage = "disabled"
+
+// Disable stops running the named check until it is enabled again, e.g.
+// when the dependency it checks is turned off by a feature flag. Disabled
+// checks are reported with Disabled set and DisabledMessage, and as healthy
+// so they do not affect the aggregate status or the checks depending on
+// them.
+func (registry *Registry) Disable(name string) error {
+	return registry.setDisabled(name, true)
+}
+
+// Enable runs the named check again after it was disabled.
+func (registry *Registry) Enable(name string) error {
+	return registry.setDisabled(name, false)
+}
+
+// Disable stops running the named check of the default registry.
+func Disable(name string) error {
+	return Default().Disable(name)
+}
+
+// Enable runs the named check of the default registry again.
+func Enable(name string) error {
+	return Default().Enable(name)
+}
+
+func (registry *Registry) setDisabled(name string, disabled bool) error {
+	rc, err := registry.registered(name)
+	if err != nil {
+		return err
+	}
+	rc.mu.Lock()
+	rc.disabled = disabled
+	rc.invalidate()
+	rc.mu.Unlock()
+	if !disabled {
+		registry.prefetch(name, rc)
+	}
+	return nil
+}
+
+// disabledStatus returns the status of the check if it is disabled.
+func (rc *registeredCheck) disabledStatus() (HealthCheck, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if !rc.disabled {
+		return HealthCheck{}, false
+	}
+	return HealthCheck{
+		Healthy:  true,
+		Message:  DisabledMessage,
+		Severity: rc.severity,
+		State:    StateHealthy,
+		Metadata: rc.metadata,
+		Disabled: true,
+	}, true
+}
@@ -0,0 +1,44 @@
+package health
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestDisable ensures disabled checks are not run and do not affect the
+// aggregate status.
+func TestDisable(t *testing.T) {
+	registry := NewRegistry()
+	calls := 0
+	registry.Register("flagged", CheckFunc(func() Result {
+		calls++
+		return Result{Error: errors.New("down")}
+	}))
+	registry.Register("dependent", CheckFunc(func() Result { return Result{} }), WithDependencies("flagged"))
+
+	if err := registry.Disable("missing"); !errors.Is(err, ErrNotRegistered) {
+		t.Errorf("expected ErrNotRegistered, got %v", err)
+	}
+
+	if err := registry.Disable("flagged"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	status := registry.CheckStatus()
+	check := status["flagged"]
+	if !check.Disabled || check.Message != DisabledMessage || calls != 0 {
+		t.Errorf("expected the check to be reported disabled without running, got %+v after %d calls", check, calls)
+	}
+	if overall := overallStatus(status); overall != StatusPass {
+		t.Errorf("expected disabled checks not to affect the status, got %s", overall)
+	}
+	if !status["dependent"].Healthy {
+		t.Errorf("expected checks depending on disabled checks to run, got %+v", status["dependent"])
+	}
+
+	if err := registry.Enable("flagged"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if check := registry.CheckStatus()["flagged"]; check.Disabled || check.Healthy || calls != 1 {
+		t.Errorf("expected the check to run once enabled, got %+v", check)
+	}
+}
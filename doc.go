@@ -1,7 +1,8 @@
 // Package health provides a generic health checking framework.
-// The health package works expvar style. By importing the package the debug
-// server is getting a "/debug/health" endpoint that returns the current
-// status of the application.
+// The health package works expvar style. After calling
+// MustRegisterDefaultEndpoint the debug server is getting a "/debug/health"
+// endpoint that returns the current status of the application. Importing the
+// package alone registers no endpoint.
 // If there are no errors, "/debug/health" will return a HTTP 200 status,
 // together with an empty JSON reply "{}". If there are any checks
 // with errors, the JSON reply will include all the failed checks, and the
@@ -15,10 +16,16 @@
 //
 // Installing
 //
-// To install health, just import it in your application:
+// To install health, import it in your application and register the
+// endpoint:
 //
 //  import "github.com/docker/distribution/health"
 //
+//  health.MustRegisterDefaultEndpoint()
+//
+// RegisterRoutes mounts it, along with "/healthz", "/readyz" and "/livez", on
+// a mux of your choice instead.
+//
 // You can also (optionally) import "health/api" that will add two convenience
 // endpoints: "/debug/health/down" and "/debug/health/up". These endpoints add
 // "manual" checks that allow the service to quickly be brought in/out of
@@ -0,0 +1,75 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// DrainingCheckName is the name of the failing readiness check reported while
+// a registry is draining.
+const DrainingCheckName = "draining"
+
+// drainingChecker is the check reported while a registry is draining.
+var drainingChecker = CheckFuncWithContext(func(ctx context.Context) Result {
+	return Result{
+		Error:   errors.New("service is shutting down"),
+		Message: "service is shutting down",
+	}
+})
+
+// SetDraining marks the registry as draining, or not. While draining, a
+// failing readiness check named DrainingCheckName is reported, so load
+// balancers stop sending traffic while liveness checks stay healthy and the
+// process can finish its in-flight work.
+func (registry *Registry) SetDraining(draining bool) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.draining = draining
+}
+
+// Draining reports whether the registry is draining.
+func (registry *Registry) Draining() bool {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	return registry.draining
+}
+
+// SetDraining marks the default registry as draining, or not.
+func SetDraining(draining bool) {
+	Default().SetDraining(draining)
+}
+
+// DrainOnSignal marks the registry as draining when one of the signals is
+// received, SIGTERM and os.Interrupt if none are given. The returned context
+// is cancelled at the same time, so it can be used to start shutting down
+// the rest of the application. Calling the returned function stops listening
+// for the signals and cancels the context.
+func (registry *Registry) DrainOnSignal(parent context.Context, signals ...os.Signal) (context.Context, context.CancelFunc) {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGTERM, os.Interrupt}
+	}
+	ctx, cancel := context.WithCancel(parent)
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+	go func() {
+		defer signal.Stop(ch)
+		select {
+		case <-ch:
+			registry.SetDraining(true)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
+}
+
+// DrainOnSignal marks the default registry as draining when one of the
+// signals is received. See Registry.DrainOnSignal.
+func DrainOnSignal(parent context.Context, signals ...os.Signal) (context.Context, context.CancelFunc) {
+	return Default().DrainOnSignal(parent, signals...)
+}
@@ -0,0 +1,45 @@
+package health
+
+import (
+	"context"
+	"testing"
+)
+
+// TestSetDraining ensures draining fails readiness but not liveness.
+func TestSetDraining(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("deadlock", CheckFunc(func() Result {
+		return Result{}
+	}), WithKind(Liveness))
+
+	registry.SetDraining(true)
+	if !registry.Draining() {
+		t.Fatalf("registry was expected to be draining")
+	}
+
+	ready := registry.CheckStatusKind(context.Background(), Readiness)
+	if check, ok := ready[DrainingCheckName]; !ok || check.Healthy {
+		t.Errorf("readiness was expected to fail while draining: %v", ready)
+	}
+	live := registry.CheckStatusKind(context.Background(), Liveness)
+	if _, ok := live[DrainingCheckName]; ok || !live["deadlock"].Healthy {
+		t.Errorf("liveness was expected to be unaffected by draining: %v", live)
+	}
+
+	registry.SetDraining(false)
+	if _, ok := registry.CheckStatus()[DrainingCheckName]; ok {
+		t.Errorf("draining check was expected to be gone")
+	}
+}
+
+// TestDrainOnSignalCancel ensures cancelling does not mark the registry as
+// draining.
+func TestDrainOnSignalCancel(t *testing.T) {
+	registry := NewRegistry()
+	ctx, cancel := registry.DrainOnSignal(context.Background())
+	cancel()
+	<-ctx.Done()
+	if registry.Draining() {
+		t.Errorf("registry was not expected to be draining")
+	}
+}
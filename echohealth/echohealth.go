@@ -0,0 +1,42 @@
+// Package echohealth exposes the status handlers of a health.Registry as
+// echo handlers.
+//
+//	e := echo.New()
+//	echohealth.RegisterRoutes(e, registry)
+package echohealth
+
+import (
+	"github.com/docker/distribution/health"
+	"github.com/labstack/echo/v4"
+)
+
+// Status returns a handler serving the status of all checks in the registry,
+// see Registry.Handler.
+func Status(registry *health.Registry, opts ...health.HandlerOption) echo.HandlerFunc {
+	return echo.WrapHandler(registry.Handler(opts...))
+}
+
+// Liveness returns a handler serving the status of the Liveness checks in
+// the registry.
+func Liveness(registry *health.Registry, opts ...health.HandlerOption) echo.HandlerFunc {
+	return Status(registry, append(opts, health.ForKind(health.Liveness))...)
+}
+
+// Readiness returns a handler serving the status of the Readiness checks in
+// the registry.
+func Readiness(registry *health.Registry, opts ...health.HandlerOption) echo.HandlerFunc {
+	return Status(registry, append(opts, health.ForKind(health.Readiness))...)
+}
+
+// Router is implemented by *echo.Echo and *echo.Group.
+type Router interface {
+	GET(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+}
+
+// RegisterRoutes mounts the status handlers of the registry on router at
+// health.HealthzPath, health.ReadyzPath and health.LivezPath.
+func RegisterRoutes(router Router, registry *health.Registry, opts ...health.HandlerOption) {
+	router.GET(health.HealthzPath, Status(registry, opts...))
+	router.GET(health.ReadyzPath, Readiness(registry, opts...))
+	router.GET(health.LivezPath, Liveness(registry, opts...))
+}
@@ -0,0 +1,122 @@
+package health
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Environment variables read by FromEnv.
+const (
+	EnvPath           = "HEALTHCHECK_PATH"
+	EnvVerbose        = "HEALTHCHECK_VERBOSE"
+	EnvTimeout        = "HEALTHCHECK_TIMEOUT"
+	EnvFailureStatus  = "HEALTHCHECK_FAILURE_STATUS"
+	EnvDegradedStatus = "HEALTHCHECK_DEGRADED_STATUS"
+)
+
+// DefaultPath is the path of the status endpoint when EnvPath is not set.
+const DefaultPath = "/debug/health"
+
+// EnvConfig is the configuration of a status endpoint read from the
+// environment by FromEnv.
+type EnvConfig struct {
+	// Path is the path to serve the endpoint at, from EnvPath.
+	Path string
+	// Verbose lists the checks in responses, from EnvVerbose. Otherwise
+	// only the aggregate status is reported, see WithMinimalResponse.
+	Verbose bool
+	// Timeout is the default timeout of the checks, from EnvTimeout, e.g.
+	// "2s". Zero means no timeout.
+	Timeout time.Duration
+	// FailureStatus and DegradedStatus are the status codes of failing and
+	// degraded responses, from EnvFailureStatus and EnvDegradedStatus. Zero
+	// keeps the handler's default.
+	FailureStatus  int
+	DegradedStatus int
+}
+
+// FromEnv reads the configuration of a status endpoint from the environment,
+// for twelve-factor deployments. Unset variables keep their defaults: the
+// endpoint is served verbosely at DefaultPath, without timeout and with the
+// default status codes.
+//
+//	config, err := health.FromEnv()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	registry := health.NewRegistry(config.RegistryOptions()...)
+//	http.Handle(config.Path, config.Handler(registry))
+func FromEnv() (*EnvConfig, error) {
+	config := &EnvConfig{
+		Path:    DefaultPath,
+		Verbose: true,
+	}
+	if v, ok := os.LookupEnv(EnvPath); ok && v != "" {
+		config.Path = v
+	}
+	if v, ok := os.LookupEnv(EnvVerbose); ok {
+		verbose, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", EnvVerbose, err)
+		}
+		config.Verbose = verbose
+	}
+	if v, ok := os.LookupEnv(EnvTimeout); ok {
+		timeout, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", EnvTimeout, err)
+		}
+		config.Timeout = timeout
+	}
+	for _, status := range []struct {
+		name string
+		code *int
+	}{
+		{EnvFailureStatus, &config.FailureStatus},
+		{EnvDegradedStatus, &config.DegradedStatus},
+	} {
+		v, ok := os.LookupEnv(status.name)
+		if !ok {
+			continue
+		}
+		code, err := strconv.Atoi(v)
+		if err != nil || http.StatusText(code) == "" {
+			return nil, fmt.Errorf("invalid %s: %q is not a status code", status.name, v)
+		}
+		*status.code = code
+	}
+	return config, nil
+}
+
+// RegistryOptions returns the options to create a registry with.
+func (c *EnvConfig) RegistryOptions() []RegistryOption {
+	var opts []RegistryOption
+	if c.Timeout > 0 {
+		opts = append(opts, WithDefaultTimeout(c.Timeout))
+	}
+	return opts
+}
+
+// HandlerOptions returns the options to create a status handler with.
+func (c *EnvConfig) HandlerOptions() []HandlerOption {
+	var opts []HandlerOption
+	if !c.Verbose {
+		opts = append(opts, WithMinimalResponse())
+	}
+	if c.FailureStatus != 0 {
+		opts = append(opts, WithFailureStatus(c.FailureStatus))
+	}
+	if c.DegradedStatus != 0 {
+		opts = append(opts, WithDegradedStatus(c.DegradedStatus))
+	}
+	return opts
+}
+
+// Handler returns a status handler for registry configured by c, with the
+// additional opts.
+func (c *EnvConfig) Handler(registry *Registry, opts ...HandlerOption) http.Handler {
+	return registry.Handler(append(c.HandlerOptions(), opts...)...)
+}
@@ -0,0 +1,57 @@
+package health
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestFromEnv ensures the environment configures the registry and handler.
+func TestFromEnv(t *testing.T) {
+	config, err := FromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Path != DefaultPath || !config.Verbose || config.Timeout != 0 || len(config.HandlerOptions()) != 0 {
+		t.Errorf("unexpected default configuration: %+v", config)
+	}
+
+	t.Setenv(EnvPath, "/healthz")
+	t.Setenv(EnvVerbose, "false")
+	t.Setenv(EnvTimeout, "2s")
+	t.Setenv(EnvFailureStatus, "500")
+	config, err = FromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Path != "/healthz" || config.Verbose || config.Timeout != 2*time.Second || config.FailureStatus != 500 {
+		t.Errorf("unexpected configuration: %+v", config)
+	}
+
+	registry := NewRegistry(config.RegistryOptions()...)
+	registry.Register("check", CheckFunc(func() Result { return Result{Error: errors.New("failing")} }))
+	req, err := http.NewRequest("GET", "https://fakeurl.com/healthz", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request.")
+	}
+	recorder := httptest.NewRecorder()
+	config.Handler(registry).ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusInternalServerError || recorder.Body.String() != StatusFail+"\n" {
+		t.Errorf("unexpected response: %d %q", recorder.Code, recorder.Body.String())
+	}
+
+	for name, value := range map[string]string{
+		EnvVerbose:        "sometimes",
+		EnvTimeout:        "2",
+		EnvDegradedStatus: "999",
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(name, value)
+			if _, err := FromEnv(); err == nil {
+				t.Errorf("expected %s=%s to be invalid", name, value)
+			}
+		})
+	}
+}
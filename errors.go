@@ -0,0 +1,56 @@
+package health
+
+import "errors"
+
+var (
+	// ErrNotRegistered is the cause of the errors of operations on checks
+	// that are not registered, like Override and Disable.
+	ErrNotRegistered = errors.New("check not registered")
+
+	// ErrAlreadyRegistered is the cause of the error of TryRegister when the
+	// name is already registered.
+	ErrAlreadyRegistered = errors.New("check already registered")
+
+	// ErrTimeout is the cause of the errors of checks that timed out, see
+	// WithTimeout. The errors also match context.DeadlineExceeded.
+	ErrTimeout = errors.New("check timed out")
+
+	// ErrSkipped is the cause of the errors of checks that were not run
+	// because one of their dependencies failed, see WithDependencies.
+	ErrSkipped = errors.New("skipped")
+
+	// ErrPending is the error of checks that did not run yet with the
+	// Background and Hybrid strategies, see WithStrategy.
+	ErrPending = errors.New("check has not run yet")
+)
+
+// CheckError is an error concerning a check, returned by the registry. Use
+// errors.Is on it to test its cause, e.g. for ErrNotRegistered, or
+// errors.As to get the name of the check.
+type CheckError struct {
+	// Name is the name of the check.
+	Name  string
+	Cause error
+}
+
+// Error implements the error interface
+func (e *CheckError) Error() string {
+	return e.Name + ": " + e.Cause.Error()
+}
+
+// Unwrap returns the cause of the error.
+func (e *CheckError) Unwrap() error {
+	return e.Cause
+}
+
+// registered returns the check registered as name, or a *CheckError caused
+// by ErrNotRegistered.
+func (registry *Registry) registered(name string) (*registeredCheck, error) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	rc, ok := registry.registeredChecks[name]
+	if !ok {
+		return nil, &CheckError{Name: name, Cause: ErrNotRegistered}
+	}
+	return rc, nil
+}
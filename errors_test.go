@@ -0,0 +1,47 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestErrors ensures the errors of the registry can be tested with errors.Is
+// and errors.As.
+func TestErrors(t *testing.T) {
+	registry := NewRegistry(WithDefaultTimeout(10 * time.Millisecond))
+
+	for _, err := range []error{
+		registry.Override("missing", true, time.Minute, ""),
+		registry.ClearOverride("missing"),
+		registry.Disable("missing"),
+		registry.Enable("missing"),
+	} {
+		var checkErr *CheckError
+		if !errors.Is(err, ErrNotRegistered) || !errors.As(err, &checkErr) || checkErr.Name != "missing" {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if err.Error() != "missing: check not registered" {
+			t.Errorf("unexpected message: %q", err.Error())
+		}
+	}
+
+	registry.RegisterWithContext("slow", CheckFuncWithContext(func(ctx context.Context) Result {
+		<-ctx.Done()
+		time.Sleep(time.Millisecond)
+		return Result{}
+	}))
+	registry.Register("dependent", CheckFunc(func() Result {
+		return Result{}
+	}), WithDependencies("slow"))
+
+	err := registry.Err()
+	if !errors.Is(err, ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, ErrSkipped) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	var checkErr *CheckError
+	if !errors.As(err, &checkErr) || checkErr.Name != "dependent" || !errors.Is(checkErr, ErrSkipped) {
+		t.Errorf("expected the first failing check to be dependent, got %v", checkErr)
+	}
+}
@@ -0,0 +1,125 @@
+package health
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// minimalVariant is the response variant of handlers created with
+// WithMinimalResponse.
+const minimalVariant = "minimal"
+
+// WithETag tags successful responses with an ETag and a Last-Modified header,
+// and responds 304 Not Modified to conditional requests when the checks did
+// not run since, e.g. because their results are reused within the
+// registry's TTL, see WithResultTTL. The tag is a hash of the results of the
+// checks, so matching requests are answered without serializing them. It is
+// weak, as the body also depends on the content coding negotiated with
+// WithCompression.
+// Failing responses are never answered with 304, so pollers relying on the
+// status code keep seeing it.
+func WithETag() HandlerOption {
+	return func(c *handlerConfig) {
+		c.etag = true
+	}
+}
+
+// notModified sets the ETag and Last-Modified headers of the response with
+// the status code status, in the negotiated variant, and responds 304 Not
+// Modified if the request's conditions match. It reports whether it
+// responded.
+func notModified(w http.ResponseWriter, r *http.Request, checks Status, overall string, status int, variant string) bool {
+	if status < 200 || status >= 300 {
+		return false
+	}
+	etag := statusETag(checks, overall, status, variant)
+	w.Header().Set("ETag", etag)
+	w.Header().Add("Vary", "Accept")
+
+	var lastModified time.Time
+	for _, check := range checks {
+		if check.LastChecked.IsZero() {
+			lastModified = time.Time{}
+			break
+		}
+		if check.LastChecked.After(lastModified) {
+			lastModified = check.LastChecked
+		}
+	}
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	match := false
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if "W/"+candidate == etag || candidate == "*" {
+				match = true
+				break
+			}
+		}
+	} else if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		if since, err := http.ParseTime(ims); err == nil {
+			match = !lastModified.Truncate(time.Second).After(since)
+		}
+	}
+	if !match {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// statusETag returns a weak ETag identifying the response with the status of
+// the checks, with the status code status in the negotiated variant.
+// Results of checks that ran again are always considered changed, as their
+// LastChecked time changes.
+func statusETag(checks Status, overall string, status int, variant string) string {
+	h := fnv.New64a()
+	var buf [8]byte
+	writeString := func(s string) {
+		binary.LittleEndian.PutUint64(buf[:], uint64(len(s)))
+		h.Write(buf[:])
+		h.Write([]byte(s))
+	}
+	writeUint := func(v uint64) {
+		binary.LittleEndian.PutUint64(buf[:], v)
+		h.Write(buf[:])
+	}
+	writeBool := func(b bool) {
+		if b {
+			writeUint(1)
+		} else {
+			writeUint(0)
+		}
+	}
+
+	writeString(variant)
+	writeString(overall)
+	writeUint(uint64(status))
+	for _, name := range sortedNames(checks) {
+		check := checks[name]
+		writeString(name)
+		writeBool(check.Healthy)
+		writeString(check.Message)
+		writeString(string(check.Severity))
+		writeString(string(check.State))
+		writeString(string(check.Code))
+		// encoding/json sorts the keys of maps
+		details, _ := json.Marshal(check.Details)
+		writeString(string(details))
+		writeUint(uint64(check.LastChecked.UnixNano()))
+		writeUint(math.Float64bits(check.DurationMs))
+		writeBool(check.Initializing)
+		writeBool(check.Disabled)
+		writeBool(check.Availability != nil)
+	}
+	return `W/"` + strconv.FormatUint(h.Sum64(), 16) + `"`
+}
@@ -0,0 +1,106 @@
+package health
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestETag ensures unchanged statuses are answered with 304 Not Modified.
+func TestETag(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	registry := NewRegistry(WithClock(clock), WithResultTTL(time.Minute))
+	updater := NewStatusUpdater()
+	registry.Register("check", updater)
+	handler := registry.Handler(WithETag())
+
+	get := func(header, value string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+		if err != nil {
+			t.Errorf("Failed to create request.")
+		}
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	first := get("", "")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || !strings.HasPrefix(etag, `W/"`) {
+		t.Fatalf("expected a weakly tagged response, got %d %q", first.Code, etag)
+	}
+	lastModified := first.Header().Get("Last-Modified")
+	if lastModified != clock.Now().Format(http.TimeFormat) {
+		t.Errorf("unexpected Last-Modified: %q", lastModified)
+	}
+
+	if recorder := get("If-None-Match", etag); recorder.Code != http.StatusNotModified || recorder.Body.Len() != 0 {
+		t.Errorf("expected 304 within the TTL, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if recorder := get("If-None-Match", `"other", `+strings.TrimPrefix(etag, "W/")); recorder.Code != http.StatusNotModified {
+		t.Errorf("expected 304 for a match in a list, got %d", recorder.Code)
+	}
+	if recorder := get("If-Modified-Since", lastModified); recorder.Code != http.StatusNotModified {
+		t.Errorf("expected 304 for If-Modified-Since, got %d", recorder.Code)
+	}
+
+	req := httptest.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+	req.Header.Set("If-None-Match", etag)
+	req.Header.Set("Accept", "text/plain")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusOK {
+		t.Errorf("expected other variants to have another tag, got %d", recorder.Code)
+	}
+
+	clock.Add(2 * time.Minute)
+	recorder = get("If-None-Match", etag)
+	if recorder.Code != http.StatusOK || recorder.Header().Get("ETag") == etag {
+		t.Errorf("expected a new tag after the check ran again, got %d %q", recorder.Code, recorder.Header().Get("ETag"))
+	}
+
+	updater.Update(Result{Error: errors.New("failing")})
+	clock.Add(2 * time.Minute)
+	failing := get("", "")
+	clock.Add(time.Second)
+	if recorder := get("If-None-Match", failing.Header().Get("ETag")); recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected failing responses not to be conditional, got %d", recorder.Code)
+	}
+}
+
+// TestStatusETag ensures the tag changes with the code and the details of
+// the checks.
+func TestStatusETag(t *testing.T) {
+	checks := Status{"check": {Healthy: true, State: StateDegraded}}
+	etag := statusETag(checks, StatusWarn, http.StatusOK, "")
+	checks["check"] = HealthCheck{Healthy: true, State: StateDegraded, Code: CodeDegraded}
+	withCode := statusETag(checks, StatusWarn, http.StatusOK, "")
+	checks["check"] = HealthCheck{Healthy: true, State: StateDegraded, Code: CodeDegraded, Details: map[string]any{"lag": 3}}
+	withDetails := statusETag(checks, StatusWarn, http.StatusOK, "")
+	if etag == withCode || withCode == withDetails {
+		t.Errorf("expected distinct tags, got %q, %q and %q", etag, withCode, withDetails)
+	}
+}
+
+// TestETagDisabled ensures responses are not tagged by default.
+func TestETagDisabled(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("check", NewStatusUpdater())
+
+	req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+	if err != nil {
+		t.Errorf("Failed to create request.")
+	}
+	req.Header.Set("If-None-Match", "*")
+	recorder := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusOK || recorder.Header().Get("ETag") != "" {
+		t.Errorf("unexpected response: %d %q", recorder.Code, recorder.Header().Get("ETag"))
+	}
+}
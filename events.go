@@ -0,0 +1,135 @@
+package health
+
+import (
+	"sync"
+	"time"
+)
+
+// EventType is the type of an Event.
+type EventType string
+
+const (
+	// CheckStarted is published before a check runs.
+	CheckStarted EventType = "check_started"
+	// CheckCompleted is published after a check ran, with its result.
+	CheckCompleted EventType = "check_completed"
+	// StateChanged is published after CheckCompleted when the State of the
+	// check changed. Checks are assumed healthy until they first run.
+	StateChanged EventType = "state_changed"
+	// RegistryClosed is published when the registry is closed.
+	RegistryClosed EventType = "registry_closed"
+)
+
+// Event is something that happened in a registry. Fields that do not apply
+// to the type of event are left empty.
+type Event struct {
+	Type EventType
+	// Check is the name of the check, empty for RegistryClosed.
+	Check string
+	Time  time.Time
+
+	Healthy bool
+	State   State
+	// PreviousState is the state of the check before a StateChanged event.
+	PreviousState State
+	Code          Code
+	Message       string
+	Error         error
+	Duration      time.Duration
+	// Failures is the number of consecutive failures of the check.
+	Failures int
+}
+
+// Sink receives the events of a registry. Handle is called synchronously by
+// the goroutine running the check, possibly concurrently for different
+// checks, so it must be safe for concurrent use and must not block. Sinks
+// doing I/O should queue events, like the one returned by NewWebhookSink.
+type Sink interface {
+	Handle(event Event)
+}
+
+// SinkFunc is a convenience type to create functions that implement the Sink
+// interface
+type SinkFunc func(event Event)
+
+// Handle implements the Sink interface
+func (f SinkFunc) Handle(event Event) {
+	f(event)
+}
+
+// AddSink adds a sink receiving the events of the registry, see EventType.
+func (registry *Registry) AddSink(sink Sink) {
+	registry.events.subscribe(sink)
+}
+
+// AddSink adds a sink receiving the events of the default registry.
+func AddSink(sink Sink) {
+	Default().AddSink(sink)
+}
+
+// eventBus delivers events to sinks.
+type eventBus struct {
+	mu    sync.RWMutex
+	sinks []Sink
+}
+
+// subscribe adds sink to the bus.
+func (b *eventBus) subscribe(sink Sink) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sinks = append(b.sinks, sink)
+}
+
+// publish delivers event to all sinks, in the order they were added.
+func (b *eventBus) publish(event Event) {
+	b.mu.RLock()
+	sinks := b.sinks
+	b.mu.RUnlock()
+	for _, sink := range sinks {
+		sink.Handle(event)
+	}
+}
+
+// active reports whether the bus has sinks.
+func (b *eventBus) active() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.sinks) > 0
+}
+
+// publishResult publishes the CheckCompleted event of a run of the check,
+// followed by StateChanged if the run changed its state.
+func (registry *Registry) publishResult(name string, rc *registeredCheck, res Result, failures int) {
+	state := res.State()
+	rc.mu.Lock()
+	previous := rc.state
+	if previous == "" {
+		previous = StateHealthy
+	}
+	rc.state = state
+	rc.mu.Unlock()
+
+	if !registry.events.active() {
+		return
+	}
+	event := Event{
+		Type:     CheckCompleted,
+		Check:    name,
+		Time:     res.CheckedAt,
+		Healthy:  res.Error == nil,
+		State:    state,
+		Code:     res.Code,
+		Message:  res.Message,
+		Error:    res.Error,
+		Duration: res.Duration,
+	}
+	if res.Error != nil {
+		event.Failures = failures
+	}
+	registry.events.publish(event)
+	if state != previous {
+		event.Type = StateChanged
+		event.PreviousState = previous
+		registry.events.publish(event)
+	}
+}
@@ -0,0 +1,105 @@
+package health
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+// eventRecorder is a Sink recording the events it receives.
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (r *eventRecorder) Handle(event Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+// types returns the types of the received events, and clears them.
+func (r *eventRecorder) types() []EventType {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var types []EventType
+	for _, event := range r.events {
+		types = append(types, event.Type)
+	}
+	r.events = nil
+	return types
+}
+
+func TestEvents(t *testing.T) {
+	registry := NewRegistry()
+	updater := NewStatusUpdater()
+	registry.Register("database", updater)
+
+	sink := &eventRecorder{}
+	registry.AddSink(sink)
+
+	assertTypes := func(expected ...EventType) {
+		t.Helper()
+		types := sink.types()
+		if len(types) != len(expected) {
+			t.Fatalf("unexpected events: %v != %v", types, expected)
+		}
+		for i := range types {
+			if types[i] != expected[i] {
+				t.Fatalf("unexpected events: %v != %v", types, expected)
+			}
+		}
+	}
+
+	registry.CheckStatus()
+	assertTypes(CheckStarted, CheckCompleted)
+
+	updater.Update(Result{Error: errors.New("connection refused")})
+	sink.mu.Lock()
+	sink.events = nil
+	sink.mu.Unlock()
+	registry.CheckStatus()
+	sink.mu.Lock()
+	changed := sink.events[len(sink.events)-1]
+	sink.mu.Unlock()
+	if changed.Check != "database" || changed.State != StateUnhealthy || changed.PreviousState != StateHealthy {
+		t.Errorf("unexpected state change: %+v", changed)
+	}
+	if changed.Error == nil || changed.Failures != 1 {
+		t.Errorf("state change misses the failure: %+v", changed)
+	}
+	assertTypes(CheckStarted, CheckCompleted, StateChanged)
+
+	registry.CheckStatus()
+	assertTypes(CheckStarted, CheckCompleted)
+
+	updater.Update(Result{})
+	registry.CheckStatus()
+	assertTypes(CheckStarted, CheckCompleted, StateChanged)
+
+	registry.Close()
+	assertTypes(RegistryClosed)
+}
+
+func TestEventsDegraded(t *testing.T) {
+	registry := NewRegistry()
+	degraded := false
+	registry.Register("cache", CheckFunc(func() Result {
+		return Result{Degraded: degraded}
+	}))
+	registry.CheckStatus()
+
+	var changes []Event
+	registry.AddSink(SinkFunc(func(event Event) {
+		if event.Type == StateChanged {
+			changes = append(changes, event)
+		}
+	}))
+	degraded = true
+	registry.CheckStatus()
+	registry.CheckStatus()
+
+	if len(changes) != 1 || changes[0].State != StateDegraded || !changes[0].Healthy {
+		t.Errorf("unexpected state changes: %+v", changes)
+	}
+}
@@ -0,0 +1,23 @@
+package health
+
+import "expvar"
+
+// DefaultExpvarName is the name PublishExpvar publishes the status of the
+// default registry under.
+const DefaultExpvarName = "health.status"
+
+// PublishExpvar publishes the status of the checks in the registry as the
+// expvar variable name, so it is served by /debug/vars. The checks are
+// evaluated every time the variable is read. Like expvar.Publish, it panics
+// if name is already published.
+func (registry *Registry) PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return registry.CheckStatus()
+	}))
+}
+
+// PublishExpvar publishes the status of the checks in the default registry as
+// the expvar variable DefaultExpvarName.
+func PublishExpvar() {
+	Default().PublishExpvar(DefaultExpvarName)
+}
@@ -0,0 +1,42 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"strconv"
+	"testing"
+)
+
+// expvarRuns makes the variable names of repeated test runs unique, as
+// variables can not be unpublished.
+var expvarRuns int
+
+// TestPublishExpvar ensures the published variable reflects the current
+// status of the checks.
+func TestPublishExpvar(t *testing.T) {
+	registry := NewRegistry()
+	updater := NewStatusUpdater()
+	registry.Register("check", updater)
+	expvarRuns++
+	name := "health.test." + strconv.Itoa(expvarRuns)
+	registry.PublishExpvar(name)
+
+	v := expvar.Get(name)
+	if v == nil {
+		t.Fatalf("variable was not published")
+	}
+
+	for _, healthy := range []bool{true, false} {
+		if !healthy {
+			updater.Update(Result{Error: errors.New("failing")})
+		}
+		var status Status
+		if err := json.Unmarshal([]byte(v.String()), &status); err != nil {
+			t.Fatalf("error decoding variable %q: %v", v.String(), err)
+		}
+		if status["check"].Healthy != healthy {
+			t.Errorf("expected healthy to be %v, got %+v", healthy, status)
+		}
+	}
+}
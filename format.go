@@ -0,0 +1,128 @@
+package health
+
+import (
+	"bytes"
+	"html/template"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// negotiate returns the offered media type the request's Accept header
+// prefers. Ties are broken by the order of the offers, and the first offer is
+// returned if the request does not accept any of them.
+func negotiate(r *http.Request, offers ...string) string {
+	header := strings.Join(r.Header.Values("Accept"), ",")
+	if header == "" {
+		return offers[0]
+	}
+
+	best, bestQ := offers[0], 0.0
+	for _, offer := range offers {
+		q, specificity := 0.0, -1
+		for _, part := range strings.Split(header, ",") {
+			mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err != nil {
+				continue
+			}
+			s := matchMediaType(mt, offer)
+			if s <= specificity {
+				continue
+			}
+			pq := 1.0
+			if v, ok := params["q"]; ok {
+				if pq, err = strconv.ParseFloat(v, 64); err != nil {
+					continue
+				}
+			}
+			q, specificity = pq, s
+		}
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
+
+// matchMediaType returns how specifically the media range mt matches offer:
+// 2 for an exact match, 1 for a type wildcard, 0 for */* and -1 for no match.
+func matchMediaType(mt, offer string) int {
+	switch {
+	case mt == offer:
+		return 2
+	case mt == "*/*":
+		return 0
+	case strings.HasSuffix(mt, "/*") && strings.HasPrefix(offer, strings.TrimSuffix(mt, "*")):
+		return 1
+	}
+	return -1
+}
+
+// sortedNames returns the names of the checks in order.
+func sortedNames(checks Status) []string {
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// textResponse responds with a short plain text summary: the aggregate status
+// on the first line, followed by a line per check.
+func textResponse(w http.ResponseWriter, logger Logger, status int, checks Status, overall string) {
+	var buf bytes.Buffer
+	buf.WriteString(overall + "\n")
+	for _, name := range sortedNames(checks) {
+		check := checks[name]
+		buf.WriteString(name + ": " + check.Status())
+		if check.Message != "" {
+			buf.WriteString(" " + check.Message)
+		}
+		buf.WriteString("\n")
+	}
+	writeResponse(w, logger, status, "text/plain; charset=utf-8", buf.Bytes())
+}
+
+// htmlTemplate renders the status of the checks as a table.
+var htmlTemplate = template.Must(template.New("health").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Health: {{.Status}}</title></head>
+<body>
+<h1>Health: {{.Status}}</h1>
+<table>
+<tr><th>Check</th><th>Status</th><th>Message</th><th>Duration (ms)</th><th>Last checked</th></tr>
+{{range .Checks}}<tr><td>{{.Name}}</td><td>{{.Status}}</td><td>{{.Message}}</td><td>{{printf "%.3f" .DurationMs}}</td><td>{{.LastChecked.Format "2006-01-02T15:04:05Z07:00"}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// htmlResponse responds with a simple HTML page for humans.
+func htmlResponse(w http.ResponseWriter, logger Logger, status int, checks Status, overall string) {
+	type namedCheck struct {
+		Name   string
+		Status string
+		HealthCheck
+	}
+	data := struct {
+		Status string
+		Checks []namedCheck
+	}{
+		Status: overall,
+	}
+	for _, name := range sortedNames(checks) {
+		check := checks[name]
+		data.Checks = append(data.Checks, namedCheck{Name: name, Status: check.Status(), HealthCheck: check})
+	}
+
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, data); err != nil {
+		logger.Printf("error rendering health status page: %v", err)
+		http.Error(w, "Could not render health status", http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, logger, status, "text/html; charset=utf-8", buf.Bytes())
+}
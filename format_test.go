@@ -0,0 +1,73 @@
+package health
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestNegotiate ensures the preferred offer is picked from Accept headers
+// sent by common clients.
+func TestNegotiate(t *testing.T) {
+	offers := []string{"application/json", HealthJSONContentType, "text/html", "text/plain"}
+	for accept, expected := range map[string]string{
+		"":                                   "application/json",
+		"*/*":                                "application/json",
+		"text/plain":                         "text/plain",
+		"text/*":                             "text/html",
+		"application/health+json":            HealthJSONContentType,
+		"text/plain;q=0.5, application/json": "application/json",
+		"application/json;q=0.1, text/plain": "text/plain",
+		"image/png":                          "application/json",
+		"text/html,application/xhtml+xml,*/*;q=0.8": "text/html",
+	} {
+		req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+		if err != nil {
+			t.Fatalf("Failed to create request.")
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		if offer := negotiate(req, offers...); offer != expected {
+			t.Errorf("%q: unexpected offer: %q != %q", accept, offer, expected)
+		}
+	}
+}
+
+// TestTextAndHTMLResponses ensures plain text and HTML are served when asked
+// for.
+func TestTextAndHTMLResponses(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("cache", CheckFunc(func() Result {
+		return Result{}
+	}))
+	registry.Register("database", CheckFunc(func() Result {
+		return Result{Error: errors.New("failing"), Message: "<connection refused>"}
+	}))
+
+	get := func(accept string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+		if err != nil {
+			t.Fatalf("Failed to create request.")
+		}
+		req.Header.Set("Accept", accept)
+		recorder := httptest.NewRecorder()
+		registry.StatusHandler(recorder, req)
+		if recorder.Code != http.StatusServiceUnavailable {
+			t.Errorf("%s: Did not get a 503.", accept)
+		}
+		return recorder
+	}
+
+	text := get("text/plain").Body.String()
+	if expected := "fail\ncache: pass\ndatabase: fail <connection refused>\n"; text != expected {
+		t.Errorf("unexpected text response: %q != %q", text, expected)
+	}
+
+	html := get("text/html").Body.String()
+	if !strings.Contains(html, "<td>database</td><td>fail</td><td>&lt;connection refused&gt;</td>") {
+		t.Errorf("unexpected html response: %s", html)
+	}
+}
@@ -0,0 +1,16 @@
+package health
+
+// ResultFormatter converts the status of the checks into the body of JSON
+// responses, to follow a schema the handler does not know about. The returned
+// value is serialized with encoding/json.
+type ResultFormatter func(status StatusEnvelope) any
+
+// WithResultFormatter makes the handler respond with the result of formatter
+// instead of the bare map of checks or the StatusEnvelope. Only JSON
+// responses are affected, health+json, HTML, text and XML responses keep
+// their format. The checks are redacted before they are passed to formatter.
+func WithResultFormatter(formatter ResultFormatter) HandlerOption {
+	return func(c *handlerConfig) {
+		c.formatter = formatter
+	}
+}
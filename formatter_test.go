@@ -0,0 +1,72 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestResultFormatter ensures JSON responses are built by the formatter.
+func TestResultFormatter(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("database", CheckFunc(func() Result {
+		return Result{Error: errors.New("secret dsn")}
+	}), Sensitive())
+	registry.Register("cache", NewStatusUpdater())
+
+	type component struct {
+		Name string `json:"componentName"`
+		Up   bool   `json:"isUp"`
+		Info string `json:"info,omitempty"`
+	}
+	formatter := func(status StatusEnvelope) any {
+		var components []component
+		for _, name := range sortedNames(status.Checks) {
+			check := status.Checks[name]
+			components = append(components, component{Name: name, Up: check.Healthy, Info: check.Message})
+		}
+		return map[string]any{
+			"serviceStatus": status.Status,
+			"components":    components,
+		}
+	}
+
+	req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+	if err != nil {
+		t.Errorf("Failed to create request.")
+	}
+	recorder := httptest.NewRecorder()
+	registry.Handler(WithResultFormatter(formatter)).ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("unexpected status code: %d", recorder.Code)
+	}
+
+	var body struct {
+		ServiceStatus string      `json:"serviceStatus"`
+		Components    []component `json:"components"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("error decoding response: %v", err)
+	}
+	expected := []component{
+		{Name: "cache", Up: true},
+		{Name: "database", Up: false, Info: RedactedMessage},
+	}
+	if body.ServiceStatus != StatusFail || len(body.Components) != len(expected) {
+		t.Fatalf("unexpected response: %s", recorder.Body.String())
+	}
+	for i := range expected {
+		if body.Components[i] != expected[i] {
+			t.Errorf("unexpected component %d: %+v != %+v", i, body.Components[i], expected[i])
+		}
+	}
+
+	req.Header.Set("Accept", "text/plain")
+	recorder = httptest.NewRecorder()
+	registry.Handler(WithResultFormatter(formatter)).ServeHTTP(recorder, req)
+	if ct := recorder.Header().Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+		t.Errorf("expected text responses not to be formatted, got %q", ct)
+	}
+}
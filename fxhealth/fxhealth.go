@@ -0,0 +1,112 @@
+// Package fxhealth integrates the health package with the fx dependency
+// injection framework.
+//
+// Module supplies a *health.Registry holding the checks of every
+// health.CheckProvider in the application, mounts its status handlers on the
+// *http.ServeMux of the application, if there is one, and closes it when the
+// application stops.
+//
+//	fx.New(
+//	  fxhealth.Module,
+//	  fx.Provide(fxhealth.AsCheckProvider(database.New)),
+//	  fx.Supply(fxhealth.AsRegistryOption(health.WithDefaultTimeout(time.Second))),
+//	)
+package fxhealth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/docker/distribution/health"
+	"go.uber.org/fx"
+)
+
+// Value groups collecting the check providers and registry options of the
+// application.
+const (
+	ProvidersGroup = "health.providers"
+	OptionsGroup   = "health.options"
+)
+
+// Module supplies a *health.Registry, see NewRegistry, and mounts its status
+// handlers, see Mount.
+var Module = fx.Module("health",
+	fx.Provide(NewRegistry),
+	fx.Invoke(Mount),
+)
+
+// RegistryParams are the dependencies of NewRegistry.
+type RegistryParams struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+	Providers []health.CheckProvider  `group:"health.providers"`
+	Options   []health.RegistryOption `group:"health.options"`
+}
+
+// NewRegistry returns a registry created with the options of the
+// OptionsGroup, holding the checks of the providers of the ProvidersGroup. It
+// fails if two checks have the same name. The registry, with its scheduler,
+// is closed when the application stops.
+func NewRegistry(p RegistryParams) (*health.Registry, error) {
+	registry := health.NewRegistry(p.Options...)
+	for _, provider := range p.Providers {
+		checks := provider.HealthChecks()
+		names := make([]string, 0, len(checks))
+		for name := range checks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if err := registry.TryRegister(name, checks[name]); err != nil {
+				registry.Close()
+				return nil, fmt.Errorf("fxhealth: %w", err)
+			}
+		}
+	}
+	p.Lifecycle.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			return registry.Close()
+		},
+	})
+	return registry, nil
+}
+
+// MountParams are the dependencies of Mount.
+type MountParams struct {
+	fx.In
+
+	Registry *health.Registry
+	Mux      *http.ServeMux `optional:"true"`
+}
+
+// Mount mounts the status handlers of the registry on the *http.ServeMux of
+// the application, see Registry.RegisterRoutes. It does nothing if the
+// application provides no *http.ServeMux.
+func Mount(p MountParams) {
+	if p.Mux != nil {
+		p.Registry.RegisterRoutes(p.Mux)
+	}
+}
+
+// AsCheckProvider annotates constructor, returning a type implementing
+// health.CheckProvider, so that its result is added to the ProvidersGroup
+// instead of being provided as its own type.
+//
+//	fx.Provide(fxhealth.AsCheckProvider(database.New))
+func AsCheckProvider(constructor any) any {
+	return fx.Annotate(constructor,
+		fx.As(new(health.CheckProvider)),
+		fx.ResultTags(`group:"`+ProvidersGroup+`"`),
+	)
+}
+
+// AsRegistryOption annotates opt so that it is added to the OptionsGroup when
+// supplied.
+//
+//	fx.Supply(fxhealth.AsRegistryOption(health.WithDefaultTimeout(time.Second)))
+func AsRegistryOption(opt health.RegistryOption) any {
+	return fx.Annotate(opt, fx.ResultTags(`group:"`+OptionsGroup+`"`))
+}
@@ -0,0 +1,73 @@
+package fxhealth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/distribution/health"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+// provider is a library exposing checks.
+type provider map[string]health.Checker
+
+// HealthChecks implements the health.CheckProvider interface
+func (p provider) HealthChecks() map[string]health.Checker {
+	return p
+}
+
+func healthy() health.Checker {
+	return health.CheckFunc(func() health.Result { return health.Result{} })
+}
+
+func TestModule(t *testing.T) {
+	var (
+		registry *health.Registry
+		mux      = http.NewServeMux()
+	)
+	app := fxtest.New(t,
+		Module,
+		fx.Supply(mux),
+		fx.Provide(AsCheckProvider(func() provider { return provider{"database": healthy()} })),
+		fx.Provide(AsCheckProvider(func() provider { return provider{"cache": healthy()} })),
+		fx.Supply(AsRegistryOption(health.WithDefaultTimeout(time.Second))),
+		fx.Populate(&registry),
+	)
+	app.RequireStart()
+
+	if status := registry.CheckStatus(); len(status) != 2 || !status["database"].Healthy || !status["cache"].Healthy {
+		t.Errorf("unexpected status: %+v", status)
+	}
+
+	req, err := http.NewRequest("GET", "https://fakeurl.com"+health.ReadyzPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to create request.")
+	}
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), "database") {
+		t.Errorf("unexpected response from the mounted handler: %d %s", recorder.Code, recorder.Body)
+	}
+
+	app.RequireStop()
+	if err := registry.Close(); err != nil {
+		t.Errorf("unexpected error closing the registry again: %v", err)
+	}
+}
+
+func TestModuleDuplicateCheck(t *testing.T) {
+	app := fx.New(
+		Module,
+		fx.Provide(AsCheckProvider(func() provider { return provider{"database": healthy()} })),
+		fx.Provide(AsCheckProvider(func() provider { return provider{"database": healthy()} })),
+		fx.NopLogger,
+	)
+	if err := app.Err(); !errors.Is(err, health.ErrAlreadyRegistered) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
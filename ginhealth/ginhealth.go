@@ -0,0 +1,37 @@
+// Package ginhealth exposes the status handlers of a health.Registry as
+// gin handlers.
+//
+//	router := gin.New()
+//	ginhealth.RegisterRoutes(router, registry)
+package ginhealth
+
+import (
+	"github.com/docker/distribution/health"
+	"github.com/gin-gonic/gin"
+)
+
+// Status returns a handler serving the status of all checks in the registry,
+// see Registry.Handler.
+func Status(registry *health.Registry, opts ...health.HandlerOption) gin.HandlerFunc {
+	return gin.WrapH(registry.Handler(opts...))
+}
+
+// Liveness returns a handler serving the status of the Liveness checks in
+// the registry.
+func Liveness(registry *health.Registry, opts ...health.HandlerOption) gin.HandlerFunc {
+	return Status(registry, append(opts, health.ForKind(health.Liveness))...)
+}
+
+// Readiness returns a handler serving the status of the Readiness checks in
+// the registry.
+func Readiness(registry *health.Registry, opts ...health.HandlerOption) gin.HandlerFunc {
+	return Status(registry, append(opts, health.ForKind(health.Readiness))...)
+}
+
+// RegisterRoutes mounts the status handlers of the registry on routes at
+// health.HealthzPath, health.ReadyzPath and health.LivezPath.
+func RegisterRoutes(routes gin.IRoutes, registry *health.Registry, opts ...health.HandlerOption) {
+	routes.GET(health.HealthzPath, Status(registry, opts...))
+	routes.GET(health.ReadyzPath, Readiness(registry, opts...))
+	routes.GET(health.LivezPath, Liveness(registry, opts...))
+}
@@ -0,0 +1,39 @@
+package health
+
+import "time"
+
+// WithGracePeriod reports failing checks as initializing for grace after the
+// registry is created, giving dependencies time to warm up. Initializing
+// checks are reported like failing Warning checks, so status handlers keep
+// responding 200 OK, or the code set with WithInitializingStatus, instead of
+// making an orchestrator restart the service.
+func WithGracePeriod(grace time.Duration) RegistryOption {
+	return func(registry *Registry) {
+		registry.grace = grace
+	}
+}
+
+// WithInitializingStatus sets the status code of responses with checks that
+// fail during the registry's grace period, and no other failing critical
+// checks. The default is 200 OK.
+func WithInitializingStatus(code int) HandlerOption {
+	return func(c *handlerConfig) {
+		c.initializingStatus = code
+	}
+}
+
+// initializing reports whether now is within the grace period of the
+// registry.
+func (registry *Registry) initializing(now time.Time) bool {
+	return registry.grace > 0 && now.Sub(registry.created) < registry.grace
+}
+
+// initializing reports whether any of the checks is initializing.
+func initializing(checks Status) bool {
+	for _, check := range checks {
+		if check.Initializing {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package health
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestGracePeriod ensures failing checks are reported as initializing during
+// the grace period only.
+func TestGracePeriod(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	registry := NewRegistry(WithClock(clock), WithGracePeriod(time.Minute))
+	registry.Register("check", CheckFunc(func() Result {
+		return Result{Error: errors.New("connection refused")}
+	}))
+
+	get := func(opts ...HandlerOption) int {
+		req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+		if err != nil {
+			t.Fatalf("Failed to create request.")
+		}
+		recorder := httptest.NewRecorder()
+		registry.Handler(opts...).ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	if check := registry.CheckStatus()["check"]; !check.Initializing || check.Status() != StatusWarn {
+		t.Errorf("expected check to be initializing, got %+v", check)
+	}
+	if code := get(); code != http.StatusOK {
+		t.Errorf("expected status %d during grace period, got %d", http.StatusOK, code)
+	}
+	if code := get(WithInitializingStatus(http.StatusAccepted)); code != http.StatusAccepted {
+		t.Errorf("expected status %d during grace period, got %d", http.StatusAccepted, code)
+	}
+
+	clock.Add(time.Minute)
+	if check := registry.CheckStatus()["check"]; check.Initializing {
+		t.Errorf("expected check not to be initializing after the grace period, got %+v", check)
+	}
+	if code := get(); code != http.StatusServiceUnavailable {
+		t.Errorf("expected status %d after grace period, got %d", http.StatusServiceUnavailable, code)
+	}
+}
@@ -0,0 +1,129 @@
+// Package grpchealth implements the gRPC Health Checking Protocol
+// (grpc.health.v1.Health) on top of a health.Registry, so gRPC services can
+// reuse the checks they already register for their HTTP status endpoint.
+//
+// The empty service name reports the status of every check in the registry.
+// Any other service name reports the status of the checks mapped to it with
+// WithService, or of the check registered under that exact name.
+//
+//	s := grpc.NewServer()
+//	grpc_health_v1.RegisterHealthServer(s, grpchealth.NewServer(health.DefaultRegistry,
+//	  grpchealth.WithService("my.package.Storage", "database", "cache")))
+package grpchealth
+
+import (
+	"context"
+	"time"
+
+	"github.com/docker/distribution/health"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultWatchInterval is how often Watch re-evaluates the checks of a
+// service when no WithWatchInterval option is given.
+const DefaultWatchInterval = 5 * time.Second
+
+// Server implements healthpb.HealthServer backed by a health.Registry.
+type Server struct {
+	healthpb.UnimplementedHealthServer
+
+	registry      *health.Registry
+	services      map[string][]string
+	watchInterval time.Duration
+}
+
+// Option configures a Server created by NewServer.
+type Option func(*Server)
+
+// WithService maps the gRPC service name to the named checks. The service is
+// serving only while all of them are healthy.
+func WithService(service string, checks ...string) Option {
+	return func(s *Server) {
+		s.services[service] = checks
+	}
+}
+
+// WithWatchInterval sets how often Watch re-evaluates the checks of the
+// watched service.
+func WithWatchInterval(d time.Duration) Option {
+	return func(s *Server) {
+		s.watchInterval = d
+	}
+}
+
+// NewServer returns a Server reporting the status of the checks in registry.
+func NewServer(registry *health.Registry, opts ...Option) *Server {
+	s := &Server{
+		registry:      registry,
+		services:      make(map[string][]string),
+		watchInterval: DefaultWatchInterval,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Check implements healthpb.HealthServer. It returns a NotFound error for
+// unknown services.
+func (s *Server) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	st, ok := s.servingStatus(ctx, req.GetService())
+	if !ok {
+		return nil, status.Error(codes.NotFound, "unknown service")
+	}
+	return &healthpb.HealthCheckResponse{Status: st}, nil
+}
+
+// Watch implements healthpb.HealthServer. It sends the status of the service
+// right away and then every time it changes, until the client goes away.
+// Unknown services are reported as SERVICE_UNKNOWN.
+func (s *Server) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	ctx := stream.Context()
+	t := time.NewTicker(s.watchInterval)
+	defer t.Stop()
+
+	last := healthpb.HealthCheckResponse_ServingStatus(-1)
+	for {
+		st, ok := s.servingStatus(ctx, req.GetService())
+		if !ok {
+			st = healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+		}
+		if st != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: st}); err != nil {
+				return err
+			}
+			last = st
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-t.C:
+		}
+	}
+}
+
+// servingStatus evaluates the checks of service. It returns false if the
+// service is unknown.
+func (s *Server) servingStatus(ctx context.Context, service string) (healthpb.HealthCheckResponse_ServingStatus, bool) {
+	var checks health.Status
+	if service == "" {
+		checks = s.registry.CheckStatusContext(ctx)
+	} else {
+		names, ok := s.services[service]
+		if !ok {
+			names = []string{service}
+		}
+		checks = s.registry.CheckStatusNamed(ctx, names...)
+		if len(checks) == 0 {
+			return healthpb.HealthCheckResponse_SERVICE_UNKNOWN, false
+		}
+	}
+
+	if checks.Overall() == health.StatusFail {
+		return healthpb.HealthCheckResponse_NOT_SERVING, true
+	}
+	return healthpb.HealthCheckResponse_SERVING, true
+}
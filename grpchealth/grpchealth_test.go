@@ -0,0 +1,46 @@
+package grpchealth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/docker/distribution/health"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestCheck(t *testing.T) {
+	registry := health.NewRegistry()
+	registry.Register("database", health.CheckFunc(func() health.Result {
+		return health.Result{}
+	}))
+	registry.Register("cache", health.CheckFunc(func() health.Result {
+		return health.Result{Error: errors.New("cache unavailable")}
+	}))
+
+	s := NewServer(registry,
+		WithService("storage", "database"),
+		WithService("frontend", "database", "cache"))
+
+	for service, expected := range map[string]healthpb.HealthCheckResponse_ServingStatus{
+		"":         healthpb.HealthCheckResponse_NOT_SERVING,
+		"storage":  healthpb.HealthCheckResponse_SERVING,
+		"frontend": healthpb.HealthCheckResponse_NOT_SERVING,
+		"database": healthpb.HealthCheckResponse_SERVING,
+	} {
+		resp, err := s.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", service, err)
+		}
+		if resp.GetStatus() != expected {
+			t.Errorf("%q: unexpected status: %v != %v", service, resp.GetStatus(), expected)
+		}
+	}
+
+	_, err := s.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "unknown"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound for unknown service, got %v", err)
+	}
+}
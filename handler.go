@@ -0,0 +1,276 @@
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// StatusPass is the aggregate status of a response whose checks are all
+	// healthy.
+	StatusPass = "pass"
+	// StatusWarn is the aggregate status of a response whose failing checks
+	// all have Warning severity, or with degraded checks.
+	StatusWarn = "warn"
+	// StatusFail is the aggregate status of a response with failing critical
+	// checks.
+	StatusFail = "fail"
+)
+
+// StatusEnvelope is the response body of handlers created with the
+// WithEnvelope option.
+type StatusEnvelope struct {
+	// Status is StatusPass, StatusWarn or StatusFail.
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+	// Score is the health of the service between 0 and 100, see
+	// Registry.Score.
+	Score float64 `json:"score"`
+	// Build is the build of the service, see SetBuildInfo.
+	Build *BuildInfo `json:"build,omitempty"`
+	// Checks is last, so streamed responses can write the other fields
+	// before the checks.
+	Checks Status `json:"checks"`
+}
+
+// handlerConfig holds the configuration of a status handler.
+type handlerConfig struct {
+	// include selects the checks the handler evaluates.
+	include func(*registeredCheck) bool
+
+	envelope   bool
+	healthJSON bool
+	release    releaseInfo
+	// formatter converts the status to the body of JSON responses when set.
+	formatter ResultFormatter
+	// protobuf offers ProtobufContentType responses.
+	protobuf bool
+	// etag tags responses and answers conditional requests.
+	etag bool
+	// encoders compress responses of at least compressMinSize bytes, in
+	// order of preference.
+	encoders        []Encoder
+	compressMinSize int
+
+	// authorizers must all accept a request before it is served.
+	authorizers []authorizer
+	minimal     bool
+	redact      bool
+	// availability keeps the Availability of the checks in responses.
+	availability bool
+
+	// agents serve the requests of matching User-Agents.
+	agents []*probeAgent
+
+	// successStatus, failureStatus, degradedStatus and drainingStatus are
+	// the status codes of responses with StatusPass, StatusFail, StatusWarn
+	// and while draining. initializingStatus replaces degradedStatus while
+	// checks are initializing.
+	successStatus      int
+	failureStatus      int
+	degradedStatus     int
+	drainingStatus     int
+	initializingStatus int
+}
+
+// HandlerOption configures a handler created by Registry.Handler.
+type HandlerOption func(*handlerConfig)
+
+// WithEnvelope wraps the response in a StatusEnvelope carrying the aggregate
+// status, instead of responding with the bare map of checks.
+func WithEnvelope() HandlerOption {
+	return func(c *handlerConfig) {
+		c.envelope = true
+	}
+}
+
+// WithFailureStatus sets the status code of responses with failing critical
+// checks. The default is 503 Service Unavailable.
+func WithFailureStatus(code int) HandlerOption {
+	return func(c *handlerConfig) {
+		c.failureStatus = code
+	}
+}
+
+// WithDegradedStatus sets the status code of responses whose failing checks
+// all have Warning severity, or with degraded checks. The default is 200 OK.
+func WithDegradedStatus(code int) HandlerOption {
+	return func(c *handlerConfig) {
+		c.degradedStatus = code
+	}
+}
+
+// WithDrainingStatus sets the status code of failing responses while the
+// registry is draining, e.g. 429 Too Many Requests. By default the failure
+// status code is used.
+func WithDrainingStatus(code int) HandlerOption {
+	return func(c *handlerConfig) {
+		c.drainingStatus = code
+	}
+}
+
+// statusHandler serves the status of the checks of a registry.
+type statusHandler struct {
+	registry *Registry
+	handlerConfig
+}
+
+// Handler returns an http.Handler serving the status of the checks in the
+// registry, configured by opts. Without options it behaves like
+// StatusHandler.
+func (registry *Registry) Handler(opts ...HandlerOption) http.Handler {
+	h := &statusHandler{
+		registry: registry,
+		handlerConfig: handlerConfig{
+			include:            func(*registeredCheck) bool { return true },
+			successStatus:      http.StatusOK,
+			failureStatus:      http.StatusServiceUnavailable,
+			degradedStatus:     http.StatusOK,
+			initializingStatus: http.StatusOK,
+		},
+	}
+	for _, opt := range opts {
+		opt(&h.handlerConfig)
+	}
+	for _, agent := range h.agents {
+		agentOpts := append(append([]HandlerOption(nil), opts...), agent.opts...)
+		agentOpts = append(agentOpts, func(c *handlerConfig) { c.agents = nil })
+		agent.handler = registry.Handler(agentOpts...)
+	}
+	return h
+}
+
+// ServeHTTP implements http.Handler
+func (h *statusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if agent := h.agent(r); agent != nil {
+		agent.ServeHTTP(w, r)
+		return
+	}
+	if len(h.encoders) > 0 {
+		cw := newCompressWriter(w, r, h.encoders, h.compressMinSize)
+		defer cw.Close()
+		w = cw
+	}
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method == "GET" {
+		include, unknown := h.selection(r)
+		if unknown != "" {
+			http.Error(w, "unknown check: "+unknown, http.StatusNotFound)
+			return
+		}
+		checks := redact(h.registry.checkStatus(r.Context(), include), h.redact)
+		if !h.availability {
+			for name, check := range checks {
+				check.Availability = nil
+				checks[name] = check
+			}
+		}
+		overall := overallStatus(checks)
+
+		status := h.successStatus
+		switch overall {
+		case StatusFail:
+			status = h.failureStatus
+			if h.drainingStatus != 0 && h.registry.Draining() {
+				status = h.drainingStatus
+			}
+		case StatusWarn:
+			status = h.degradedStatus
+			if initializing(checks) {
+				status = h.initializingStatus
+			}
+		}
+
+		variant := minimalVariant
+		if !h.minimal {
+			jsonType := "application/json"
+			if h.healthJSON {
+				jsonType = HealthJSONContentType
+			}
+			offers := []string{jsonType, HealthJSONContentType, "text/html", "text/plain", "application/xml", "text/xml"}
+			if h.protobuf {
+				offers = append(offers, ProtobufContentType)
+			}
+			variant = negotiate(r, offers...)
+		}
+		if h.etag && notModified(w, r, checks, overall, status, variant) {
+			return
+		}
+
+		switch variant {
+		case minimalVariant:
+			writeResponse(w, h.registry.log(), status, "text/plain; charset=utf-8", []byte(overall+"\n"))
+		case ProtobufContentType:
+			writeResponse(w, h.registry.log(), status, ProtobufContentType, marshalProtobuf(checks, overall, h.registry.clock.Now()))
+		case HealthJSONContentType:
+			statusResponse(w, r, h.registry.log(), status, HealthJSONContentType, newHealthJSONResponse(checks, overall, h.release))
+		case "text/html":
+			htmlResponse(w, h.registry.log(), status, checks, overall)
+		case "text/plain":
+			textResponse(w, h.registry.log(), status, checks, overall)
+		case "application/xml", "text/xml":
+			xmlResponse(w, h.registry.log(), status, checks, overall, h.registry.clock.Now())
+		default:
+			if !h.envelope && h.formatter == nil {
+				streamResponse(w, h.registry.log(), status, checks, nil)
+				return
+			}
+			envelope := StatusEnvelope{
+				Status:    overall,
+				Checks:    checks,
+				Timestamp: h.registry.clock.Now().UTC(),
+				Score:     score(checks),
+				Build:     currentBuildInfo(),
+			}
+			if h.formatter != nil {
+				statusResponse(w, r, h.registry.log(), status, "application/json; charset=utf-8", h.formatter(envelope))
+				return
+			}
+			streamResponse(w, h.registry.log(), status, checks, &envelope)
+		}
+	} else {
+		http.NotFound(w, r)
+	}
+}
+
+// statusResponse completes the request with a response describing the health
+// of the service.
+func statusResponse(w http.ResponseWriter, r *http.Request, logger Logger, status int, contentType string, body any) {
+	p, err := json.Marshal(body)
+	if err != nil {
+		serializationFailed(w, logger, contentType, err)
+		return
+	}
+
+	writeResponse(w, logger, status, contentType, p)
+}
+
+// serializationFailed completes the request with an error, after the health
+// status failed to serialize with err.
+func serializationFailed(w http.ResponseWriter, logger Logger, contentType string, err error) {
+	logger.Printf("error serializing health status: %v", err)
+	p, err := json.Marshal(struct {
+		ServerError string `json:"server_error"`
+	}{
+		ServerError: "Could not parse error message",
+	})
+	if err != nil {
+		logger.Printf("error serializing health status failure message: %v", err)
+		return
+	}
+	writeResponse(w, logger, http.StatusInternalServerError, contentType, p)
+}
+
+// writeResponse writes the serialized response p.
+func writeResponse(w http.ResponseWriter, logger Logger, status int, contentType string, p []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", fmt.Sprint(len(p)))
+	w.WriteHeader(status)
+	if _, err := w.Write(p); err != nil {
+		logger.Printf("error writing health status response body: %v", err)
+	}
+}
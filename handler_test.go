@@ -0,0 +1,94 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestHandlerEnvelope ensures the envelope carries the aggregate status.
+func TestHandlerEnvelope(t *testing.T) {
+	registry := NewRegistry()
+	updater := NewStatusUpdater()
+	registry.Register("check", updater)
+	handler := registry.Handler(WithEnvelope())
+
+	get := func() (int, StatusEnvelope) {
+		req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+		if err != nil {
+			t.Fatalf("Failed to create request.")
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+
+		var env StatusEnvelope
+		if err := json.Unmarshal(recorder.Body.Bytes(), &env); err != nil {
+			t.Fatalf("error decoding envelope: %v", err)
+		}
+		return recorder.Code, env
+	}
+
+	code, env := get()
+	if code != http.StatusOK || env.Status != StatusPass || len(env.Checks) != 1 || env.Timestamp.IsZero() {
+		t.Errorf("unexpected passing response: %d %+v", code, env)
+	}
+
+	updater.Update(Result{Error: errors.New("failing")})
+	code, env = get()
+	if code != http.StatusServiceUnavailable || env.Status != StatusFail || env.Checks["check"].Healthy {
+		t.Errorf("unexpected failing response: %d %+v", code, env)
+	}
+}
+
+// TestHandlerStatusCodes ensures the status codes of the handler can be
+// configured.
+func TestHandlerStatusCodes(t *testing.T) {
+	registry := NewRegistry()
+	critical := NewStatusUpdater()
+	warning := NewStatusUpdater()
+	registry.Register("critical", critical)
+	registry.Register("warning", warning, WithSeverity(Warning))
+
+	get := func(opts ...HandlerOption) int {
+		req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+		if err != nil {
+			t.Fatalf("Failed to create request.")
+		}
+		recorder := httptest.NewRecorder()
+		registry.Handler(opts...).ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+	opts := []HandlerOption{
+		WithFailureStatus(http.StatusInternalServerError),
+		WithDegradedStatus(http.StatusMultiStatus),
+		WithDrainingStatus(http.StatusTooManyRequests),
+	}
+
+	warning.Update(Result{Error: errors.New("failing")})
+	if code := get(); code != http.StatusOK {
+		t.Errorf("expected degraded status %d by default, got %d", http.StatusOK, code)
+	}
+	if code := get(opts...); code != http.StatusMultiStatus {
+		t.Errorf("expected degraded status %d, got %d", http.StatusMultiStatus, code)
+	}
+
+	critical.Update(Result{Error: errors.New("failing")})
+	if code := get(); code != http.StatusServiceUnavailable {
+		t.Errorf("expected failure status %d by default, got %d", http.StatusServiceUnavailable, code)
+	}
+	if code := get(opts...); code != http.StatusInternalServerError {
+		t.Errorf("expected failure status %d, got %d", http.StatusInternalServerError, code)
+	}
+
+	critical.Update(Result{})
+	warning.Update(Result{})
+	registry.SetDraining(true)
+	if code := get(opts...); code != http.StatusTooManyRequests {
+		t.Errorf("expected draining status %d, got %d", http.StatusTooManyRequests, code)
+	}
+	if code := get(); code != http.StatusServiceUnavailable {
+		t.Errorf("expected failure status %d while draining by default, got %d", http.StatusServiceUnavailable, code)
+	}
+}
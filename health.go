@@ -1,12 +1,17 @@
 package health
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"math/rand"
 	"net/http"
+	"reflect"
 	"sync"
 	"time"
+
+	"github.com/docker/distribution/health/healthapi"
 )
 
 // A Registry is a collection of checks. Most applications will use the global
@@ -14,33 +19,259 @@ import (
 // separate registries to isolate themselves from other tests.
 type Registry struct {
 	mu               sync.RWMutex
-	registeredChecks map[string]Checker
+	registeredChecks map[string]*registeredCheck
+
+	// maxConcurrency limits the number of checks run at the same time. A
+	// value of zero or less means no limit.
+	maxConcurrency int
+	// timeout is the default timeout of a check, zero for none.
+	timeout time.Duration
+	// ttl is how long the result of a check is reused, zero to run checks on
+	// every evaluation.
+	ttl    time.Duration
+	clock  Clock
+	logger Logger
+
+	// created is when the registry was created, failing checks are reported
+	// as initializing until grace has passed since.
+	created time.Time
+	grace   time.Duration
+
+	// historySize is the number of results kept per check.
+	historySize int
+	// traceSize is the number of executions kept in trace.
+	traceSize int
+	trace     *traceBuffer
+
+	// hooks wrap every check when it runs, the first hook outermost.
+	hooks []Hook
+
+	// mounts are the registries whose checks are added under a prefix.
+	mounts map[string]*Registry
+
+	// scheduler runs the periodic checks registered with the registry,
+	// created with schedulerOpts.
+	scheduler     *Scheduler
+	schedulerOpts []SchedulerOption
+
+	// draining adds drainCheck to the readiness checks while the service is
+	// shutting down.
+	draining   bool
+	drainCheck *registeredCheck
+	// maintenance adds maintenanceCheck to the readiness checks when set.
+	maintenance      *maintenance
+	maintenanceCheck *registeredCheck
+
+	watchMu  sync.Mutex
+	watchers map[chan StatusChange]struct{}
+	// transitions logs the changes when set.
+	transitions *slog.Logger
+
+	// events delivers the events of the registry to its sinks.
+	events eventBus
+
+	// breakers are the circuit breakers of the checks by name, see
+	// Registry.Breaker.
+	breakers        map[string]*Breaker
+	breakerCooldown time.Duration
+
+	// strategy is when the checks run, every strategyInterval in the
+	// background unless it is OnRequest.
+	strategy         Strategy
+	strategyInterval time.Duration
+
+	// stateStore persists the state of the registry. restored is the saved
+	// state of the checks that are not registered yet.
+	stateStore StateStore
+	restored   map[string]savedCheck
 }
 
 // NewRegistry creates a new registry. This isn't necessary for normal use of
 // the package, but may be useful for unit tests so individual tests have their
 // own set of checks.
-func NewRegistry() *Registry {
-	return &Registry{
-		registeredChecks: make(map[string]Checker),
+func NewRegistry(opts ...RegistryOption) *Registry {
+	registry := &Registry{
+		registeredChecks: make(map[string]*registeredCheck),
+		drainCheck:       newRegisteredCheck(drainingChecker, nil),
+		clock:            realClock{},
+		historySize:      DefaultHistorySize,
+		traceSize:        DefaultTraceSize,
+	}
+	for _, opt := range opts {
+		opt(registry)
+	}
+	registry.maintenanceCheck = newRegisteredCheck(maintenanceChecker(registry), nil)
+	registry.trace = newTraceBuffer(registry.traceSize)
+	registry.scheduler = NewScheduler(append([]SchedulerOption{WithSchedulerClock(registry.clock)}, registry.schedulerOpts...)...)
+	registry.created = registry.clock.Now()
+	registry.drainCheck.registeredAt = registry.created
+	registry.maintenanceCheck.registeredAt = registry.created
+	registry.loadState()
+	registry.startRefresh()
+	return registry
+}
+
+// registeredCheck is a checker together with the options it was registered
+// with.
+type registeredCheck struct {
+	check    CheckerWithContext
+	kind     Kind
+	severity Severity
+	deps     []string
+	timeout  time.Duration
+	// sensitive hides the failures of the check from HTTP responses.
+	sensitive bool
+	metadata  *Metadata
+	weight    float64
+
+	mu          sync.Mutex
+	lastSuccess time.Time
+	// unhealthy is the outcome of the last run. Checks are assumed healthy
+	// until they ran.
+	unhealthy bool
+	// state is the State of the last run, empty until the check ran.
+	state State
+	// failures counts the consecutive failed runs.
+	failures int
+	// cached is the last result, computed at cachedAt. invalidations
+	// counts the times it was dropped, so runs started before are not
+	// cached.
+	cached        HealthCheck
+	cachedAt      time.Time
+	invalidations int
+	// disabled checks are not run, see Registry.Disable.
+	disabled bool
+	// override pins the result of the check instead of running it.
+	override *override
+	// inflight is the run in progress, shared by concurrent evaluations.
+	inflight *flight
+	// history is a ring buffer of the last results, the oldest at
+	// historyNext once it is full.
+	history     []HistoryEntry
+	historyNext int
+	// registeredAt is when the check was registered. Since then it was
+	// healthy for healthyTime and unhealthy for unhealthyTime, up to
+	// accountedAt, and changed between the two flaps times.
+	registeredAt  time.Time
+	accountedAt   time.Time
+	healthyTime   time.Duration
+	unhealthyTime time.Duration
+	flaps         int
+	// accountedUnhealthy is the state accounted for since accountedAt.
+	accountedUnhealthy bool
+	// stats are the cumulative statistics of the runs of the check.
+	stats checkStats
+	// generation counts the replacements of the check by RegisterOrReplace.
+	generation uint64
+	// last is the result of the last run. restored is the result restored
+	// from the state store, reported until the check reports its own.
+	last     Result
+	restored *Result
+}
+
+// flight is a run of a check shared by concurrent evaluations. status is set
+// before done is closed.
+type flight struct {
+	done   chan struct{}
+	status HealthCheck
+}
+
+// observe records the result of a run of the check. It returns the time of
+// the last success, or nil if the check never succeeded, the number of
+// consecutive failures including this run, or ended by it if it succeeded,
+// and whether the check changed between healthy and unhealthy.
+func (rc *registeredCheck) observe(res Result) (*time.Time, int, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	failures := rc.failures
+	if res.Error == nil {
+		if res.CheckedAt.After(rc.lastSuccess) {
+			rc.lastSuccess = res.CheckedAt
+		}
+		rc.failures = 0
+	} else {
+		rc.failures++
+		failures = rc.failures
+	}
+	changed := (res.Error != nil) != rc.unhealthy
+	rc.unhealthy = res.Error != nil
+	rc.last = res
+
+	if rc.lastSuccess.IsZero() {
+		return nil, failures, changed
+	}
+	lastSuccess := rc.lastSuccess
+	return &lastSuccess, failures, changed
+}
+
+// stop stops the check if it runs in the background.
+func (rc *registeredCheck) stop() {
+	check := any(rc.check)
+	if a, ok := check.(checkerAdapter); ok {
+		check = a.Checker
+	}
+	if s, ok := check.(interface{ Stop() }); ok {
+		s.Stop()
+	}
+}
+
+// sameChecker reports whether a and b wrap the same checker, which must not
+// be stopped when a check is replaced by itself.
+func sameChecker(a, b CheckerWithContext) bool {
+	x, y := any(a), any(b)
+	if adapter, ok := x.(checkerAdapter); ok {
+		x = adapter.Checker
 	}
+	if adapter, ok := y.(checkerAdapter); ok {
+		y = adapter.Checker
+	}
+	t := reflect.TypeOf(x)
+	return t != nil && t == reflect.TypeOf(y) && t.Comparable() && x == y
+}
+
+// CheckOption configures a check when it is registered.
+type CheckOption func(*registeredCheck)
+
+// newRegisteredCheck applies the options to a new registeredCheck for check.
+func newRegisteredCheck(check CheckerWithContext, opts []CheckOption) *registeredCheck {
+	rc := &registeredCheck{
+		check:    check,
+		kind:     Readiness,
+		severity: Critical,
+		weight:   1,
+	}
+	for _, opt := range opts {
+		opt(rc)
+	}
+	return rc
 }
 
 // DefaultRegistry is the default registry where checks are registered. It is
-// the registry used by the HTTP handler.
+// the registry used by the package level functions and handlers. Use
+// SetDefaultRegistry to replace it while they may be in use.
 var DefaultRegistry *Registry
 
-type Result struct {
-	Error   error
-	Message string
-}
+// Result is the outcome of running a check, see healthapi.Result.
+type Result = healthapi.Result
 
-// Checker is the interface for a Health Checker
-type Checker interface {
-	// Check returns nil if the service is okay.
-	Check() Result
+// timed fills in the timing of res for a check run from start to end, unless
+// the checker already did.
+func timed(res Result, start, end time.Time) Result {
+	if res.CheckedAt.IsZero() {
+		res.CheckedAt = start
+		res.Duration = end.Sub(start)
+	}
+	return res
 }
 
+// Checker is the interface for a Health Checker, see healthapi.Checker.
+type Checker = healthapi.Checker
+
+// CheckerWithContext is the interface for a Health Checker that observes a
+// context, see healthapi.CheckerWithContext.
+type CheckerWithContext = healthapi.CheckerWithContext
+
 // CheckFunc is a convenience type to create functions that implement
 // the Checker interface
 type CheckFunc func() Result
@@ -51,6 +282,31 @@ func (cf CheckFunc) Check() Result {
 	return cf()
 }
 
+// CheckFuncWithContext is a convenience type to create functions that
+// implement the CheckerWithContext interface
+type CheckFuncWithContext func(ctx context.Context) Result
+
+// Check implements the CheckerWithContext interface
+func (cf CheckFuncWithContext) Check(ctx context.Context) Result {
+	return cf(ctx)
+}
+
+// WithContext adapts a Checker to the CheckerWithContext interface. The
+// context is ignored, the wrapped check always runs to completion.
+func WithContext(check Checker) CheckerWithContext {
+	return checkerAdapter{check}
+}
+
+// checkerAdapter implements CheckerWithContext for a plain Checker.
+type checkerAdapter struct {
+	Checker
+}
+
+// Check implements the CheckerWithContext interface
+func (a checkerAdapter) Check(ctx context.Context) Result {
+	return a.Checker.Check()
+}
+
 // Updater implements a health check that is explicitly set.
 type Updater interface {
 	Checker
@@ -82,7 +338,8 @@ func (u *updater) Update(status Result) {
 	u.mu.Lock()
 	defer u.mu.Unlock()
 
-	u.status = status
+	now := time.Now()
+	u.status = timed(status, now, now)
 }
 
 // NewStatusUpdater returns a new updater
@@ -90,150 +347,762 @@ func NewStatusUpdater() Updater {
 	return &updater{}
 }
 
+// StoppableChecker is a Checker that runs in the background until stopped.
+type StoppableChecker interface {
+	Checker
+
+	// Stop stops running the check in the background. The last result keeps
+	// being reported.
+	Stop()
+}
+
+// periodicOptions holds the configuration of a periodic checker.
+type periodicOptions struct {
+	immediate  bool
+	jitter     time.Duration
+	clock      Clock
+	scheduler  *Scheduler
+	maxBackoff time.Duration
+	// unhealthyPeriod replaces the period while the check fails.
+	unhealthyPeriod time.Duration
+}
+
+// PeriodicOption configures a periodic checker.
+type PeriodicOption func(*periodicOptions)
+
+// RunImmediately runs the check once before the periodic checker is
+// returned, so it reports an actual result right away instead of being
+// healthy until the first period has passed.
+func RunImmediately() PeriodicOption {
+	return func(o *periodicOptions) {
+		o.immediate = true
+	}
+}
+
+// WithJitter adds a random delay of up to max to every period, so many
+// instances started at the same time don't run their checks in lockstep.
+func WithJitter(max time.Duration) PeriodicOption {
+	return func(o *periodicOptions) {
+		o.jitter = max
+	}
+}
+
+// WithBackoff doubles the period after every consecutive failure, up to max,
+// so a check does not hammer a broken dependency. The period is restored once
+// the check succeeds again.
+func WithBackoff(max time.Duration) PeriodicOption {
+	return func(o *periodicOptions) {
+		o.maxBackoff = max
+	}
+}
+
+// WithUnhealthyPeriod runs the check every period while it fails, instead of
+// the period it was created with. A shorter period detects recovery faster.
+// Combined with WithBackoff, backing off starts from this period.
+func WithUnhealthyPeriod(period time.Duration) PeriodicOption {
+	return func(o *periodicOptions) {
+		o.unhealthyPeriod = period
+	}
+}
+
+// WithPeriodicClock sets the clock timing the runs of the periodic checker.
+// By default the time package is used.
+func WithPeriodicClock(clock Clock) PeriodicOption {
+	return func(o *periodicOptions) {
+		o.clock = clock
+	}
+}
+
+// WithScheduler runs the periodic checker on scheduler, instead of a
+// goroutine of its own. The clock of the scheduler is used.
+func WithScheduler(scheduler *Scheduler) PeriodicOption {
+	return func(o *periodicOptions) {
+		o.scheduler = scheduler
+	}
+}
+
 // PeriodicChecker wraps an updater to provide a periodic checker
-func PeriodicChecker(check Checker, period time.Duration) Checker {
-	u := NewStatusUpdater()
-	go func() {
-		t := time.NewTicker(period)
-		for {
-			<-t.C
-			u.Update(check.Check())
+func PeriodicChecker(check Checker, period time.Duration, opts ...PeriodicOption) StoppableChecker {
+	return PeriodicCheckerContext(context.Background(), check, period, opts...)
+}
+
+// PeriodicCheckerContext wraps an updater to provide a periodic checker that
+// stops running when ctx is done or Stop is called.
+func PeriodicCheckerContext(ctx context.Context, check Checker, period time.Duration, opts ...PeriodicOption) StoppableChecker {
+	o := periodicOptions{clock: realClock{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.scheduler != nil {
+		return o.scheduler.schedule(ctx, check, period, o, false)
+	}
+	return NewScheduler(WithSchedulerClock(o.clock)).schedule(ctx, check, period, o, true)
+}
+
+// next returns the time to wait before the next run, after the given number
+// of consecutive failures.
+func (o *periodicOptions) next(period time.Duration, failures int) time.Duration {
+	if failures > 0 && o.unhealthyPeriod > 0 {
+		period = o.unhealthyPeriod
+	}
+	if o.maxBackoff > 0 {
+		for i := 0; i < failures && period < o.maxBackoff; i++ {
+			period *= 2
 		}
-	}()
+		if failures > 0 && period > o.maxBackoff {
+			period = o.maxBackoff
+		}
+	}
+	if o.jitter <= 0 {
+		return period
+	}
+	return period + time.Duration(rand.Int63n(int64(o.jitter)))
+}
+
+// thresholdChecker tolerates a number of consecutive failures of the wrapped
+// check before reporting it as failing.
+type thresholdChecker struct {
+	check     Checker
+	threshold int
 
-	return u
+	mu       sync.Mutex
+	failures int
+}
+
+// ThresholdChecker wraps a Checker so that it only reports an error after
+// failuresBeforeUnhealthy consecutive failures. Failures below the threshold
+// are reported as healthy, with the failure count in the message.
+func ThresholdChecker(check Checker, failuresBeforeUnhealthy int) Checker {
+	return &thresholdChecker{
+		check:     check,
+		threshold: failuresBeforeUnhealthy,
+	}
+}
+
+// Check implements the Checker interface
+func (tc *thresholdChecker) Check() Result {
+	res := tc.check.Check()
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if res.Error == nil {
+		tc.failures = 0
+		return res
+	}
+
+	tc.failures++
+	msg := res.Message
+	if msg == "" {
+		msg = res.Error.Error()
+	}
+	res.Message = fmt.Sprintf("%d consecutive failures: %s", tc.failures, msg)
+	if tc.failures < tc.threshold {
+		res.Error = nil
+	}
+	return res
+}
+
+// RetryChecker wraps a Checker so that a failing check is retried up to
+// attempts times in total before the failure is reported. It waits backoff
+// before the first retry, doubling the wait before every further retry.
+func RetryChecker(check Checker, attempts int, backoff time.Duration) Checker {
+	return CheckFunc(func() Result {
+		wait := backoff
+		for attempt := 1; ; attempt++ {
+			res := check.Check()
+			if res.Error == nil || attempt >= attempts {
+				return res
+			}
+			time.Sleep(wait)
+			wait *= 2
+		}
+	})
+}
+
+// PeriodicThresholdChecker wraps an updater to provide a periodic checker that
+// uses a threshold before it changes status
+func PeriodicThresholdChecker(check Checker, period time.Duration, threshold int, opts ...PeriodicOption) StoppableChecker {
+	return PeriodicChecker(ThresholdChecker(check, threshold), period, opts...)
 }
 
 type HealthCheck struct {
-	Healthy bool   `json:"healthy"`
-	Message string `json:"message"`
+	Healthy  bool     `json:"healthy"`
+	Message  string   `json:"message"`
+	Severity Severity `json:"severity"`
+	// State refines Healthy, telling degraded checks from healthy ones.
+	State State `json:"state,omitempty"`
+	// Code is the machine readable reason of a failing or degraded check.
+	Code Code `json:"code,omitempty"`
+
+	// DurationMs is how long the check took in milliseconds.
+	DurationMs float64 `json:"duration_ms"`
+	// LastChecked is when the check was run.
+	LastChecked time.Time `json:"last_checked"`
+	// LastSuccess is when the check last succeeded, nil if it never did.
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+
+	// Details is the Details of the check's Result.
+	Details map[string]any `json:"details,omitempty"`
+
+	// Initializing is set for failing checks during the registry's grace
+	// period. They are reported like failing Warning checks.
+	Initializing bool `json:"initializing,omitempty"`
+
+	// Availability accounts for the health of the check since it was
+	// registered. Status handlers only report it with WithAvailability.
+	Availability *Availability `json:"availability,omitempty"`
+
+	// Metadata is the metadata the check was registered with, if any.
+	Metadata *Metadata `json:"metadata,omitempty"`
+
+	// Disabled is set for checks disabled with Registry.Disable, which are
+	// not run and reported as healthy.
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Generation is the number of times the check was replaced with
+	// Registry.RegisterOrReplace.
+	Generation uint64 `json:"generation,omitempty"`
+
+	// sensitive is set for checks registered with the Sensitive option.
+	sensitive bool
+	// weight is the weight of the check in the score.
+	weight float64
+	// err and failures are the error of the run and the number of
+	// consecutive failures, reported by Snapshot.
+	err      error
+	failures int
 }
 
 type Status map[string]HealthCheck
 
 // CheckStatus returns a map with all the current health check errors
 func (registry *Registry) CheckStatus() Status {
+	return registry.CheckStatusContext(context.Background())
+}
+
+// CheckStatusContext returns a map with all the current health check errors.
+// The context is passed on to every check. Checks are run concurrently,
+// bounded by the registry's maximum concurrency.
+func (registry *Registry) CheckStatusContext(ctx context.Context) Status {
+	return registry.checkStatus(ctx, func(*registeredCheck) bool { return true })
+}
+
+// CheckStatusNamed returns a map with the current results of the named
+// checks. Names that are not registered are left out of the result.
+func (registry *Registry) CheckStatusNamed(ctx context.Context, names ...string) Status {
+	wanted := make(map[*registeredCheck]bool, len(names))
 	registry.mu.RLock()
-	defer registry.mu.RUnlock()
-	status := Status{}
+	for _, name := range names {
+		if rc, ok := registry.registeredChecks[name]; ok {
+			wanted[rc] = true
+		}
+	}
+	registry.mu.RUnlock()
+
+	return registry.checkStatus(ctx, func(rc *registeredCheck) bool { return wanted[rc] })
+}
 
+// checkStatus runs the registered checks for which include returns true and
+// returns their results.
+func (registry *Registry) checkStatus(ctx context.Context, include func(*registeredCheck) bool) Status {
+	registry.mu.RLock()
+	checks := make(map[string]*registeredCheck, len(registry.registeredChecks))
 	for k, v := range registry.registeredChecks {
-		res := v.Check()
+		if include(v) {
+			checks[k] = v
+		}
+	}
+	if registry.draining && include(registry.drainCheck) {
+		checks[DrainingCheckName] = registry.drainCheck
+	}
+	if registry.maintenance != nil && include(registry.maintenanceCheck) {
+		checks[MaintenanceCheckName] = registry.maintenanceCheck
+	}
+	hooks := registry.hooks
+	registry.mu.RUnlock()
 
-		healthy := res.Error == nil
+	var sem chan struct{}
+	if registry.maxConcurrency > 0 {
+		sem = make(chan struct{}, registry.maxConcurrency)
+	}
 
-		status[k] = HealthCheck{
-			Healthy: healthy,
-			Message: res.Message,
+	// every check waits for the checks it depends on, and is skipped if one
+	// of them fails
+	type pending struct {
+		done  chan struct{}
+		check HealthCheck
+	}
+	results := make(map[string]*pending, len(checks))
+	for k := range checks {
+		results[k] = &pending{done: make(chan struct{})}
+	}
+	deps := dependencyEdges(checks)
+
+	var (
+		wg      sync.WaitGroup
+		mounted Status
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		mounted = registry.mountedStatus(ctx, include)
+	}()
+	for k, v := range checks {
+		wg.Add(1)
+		go func(name string, rc *registeredCheck) {
+			defer wg.Done()
+			p := results[name]
+			defer close(p.done)
+
+			for _, dep := range deps[name] {
+				d := results[dep]
+				<-d.done
+				if !d.check.Healthy {
+					p.check = rc.skipped("dependency " + dep + " failing")
+					return
+				}
+			}
+
+			if sem != nil {
+				sem <- struct{}{}
+				defer func() { <-sem }()
+			}
+			p.check = registry.runCheck(ctx, name, rc, hooks)
+		}(k, v)
+	}
+	wg.Wait()
+
+	status := make(Status, len(results)+len(mounted))
+	for k, check := range mounted {
+		status[k] = check
+	}
+	for k, p := range results {
+		p.check.Generation = checks[k].generation
+		status[k] = p.check
+	}
+	return status
+}
+
+// runCheck runs a single check wrapped in hooks and records its result. A
+// result younger than the registry's TTL is reused instead. Concurrent
+// evaluations of the same check share a single run, so a storm of probes
+// does not multiply the load on slow dependencies.
+func (registry *Registry) runCheck(ctx context.Context, name string, rc *registeredCheck, hooks []Hook) HealthCheck {
+	if status, ok := rc.disabledStatus(); ok {
+		return status
+	}
+	if status, ok := registry.lastStatus(ctx, rc); ok {
+		return status
+	}
+	if registry.ttl > 0 {
+		if cached, ok := rc.fresh(registry.clock.Now(), registry.ttl); ok {
+			return cached
 		}
 	}
 
+	f, leader := rc.join()
+	if !leader {
+		select {
+		case <-f.done:
+			return f.status
+		case <-ctx.Done():
+			return HealthCheck{
+				Message:  "check cancelled",
+				Severity: rc.severity,
+				State:    StateUnhealthy,
+				Code:     CodeCancelled,
+				weight:   rc.weight,
+				err:      ctx.Err(),
+			}
+		}
+	}
+	defer rc.land(f)
+
+	f.status = registry.execute(ctx, name, rc, hooks)
+	return f.status
+}
+
+// join returns the run of the check in progress, or starts a new one. It
+// reports whether the caller started the run and must execute it.
+func (rc *registeredCheck) join() (*flight, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.inflight != nil {
+		return rc.inflight, false
+	}
+	rc.inflight = &flight{done: make(chan struct{})}
+	return rc.inflight, true
+}
+
+// land completes the run f, releasing the evaluations waiting for it.
+func (rc *registeredCheck) land(f *flight) {
+	rc.mu.Lock()
+	rc.inflight = nil
+	rc.mu.Unlock()
+	close(f.done)
+}
+
+// execute runs a single check wrapped in hooks and records its result.
+func (registry *Registry) execute(ctx context.Context, name string, rc *registeredCheck, hooks []Hook) HealthCheck {
+	check := rc.check
+	for i := len(hooks) - 1; i >= 0; i-- {
+		check = hooks[i](name, check)
+	}
+
+	timeout := registry.timeout
+	if rc.timeout > 0 {
+		timeout = rc.timeout
+	}
+
+	start := registry.clock.Now()
+	rc.mu.Lock()
+	invalidations := rc.invalidations
+	rc.mu.Unlock()
+	registry.events.publish(Event{Type: CheckStarted, Check: name, Time: start})
+	var res Result
+	if o := rc.overridden(start); o != nil {
+		res = o.result()
+	} else {
+		res = rc.warm(runWithTimeout(ctx, check, timeout))
+	}
+	res = classify(timed(res, start, registry.clock.Now()))
+	lastSuccess, failures, changed := rc.observe(res)
+	rc.record(res, registry.historySize)
+	registry.trace.add(name, rc.sensitive, res)
+	rc.observeStats(res)
+	availability := rc.account(registry.clock.Now(), res.Error == nil)
+	registry.publishResult(name, rc, res, failures)
+	if changed {
+		registry.notify(StatusChange{
+			Name:     name,
+			Healthy:  res.Error == nil,
+			Message:  res.Message,
+			Time:     res.CheckedAt,
+			Error:    res.Error,
+			Duration: res.Duration,
+			Failures: failures,
+		})
+	}
+
+	status := HealthCheck{
+		Healthy:      res.Error == nil,
+		Message:      res.Message,
+		Severity:     rc.severity,
+		State:        res.State(),
+		Code:         res.Code,
+		DurationMs:   float64(res.Duration) / float64(time.Millisecond),
+		LastChecked:  res.CheckedAt,
+		LastSuccess:  lastSuccess,
+		Details:      res.Details,
+		Metadata:     rc.metadata,
+		sensitive:    rc.sensitive,
+		weight:       rc.weight,
+		err:          res.Error,
+		Availability: &availability,
+	}
+	if !status.Healthy {
+		status.failures = failures
+	}
+	if !status.Healthy && rc != registry.drainCheck && rc != registry.maintenanceCheck && registry.initializing(registry.clock.Now()) {
+		status.Initializing = true
+	}
+	if registry.ttl > 0 || registry.strategy != OnRequest {
+		rc.cache(status, start, invalidations)
+	}
 	return status
 }
 
+// runWithTimeout runs check, giving up after timeout if it is positive. The
+// context passed to the check is cancelled at the same time, a check ignoring
+// it keeps running in the background until it returns.
+func runWithTimeout(ctx context.Context, check CheckerWithContext, timeout time.Duration) Result {
+	if timeout <= 0 {
+		return safeCheck(ctx, check)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ch := make(chan Result, 1)
+	go func() {
+		ch <- safeCheck(ctx, check)
+	}()
+
+	select {
+	case res := <-ch:
+		return res
+	case <-ctx.Done():
+		if ctx.Err() != context.DeadlineExceeded {
+			return Result{Error: ctx.Err(), Message: "check cancelled"}
+		}
+		return Result{
+			Error:   fmt.Errorf("%w after %v: %w", ErrTimeout, timeout, ctx.Err()),
+			Message: "check timed out after " + timeout.String(),
+		}
+	}
+}
+
+// fresh returns the cached result of the check if it is younger than ttl.
+func (rc *registeredCheck) fresh(now time.Time, ttl time.Duration) (HealthCheck, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.cachedAt.IsZero() || now.Sub(rc.cachedAt) >= ttl {
+		return HealthCheck{}, false
+	}
+	return rc.cached, true
+}
+
+// cache stores the result of the check run at checkedAt, unless the cached
+// result was invalidated since the run started.
+func (rc *registeredCheck) cache(status HealthCheck, checkedAt time.Time, invalidations int) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.invalidations == invalidations {
+		rc.cached, rc.cachedAt = status, checkedAt
+	}
+}
+
+// invalidate drops the cached result of the check. rc.mu must be held.
+func (rc *registeredCheck) invalidate() {
+	rc.cachedAt = time.Time{}
+	rc.invalidations++
+}
+
 // CheckStatus returns a map with all the current health check results from the
 // default registry.
 func CheckStatus() Status {
-	return DefaultRegistry.CheckStatus()
+	return Default().CheckStatus()
+}
+
+// CheckStatusContext returns a map with all the current health check results
+// from the default registry. The context is passed on to every check.
+func CheckStatusContext(ctx context.Context) Status {
+	return Default().CheckStatusContext(ctx)
+}
+
+// Register associates the checker with the provided name. Like MustRegister,
+// it panics if the name is already registered.
+func (registry *Registry) Register(name string, check Checker, opts ...CheckOption) {
+	registry.RegisterWithContext(name, WithContext(check), opts...)
+}
+
+// RegisterWithContext associates the context aware checker with the provided
+// name. It panics if the name is already registered.
+func (registry *Registry) RegisterWithContext(name string, check CheckerWithContext, opts ...CheckOption) {
+	if registry == nil {
+		registry = Default()
+	}
+	if err := registry.register(name, check, opts); err != nil {
+		panic("Check already exists: " + name)
+	}
 }
 
-// Register associates the checker with the provided name.
-func (registry *Registry) Register(name string, check Checker) {
+// TryRegister associates the checker with the provided name, returning a
+// *CheckError caused by ErrAlreadyRegistered instead of panicking if the
+// name is already registered, for applications registering checks of
+// modules loaded at runtime.
+func (registry *Registry) TryRegister(name string, check Checker, opts ...CheckOption) error {
 	if registry == nil {
-		registry = DefaultRegistry
+		registry = Default()
 	}
+	return registry.register(name, WithContext(check), opts)
+}
+
+// MustRegister associates the checker with the provided name, panicking if
+// the name is already registered. It is the same as Register.
+func (registry *Registry) MustRegister(name string, check Checker, opts ...CheckOption) {
+	registry.Register(name, check, opts...)
+}
+
+// register associates the checker with name unless it is already
+// registered.
+func (registry *Registry) register(name string, check CheckerWithContext, opts []CheckOption) error {
 	registry.mu.Lock()
 	defer registry.mu.Unlock()
-	_, ok := registry.registeredChecks[name]
-	if ok {
-		panic("Check already exists: " + name)
+	if _, ok := registry.registeredChecks[name]; ok {
+		return &CheckError{Name: name, Cause: ErrAlreadyRegistered}
 	}
-	registry.registeredChecks[name] = check
+	rc := newRegisteredCheck(check, opts)
+	registry.install(name, rc)
+	return nil
+}
+
+// install registers rc as name, restoring its saved state and running it
+// right away if the strategy of the registry has background runs.
+// registry.mu must be held.
+func (registry *Registry) install(name string, rc *registeredCheck) {
+	rc.registeredAt = registry.clock.Now()
+	registry.restore(name, rc)
+	registry.prefetch(name, rc)
+	registry.registeredChecks[name] = rc
 }
 
 // Register associates the checker with the provided name in the default
 // registry.
-func Register(name string, check Checker) {
-	DefaultRegistry.Register(name, check)
+func Register(name string, check Checker, opts ...CheckOption) {
+	Default().Register(name, check, opts...)
+}
+
+// TryRegister associates the checker with the provided name in the default
+// registry, returning an error if the name is already registered.
+func TryRegister(name string, check Checker, opts ...CheckOption) error {
+	return Default().TryRegister(name, check, opts...)
+}
+
+// MustRegister associates the checker with the provided name in the default
+// registry, panicking if the name is already registered.
+func MustRegister(name string, check Checker, opts ...CheckOption) {
+	Default().MustRegister(name, check, opts...)
+}
+
+// RegisterWithContext associates the context aware checker with the provided
+// name in the default registry.
+func RegisterWithContext(name string, check CheckerWithContext, opts ...CheckOption) {
+	Default().RegisterWithContext(name, check, opts...)
+}
+
+// Deregister removes the checker registered under the provided name, which
+// is stopped if it runs in the background. It is a no-op if no such checker
+// exists.
+func (registry *Registry) Deregister(name string) {
+	registry.mu.Lock()
+	rc, ok := registry.registeredChecks[name]
+	delete(registry.registeredChecks, name)
+	registry.mu.Unlock()
+
+	if ok {
+		rc.stop()
+	}
+}
+
+// Deregister removes the checker registered under the provided name from the
+// default registry.
+func Deregister(name string) {
+	Default().Deregister(name)
+}
+
+// Replace associates the checker with the provided name, replacing any
+// checker previously registered under that name like RegisterOrReplace.
+func (registry *Registry) Replace(name string, check Checker, opts ...CheckOption) {
+	registry.RegisterOrReplace(name, check, opts...)
+}
+
+// Replace associates the checker with the provided name in the default
+// registry, replacing any checker previously registered under that name.
+func Replace(name string, check Checker, opts ...CheckOption) {
+	Default().Replace(name, check, opts...)
+}
+
+// RegisterOrReplace associates the checker with the provided name, atomically
+// swapping any checker previously registered under that name, which is
+// stopped if it runs in the background. Every replacement bumps the
+// Generation reported in the status of the check, so a configuration reload
+// rebuilding the checks can be told apart from the previous one. It returns
+// the generation of the check.
+func (registry *Registry) RegisterOrReplace(name string, check Checker, opts ...CheckOption) uint64 {
+	rc := newRegisteredCheck(WithContext(check), opts)
+
+	registry.mu.Lock()
+	old, ok := registry.registeredChecks[name]
+	if ok {
+		rc.generation = old.generation + 1
+	}
+	registry.install(name, rc)
+	registry.mu.Unlock()
+
+	if ok && !sameChecker(old.check, rc.check) {
+		old.stop()
+	}
+	return rc.generation
+}
+
+// RegisterOrReplace associates the checker with the provided name in the
+// default registry, swapping any checker previously registered under that
+// name.
+func RegisterOrReplace(name string, check Checker, opts ...CheckOption) uint64 {
+	return Default().RegisterOrReplace(name, check, opts...)
+}
+
+// Close stops all checks in the registry that run in the background, such as
+// the ones registered with RegisterPeriodicFunc, closes its scheduler and
+// saves its state, see WithStateStore.
+// The checks stay registered and keep reporting their last result. The sinks
+// of the registry receive a RegistryClosed event.
+func (registry *Registry) Close() error {
+	registry.mu.RLock()
+	for _, rc := range registry.registeredChecks {
+		rc.stop()
+	}
+	registry.mu.RUnlock()
+	err := registry.scheduler.Close()
+	if saveErr := registry.SaveState(context.Background()); saveErr != nil {
+		err = errors.Join(err, fmt.Errorf("saving state: %w", saveErr))
+	}
+	registry.events.publish(Event{Type: RegistryClosed, Time: registry.clock.Now()})
+	return err
+}
+
+// Scheduler returns the scheduler running the periodic checks of the
+// registry. Use it with the WithScheduler option to run other periodic
+// checkers alongside them.
+func (registry *Registry) Scheduler() *Scheduler {
+	return registry.scheduler
 }
 
 // RegisterFunc allows the convenience of registering a checker directly from
 // an arbitrary func() error.
-func (registry *Registry) RegisterFunc(name string, check CheckFunc) {
-	registry.Register(name, check)
+func (registry *Registry) RegisterFunc(name string, check CheckFunc, opts ...CheckOption) {
+	registry.Register(name, check, opts...)
 }
 
 // RegisterFunc allows the convenience of registering a checker in the default
 // registry directly from an arbitrary func() error.
-func RegisterFunc(name string, check CheckFunc) {
-	DefaultRegistry.RegisterFunc(name, check)
+func RegisterFunc(name string, check CheckFunc, opts ...CheckOption) {
+	Default().RegisterFunc(name, check, opts...)
 }
 
 // RegisterPeriodicFunc allows the convenience of registering a PeriodicChecker
 // from an arbitrary func() error.
-func (registry *Registry) RegisterPeriodicFunc(name string, period time.Duration, check CheckFunc) {
-	registry.Register(name, PeriodicChecker(check, period))
+func (registry *Registry) RegisterPeriodicFunc(name string, period time.Duration, check CheckFunc, opts ...CheckOption) {
+	registry.Register(name, PeriodicChecker(check, period, WithScheduler(registry.scheduler)), opts...)
 }
 
 // RegisterPeriodicFunc allows the convenience of registering a PeriodicChecker
 // in the default registry from an arbitrary func() error.
-func RegisterPeriodicFunc(name string, period time.Duration, check CheckFunc) {
-	DefaultRegistry.RegisterPeriodicFunc(name, period, check)
+func RegisterPeriodicFunc(name string, period time.Duration, check CheckFunc, opts ...CheckOption) {
+	Default().RegisterPeriodicFunc(name, period, check, opts...)
+}
+
+// RegisterPeriodicThresholdFunc allows the convenience of registering a
+// PeriodicChecker from an arbitrary func() error.
+func (registry *Registry) RegisterPeriodicThresholdFunc(name string, period time.Duration, threshold int, check CheckFunc, opts ...CheckOption) {
+	registry.Register(name, PeriodicThresholdChecker(check, period, threshold, WithScheduler(registry.scheduler)), opts...)
+}
+
+// RegisterPeriodicThresholdFunc allows the convenience of registering a
+// PeriodicChecker in the default registry from an arbitrary func() error.
+func RegisterPeriodicThresholdFunc(name string, period time.Duration, threshold int, check CheckFunc, opts ...CheckOption) {
+	Default().RegisterPeriodicThresholdFunc(name, period, threshold, check, opts...)
 }
 
 // StatusHandler returns a JSON blob with all the currently registered Health Checks
 // and their corresponding status.
 // Returns 503 if any Error status exists, 200 otherwise
-func StatusHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method == "GET" {
-		checks := CheckStatus()
-		isFailing := false
-		for _, v := range checks {
-			if !v.Healthy {
-				isFailing = true
-			}
-		}
-
-		status := http.StatusOK
-		if isFailing {
-			// If there is an error, return 503
-			status = http.StatusServiceUnavailable
-		}
-
-		statusResponse(w, r, status, checks)
-	} else {
-		http.NotFound(w, r)
-	}
+func (registry *Registry) StatusHandler(w http.ResponseWriter, r *http.Request) {
+	registry.Handler().ServeHTTP(w, r)
 }
 
-// statusResponse completes the request with a response describing the health
-// of the service.
-func statusResponse(w http.ResponseWriter, r *http.Request, status int, checks Status) {
-	p, err := json.Marshal(checks)
-	if err != nil {
-		log.Printf("error serializing health status: %v", err)
-		p, err = json.Marshal(struct {
-			ServerError string `json:"server_error"`
-		}{
-			ServerError: "Could not parse error message",
-		})
-		status = http.StatusInternalServerError
-
-		if err != nil {
-			log.Printf("error serializing health status failure message: %v", err)
-			return
-		}
-	}
-
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	w.Header().Set("Content-Length", fmt.Sprint(len(p)))
-	w.WriteHeader(status)
-	if _, err := w.Write(p); err != nil {
-		log.Printf("error writing health status response body: %v", err)
-	}
+// StatusHandler returns a JSON blob with all the currently registered Health
+// Checks in the default registry and their corresponding status.
+// Returns 503 if any Error status exists, 200 otherwise
+func StatusHandler(w http.ResponseWriter, r *http.Request) {
+	Default().StatusHandler(w, r)
 }
 
-// Registers global /debug/health api endpoint, creates default registry
+// init creates the default registry. Importing the package registers no
+// endpoint, see MustRegisterDefaultEndpoint.
 func init() {
 	DefaultRegistry = NewRegistry()
 }
@@ -1,11 +1,15 @@
 package health
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 )
 
 // TestReturns200IfThereAreNoChecks ensures that the result code of the health
@@ -36,8 +40,8 @@ func TestReturns503IfThereAreErrorChecks(t *testing.T) {
 	}
 
 	// Create a manual error
-	Register("some_check", CheckFunc(func() error {
-		return errors.New("This Check did not succeed")
+	Register("some_check", CheckFunc(func() Result {
+		return Result{Error: errors.New("This Check did not succeed")}
 	}))
 
 	StatusHandler(recorder, req)
@@ -98,10 +102,462 @@ func TestHealthHandler(t *testing.T) {
 	checkUp(t, "initial health check")
 
 	// now, we fail the health check
-	updater.Update(fmt.Errorf("the server is now out of commission"))
+	updater.Update(Result{Error: fmt.Errorf("the server is now out of commission")})
 	checkDown(t, "server should be down") // should be down
 
 	// bring server back up
-	updater.Update(nil)
+	updater.Update(Result{})
 	checkUp(t, "when server is back up") // now we should be back up.
 }
+
+// TestStatusHandlerPassesRequestContext ensures that context aware checks
+// receive the context of the incoming request.
+func TestStatusHandlerPassesRequestContext(t *testing.T) {
+	DefaultRegistry = NewRegistry()
+
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "value")
+	req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request.")
+	}
+	req = req.WithContext(ctx)
+
+	RegisterWithContext("context_check", CheckFuncWithContext(func(ctx context.Context) Result {
+		if ctx.Value(key{}) != "value" {
+			return Result{Error: errors.New("request context was not passed to the check")}
+		}
+		return Result{}
+	}))
+
+	recorder := httptest.NewRecorder()
+	StatusHandler(recorder, req)
+
+	if recorder.Code != http.StatusOK {
+		t.Errorf("Did not get a 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+// TestRegistryHandler ensures that a registry's handler only reports the
+// checks of that registry.
+func TestRegistryHandler(t *testing.T) {
+	DefaultRegistry = NewRegistry()
+	Register("default_check", CheckFunc(func() Result {
+		return Result{Error: errors.New("the default registry is failing")}
+	}))
+
+	registry := NewRegistry()
+	registry.Register("own_check", CheckFunc(func() Result {
+		return Result{}
+	}))
+
+	server := httptest.NewServer(registry.Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("error getting status: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected response code: %d != %d", resp.StatusCode, http.StatusOK)
+	}
+
+	var status Status
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		t.Fatalf("error decoding status: %v", err)
+	}
+	if _, ok := status["own_check"]; !ok || len(status) != 1 {
+		t.Errorf("unexpected checks in response: %v", status)
+	}
+}
+
+// TestDeregisterAndReplace ensures checks can be removed and swapped at
+// runtime without panicking.
+func TestDeregisterAndReplace(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("check", CheckFunc(func() Result {
+		return Result{Error: errors.New("failing")}
+	}))
+
+	stoppable := &stoppableChecker{}
+	registry.Replace("check", stoppable)
+	if status := registry.CheckStatus(); !status["check"].Healthy || status["check"].Generation != 1 {
+		t.Errorf("replaced check was expected to be healthy with a new generation: %+v", status["check"])
+	}
+
+	replacing := &stoppableChecker{}
+	registry.Replace("check", replacing)
+	if !stoppable.stopped {
+		t.Errorf("replaced checker was not stopped")
+	}
+
+	registry.Deregister("check")
+	if status := registry.CheckStatus(); len(status) != 0 {
+		t.Errorf("deregistered check is still reported: %v", status)
+	}
+	if !replacing.stopped {
+		t.Errorf("deregistered checker was not stopped")
+	}
+
+	// registering again after removal must not panic
+	registry.Register("check", CheckFunc(func() Result {
+		return Result{}
+	}))
+}
+
+// TestRegisterOrReplace ensures replacing a check bumps its generation and
+// stops the replaced checker.
+func TestRegisterOrReplace(t *testing.T) {
+	registry := NewRegistry()
+	updater := NewStatusUpdater()
+	if gen := registry.RegisterOrReplace("check", updater); gen != 0 {
+		t.Errorf("unexpected generation of a new check: %d", gen)
+	}
+	if gen := registry.RegisterOrReplace("check", updater); gen != 1 {
+		t.Errorf("unexpected generation after replacing: %d", gen)
+	}
+
+	stoppable := &stoppableChecker{}
+	registry.RegisterOrReplace("check", stoppable)
+	registry.RegisterOrReplace("check", stoppable)
+	if stoppable.stopped {
+		t.Errorf("checker replaced by itself was stopped")
+	}
+	if gen := registry.RegisterOrReplace("check", CheckFunc(func() Result {
+		return Result{Error: errors.New("failing")}
+	})); gen != 4 {
+		t.Errorf("unexpected generation after replacing: %d", gen)
+	}
+	if !stoppable.stopped {
+		t.Errorf("replaced checker was not stopped")
+	}
+
+	status := registry.CheckStatus()["check"]
+	if status.Generation != 4 || status.Healthy {
+		t.Errorf("unexpected status: %+v", status)
+	}
+}
+
+// stoppableChecker is a healthy checker recording whether it was stopped.
+type stoppableChecker struct {
+	stopped bool
+}
+
+func (c *stoppableChecker) Check() Result { return Result{} }
+
+func (c *stoppableChecker) Stop() { c.stopped = true }
+
+// TestCheckStatusMaxConcurrency ensures checks run concurrently without
+// exceeding the configured limit.
+func TestCheckStatusMaxConcurrency(t *testing.T) {
+	const limit = 2
+	registry := NewRegistry(WithMaxConcurrency(limit))
+
+	var (
+		mu      sync.Mutex
+		running int
+		peak    int
+	)
+	for i := 0; i < 6; i++ {
+		registry.Register(fmt.Sprintf("check_%d", i), CheckFunc(func() Result {
+			mu.Lock()
+			running++
+			if running > peak {
+				peak = running
+			}
+			mu.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return Result{}
+		}))
+	}
+
+	status := registry.CheckStatus()
+	if len(status) != 6 {
+		t.Fatalf("expected 6 results, got %d", len(status))
+	}
+	if peak > limit {
+		t.Errorf("ran %d checks concurrently, limit is %d", peak, limit)
+	}
+	if peak < limit {
+		t.Errorf("checks were not run concurrently, peak was %d", peak)
+	}
+}
+
+// TestCheckStatusTimestamps ensures the registry reports how long checks took
+// and when they last ran and succeeded.
+func TestCheckStatusTimestamps(t *testing.T) {
+	registry := NewRegistry()
+	updater := NewStatusUpdater()
+	registry.Register("updater", updater)
+	registry.Register("slow", CheckFunc(func() Result {
+		time.Sleep(5 * time.Millisecond)
+		return Result{}
+	}))
+
+	before := time.Now()
+	status := registry.CheckStatus()
+	if status["slow"].DurationMs < 5 {
+		t.Errorf("slow check was expected to take at least 5ms, took %vms", status["slow"].DurationMs)
+	}
+	if status["slow"].LastChecked.Before(before) {
+		t.Errorf("unexpected last checked time: %v", status["slow"].LastChecked)
+	}
+	if status["slow"].LastSuccess == nil {
+		t.Errorf("slow check was expected to have a last success")
+	}
+
+	updated := time.Now()
+	updater.Update(Result{Error: errors.New("failing")})
+	status = registry.CheckStatus()
+	if !status["updater"].LastChecked.After(updated) {
+		t.Errorf("last checked time was expected to be the time of the update, got %v", status["updater"].LastChecked)
+	}
+	lastSuccess := status["updater"].LastSuccess
+	if lastSuccess == nil || lastSuccess.After(updated) {
+		t.Errorf("last success was expected to be before the failing update, got %v", lastSuccess)
+	}
+}
+
+// TestStatusHandlerDetails ensures result details are serialized into the
+// response.
+func TestStatusHandlerDetails(t *testing.T) {
+	DefaultRegistry = NewRegistry()
+	Register("database", CheckFunc(func() Result {
+		return Result{
+			Error:   errors.New("connection refused"),
+			Details: map[string]any{"host": "db.example.com", "retries": 3},
+		}
+	}))
+
+	req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request.")
+	}
+	recorder := httptest.NewRecorder()
+	StatusHandler(recorder, req)
+
+	var status Status
+	if err := json.Unmarshal(recorder.Body.Bytes(), &status); err != nil {
+		t.Fatalf("error decoding status: %v", err)
+	}
+	details := status["database"].Details
+	if details["host"] != "db.example.com" || details["retries"] != float64(3) {
+		t.Errorf("unexpected details: %v", details)
+	}
+}
+
+// TestThresholdChecker ensures a check only fails after the configured number
+// of consecutive failures.
+func TestThresholdChecker(t *testing.T) {
+	updater := NewStatusUpdater()
+	checker := ThresholdChecker(updater, 3)
+
+	updater.Update(Result{Error: errors.New("dropped packet")})
+	for i := 1; i < 3; i++ {
+		res := checker.Check()
+		if res.Error != nil {
+			t.Fatalf("failure %d was expected to be tolerated", i)
+		}
+		if expected := fmt.Sprintf("%d consecutive failures: dropped packet", i); res.Message != expected {
+			t.Errorf("unexpected message: %q != %q", res.Message, expected)
+		}
+	}
+	if res := checker.Check(); res.Error == nil {
+		t.Fatalf("third failure was expected to be reported")
+	}
+
+	updater.Update(Result{})
+	if res := checker.Check(); res.Error != nil {
+		t.Fatalf("check was expected to recover")
+	}
+	updater.Update(Result{Error: errors.New("dropped packet")})
+	if res := checker.Check(); res.Error != nil {
+		t.Errorf("failure count was expected to be reset on success")
+	}
+}
+
+// TestPeriodicCheckerStop ensures periodic checks stop running when the
+// registry is closed or their context is cancelled.
+func TestPeriodicCheckerStop(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		runs = map[string]int{}
+	)
+	counting := func(name string) CheckFunc {
+		return func() Result {
+			mu.Lock()
+			defer mu.Unlock()
+			runs[name]++
+			return Result{}
+		}
+	}
+	count := func(name string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return runs[name]
+	}
+
+	registry := NewRegistry()
+	registry.RegisterPeriodicFunc("registered", time.Millisecond, counting("registered"))
+	ctx, cancel := context.WithCancel(context.Background())
+	PeriodicCheckerContext(ctx, counting("context"), time.Millisecond)
+
+	time.Sleep(20 * time.Millisecond)
+	if err := registry.Close(); err != nil {
+		t.Fatalf("unexpected error closing registry: %v", err)
+	}
+	cancel()
+	time.Sleep(5 * time.Millisecond)
+
+	registered, withContext := count("registered"), count("context")
+	if registered == 0 || withContext == 0 {
+		t.Fatalf("periodic checks did not run")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if count("registered") != registered {
+		t.Errorf("periodic check kept running after the registry was closed")
+	}
+	if count("context") != withContext {
+		t.Errorf("periodic check kept running after its context was cancelled")
+	}
+}
+
+// TestPeriodicCheckerRunImmediately ensures the first result of a periodic
+// check is available right away.
+func TestPeriodicCheckerRunImmediately(t *testing.T) {
+	checker := PeriodicChecker(CheckFunc(func() Result {
+		return Result{Error: errors.New("failing")}
+	}), time.Hour, RunImmediately(), WithJitter(time.Minute))
+	defer checker.Stop()
+
+	if res := checker.Check(); res.Error == nil {
+		t.Errorf("periodic check was expected to have run")
+	}
+}
+
+// TestRetryChecker ensures failures are retried before being reported.
+func TestRetryChecker(t *testing.T) {
+	calls := 0
+	flaky := CheckFunc(func() Result {
+		calls++
+		if calls < 3 {
+			return Result{Error: errors.New("network blip")}
+		}
+		return Result{}
+	})
+
+	if res := RetryChecker(flaky, 3, time.Millisecond).Check(); res.Error != nil {
+		t.Errorf("check was expected to succeed on the third attempt, error:%v", res.Error)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+
+	calls = 0
+	if res := RetryChecker(flaky, 2, time.Millisecond).Check(); res.Error == nil {
+		t.Errorf("check was expected to fail after 2 attempts")
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 attempts, got %d", calls)
+	}
+}
+
+// TestPeriodicCheckerBackoff ensures the period of a failing periodic check
+// doubles up to the maximum, and is restored on recovery.
+func TestPeriodicCheckerBackoff(t *testing.T) {
+	o := periodicOptions{maxBackoff: 4 * time.Minute}
+	for failures, expected := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 4 * time.Minute} {
+		if next := o.next(time.Minute, failures); next != expected {
+			t.Errorf("expected %v after %d failures, got %v", expected, failures, next)
+		}
+	}
+
+	clock := &fakeClock{now: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)}
+	runs := make(chan struct{}, 1)
+	check := PeriodicChecker(CheckFunc(func() Result {
+		runs <- struct{}{}
+		return Result{Error: errors.New("failing")}
+	}), time.Minute, RunImmediately(), WithBackoff(4*time.Minute), WithPeriodicClock(clock))
+	defer check.Stop()
+	<-runs
+
+	clock.waitForTimers(t, 1)
+	clock.Add(time.Minute)
+	select {
+	case <-runs:
+		t.Fatalf("check ran before the backed off period passed")
+	default:
+	}
+	clock.Add(time.Minute)
+	select {
+	case <-runs:
+	case <-time.After(time.Second):
+		t.Fatalf("check did not run after the backed off period passed")
+	}
+}
+
+// TestPeriodicCheckerUnhealthyPeriod ensures failing periodic checks run at
+// their unhealthy period.
+func TestPeriodicCheckerUnhealthyPeriod(t *testing.T) {
+	o := periodicOptions{unhealthyPeriod: 10 * time.Second}
+	if next := o.next(time.Minute, 0); next != time.Minute {
+		t.Errorf("expected the period while healthy, got %v", next)
+	}
+	if next := o.next(time.Minute, 3); next != 10*time.Second {
+		t.Errorf("expected the unhealthy period while failing, got %v", next)
+	}
+	o.maxBackoff = 30 * time.Second
+	if next := o.next(time.Minute, 2); next != 30*time.Second {
+		t.Errorf("expected backing off from the unhealthy period, got %v", next)
+	}
+
+	clock := &fakeClock{now: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)}
+	runs := make(chan struct{}, 1)
+	check := PeriodicChecker(CheckFunc(func() Result {
+		runs <- struct{}{}
+		return Result{Error: errors.New("failing")}
+	}), time.Minute, RunImmediately(), WithUnhealthyPeriod(10*time.Second), WithPeriodicClock(clock))
+	defer check.Stop()
+	<-runs
+
+	clock.waitForTimers(t, 1)
+	clock.Add(10 * time.Second)
+	select {
+	case <-runs:
+	case <-time.After(time.Second):
+		t.Fatalf("failing check did not run after the unhealthy period")
+	}
+}
+
+// TestTryRegister ensures TryRegister reports duplicate names as an error
+// while MustRegister panics.
+func TestTryRegister(t *testing.T) {
+	registry := NewRegistry()
+	check := CheckFunc(func() Result { return Result{} })
+
+	if err := registry.TryRegister("check", check); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := registry.TryRegister("check", check)
+	var checkErr *CheckError
+	if !errors.Is(err, ErrAlreadyRegistered) || !errors.As(err, &checkErr) || checkErr.Name != "check" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(registry.CheckStatus()) != 1 {
+		t.Errorf("duplicate registration replaced the check")
+	}
+	assertPanics(t, func() { registry.MustRegister("check", check) }, "registering a duplicate name")
+
+	defer SetDefaultRegistry(SetDefaultRegistry(registry))
+	if err := (*Registry)(nil).TryRegister("check", check); !errors.Is(err, ErrAlreadyRegistered) {
+		t.Errorf("expected a nil registry to use the default registry, got %v", err)
+	}
+}
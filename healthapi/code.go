@@ -0,0 +1,85 @@
+package healthapi
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"syscall"
+)
+
+// Code is the machine readable reason of a failing or degraded result, so
+// automation can branch on the type of failure rather than parse messages.
+type Code string
+
+const (
+	// CodeTimeout is the code of checks that timed out.
+	CodeTimeout Code = "TIMEOUT"
+	// CodeCancelled is the code of checks cancelled before they completed.
+	CodeCancelled Code = "CANCELLED"
+	// CodeConnRefused is the code of checks whose connection was refused.
+	CodeConnRefused Code = "CONN_REFUSED"
+	// CodeDNS is the code of checks failing to resolve a host.
+	CodeDNS Code = "DNS"
+	// CodeTLS is the code of checks failing the TLS handshake, for example
+	// on an expired or untrusted certificate.
+	CodeTLS Code = "TLS"
+	// CodeAuth is the code of checks whose credentials were rejected.
+	CodeAuth Code = "AUTH"
+	// CodeUnavailable is the code of checks whose dependency answered but
+	// reported itself unavailable, like a 503 response.
+	CodeUnavailable Code = "UNAVAILABLE"
+	// CodeThreshold is the code of checks measuring a value beyond its
+	// limit, like disk usage or clock skew.
+	CodeThreshold Code = "THRESHOLD"
+	// CodePanic is the code of checks that panicked.
+	CodePanic Code = "PANIC"
+	// CodeSkipped is the code of checks that were not run because a
+	// dependency failed.
+	CodeSkipped Code = "SKIPPED"
+	// CodeDegraded is the code of degraded results without a more specific
+	// code.
+	CodeDegraded Code = "DEGRADED"
+	// CodeUnknown is the code of failures that could not be classified.
+	CodeUnknown Code = "UNKNOWN"
+)
+
+// CodeOf classifies err, returning CodeUnknown for errors it does not
+// recognize and an empty Code for a nil error. Built-in checkers and the
+// health registry use it to fill in the Code of failing results.
+func CodeOf(err error) Code {
+	if err == nil {
+		return ""
+	}
+
+	var (
+		dnsErr      *net.DNSError
+		netErr      net.Error
+		certErr     *tls.CertificateVerificationError
+		unknownCA   x509.UnknownAuthorityError
+		invalidCert x509.CertificateInvalidError
+		hostnameErr x509.HostnameError
+		recordErr   tls.RecordHeaderError
+		alertErr    tls.AlertError
+	)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return CodeTimeout
+	case errors.Is(err, context.Canceled):
+		return CodeCancelled
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return CodeConnRefused
+	case errors.As(err, &dnsErr):
+		if dnsErr.IsTimeout {
+			return CodeTimeout
+		}
+		return CodeDNS
+	case errors.As(err, &certErr), errors.As(err, &unknownCA), errors.As(err, &invalidCert),
+		errors.As(err, &hostnameErr), errors.As(err, &recordErr), errors.As(err, &alertErr):
+		return CodeTLS
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return CodeTimeout
+	}
+	return CodeUnknown
+}
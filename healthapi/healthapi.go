@@ -0,0 +1,85 @@
+// Package healthapi defines the interfaces between checks and the health
+// package. It has no dependencies outside the standard library, so code
+// implementing checks, or consuming them, can depend on it alone. The health
+// package refers to these types through aliases, so they are interchangeable
+// with health.Result, health.Checker and health.CheckerWithContext.
+package healthapi
+
+import (
+	"context"
+	"time"
+)
+
+// Result is the outcome of running a check. A nil Error means the check is
+// healthy, or degraded if Degraded is set.
+type Result struct {
+	Error   error
+	Message string
+
+	// Degraded reports that the check works in a reduced mode, like a
+	// database in read-only mode or a cache serving from its slow path. It
+	// only applies to results without an Error.
+	Degraded bool
+
+	// Code is the machine readable reason of a failing or degraded result.
+	// If it is left empty, the registry derives it from Error with CodeOf,
+	// or sets CodeDegraded for degraded results.
+	Code Code
+
+	// Details holds optional machine readable context about the result, for
+	// example the host of a failing connection or a retry count. It is
+	// serialized into the JSON response, so values must be JSON encodable.
+	Details map[string]any
+
+	// CheckedAt is the time the check was run and Duration how long it took.
+	// If CheckedAt is left zero, the registry sets both when it runs the
+	// check. Checkers returning previously computed results, like
+	// PeriodicChecker, set them to report the age of the result.
+	CheckedAt time.Time
+	Duration  time.Duration
+}
+
+// State is the health of a check or of a service.
+type State string
+
+const (
+	// StateHealthy is the state of checks that work.
+	StateHealthy State = "healthy"
+	// StateDegraded is the state of checks that work in a reduced mode.
+	StateDegraded State = "degraded"
+	// StateUnhealthy is the state of failing checks.
+	StateUnhealthy State = "unhealthy"
+)
+
+// State returns the state of the check that returned the result.
+func (r Result) State() State {
+	switch {
+	case r.Error != nil:
+		return StateUnhealthy
+	case r.Degraded:
+		return StateDegraded
+	}
+	return StateHealthy
+}
+
+// Checker is the interface for a Health Checker
+type Checker interface {
+	// Check returns a Result with a nil Error if the service is okay.
+	Check() Result
+}
+
+// CheckerWithContext is the interface for a Health Checker that observes a
+// context. The context is cancelled when the caller is no longer interested
+// in the result, for example when the client of the status endpoint goes away.
+type CheckerWithContext interface {
+	// Check returns a Result with a nil Error if the service is okay.
+	Check(ctx context.Context) Result
+}
+
+// CheckProvider is implemented by libraries exposing health checks of their
+// own, like clients of a service checking their connection, so applications
+// can register them all at once.
+type CheckProvider interface {
+	// HealthChecks returns the checks of the library by name.
+	HealthChecks() map[string]Checker
+}
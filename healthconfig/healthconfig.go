@@ -0,0 +1,284 @@
+// Package healthconfig registers the built-in checks of the checks package
+// from a YAML or JSON description, so operators can add probes without
+// recompiling the service.
+//
+//	checks:
+//	  - name: upstream
+//	    type: http
+//	    url: http://upstream:8080/health
+//	    period: 10s
+//	    timeout: 2s
+//	  - name: disk
+//	    type: disk
+//	    path: /var/lib/data
+//	    max_used_percent: 90
+//	    severity: warning
+//	  - name: heartbeat
+//	    type: file
+//	    path: /tmp/heartbeat
+//	    max_age: 1m
+//	    kinds: [liveness]
+//
+// A Watcher applies the changes of a configuration file at runtime.
+package healthconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/docker/distribution/health"
+	"github.com/docker/distribution/health/checks"
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultTimeout is the timeout of tcp and dns checks configured without
+// one.
+const DefaultTimeout = 5 * time.Second
+
+// Config describes a set of checks.
+type Config struct {
+	Checks []Check `json:"checks"`
+}
+
+// Check describes a single check. Type selects the checker and which of the
+// other fields apply:
+//
+//   - http: URL, StatusCodes and Headers, see checks.HTTPGetCheck
+//   - tcp: Address, see checks.TCPChecker
+//   - dns: Host, see checks.DNSResolve
+//   - disk: Path and MaxUsedPercent, see checks.DiskUsage
+//   - file: Path and optionally MaxAge, see checks.FileExists and
+//     checks.FileTouchedWithin
+type Check struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+
+	// Period runs the check in the background when set, see
+	// health.PeriodicChecker.
+	Period Duration `json:"period,omitempty"`
+	// Timeout limits how long the check may take.
+	Timeout Duration `json:"timeout,omitempty"`
+	// Threshold is the number of consecutive failures before the check is
+	// reported as failing, see health.ThresholdChecker.
+	Threshold int `json:"threshold,omitempty"`
+	// Severity is "critical", the default, or "warning".
+	Severity string `json:"severity,omitempty"`
+	// Kinds lists "liveness", "readiness" and "startup". The default is
+	// readiness.
+	Kinds []string `json:"kinds,omitempty"`
+
+	URL            string            `json:"url,omitempty"`
+	StatusCodes    []int             `json:"status_codes,omitempty"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	Address        string            `json:"address,omitempty"`
+	Host           string            `json:"host,omitempty"`
+	Path           string            `json:"path,omitempty"`
+	MaxUsedPercent float64           `json:"max_used_percent,omitempty"`
+	MaxAge         Duration          `json:"max_age,omitempty"`
+}
+
+// Duration is a time.Duration written like "1m30s" in configuration files.
+type Duration time.Duration
+
+// MarshalJSON implements json.Marshaler
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("invalid duration %s", b)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Parse parses a YAML or JSON configuration.
+func Parse(data []byte) (*Config, error) {
+	var config Config
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// Load reads and parses the configuration file at path.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return config, nil
+}
+
+// NewRegistry returns a registry created with opts holding the configured
+// checks.
+func (c *Config) NewRegistry(opts ...health.RegistryOption) (*health.Registry, error) {
+	registry := health.NewRegistry(opts...)
+	if err := c.Register(registry); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+// Register adds the configured checks to registry. Nothing is registered if
+// any check is invalid. Like health.Register, it panics if a name is already
+// registered.
+func (c *Config) Register(registry *health.Registry) error {
+	registrations, err := c.validate()
+	if err != nil {
+		return err
+	}
+	for _, r := range registrations {
+		registry.Register(r.check.Name, r.check.build(registry), r.opts...)
+	}
+	return nil
+}
+
+// registration is a validated check and its registration options.
+type registration struct {
+	check Check
+	opts  []health.CheckOption
+}
+
+// validate returns the registrations of the configured checks, or the error
+// of the first invalid one.
+func (c *Config) validate() ([]registration, error) {
+	var registrations []registration
+	names := make(map[string]bool)
+	for i, check := range c.Checks {
+		if check.Name == "" {
+			return nil, fmt.Errorf("check %d: missing name", i)
+		}
+		if names[check.Name] {
+			return nil, fmt.Errorf("check %s: duplicate name", check.Name)
+		}
+		names[check.Name] = true
+
+		// Validate the check before anything is started.
+		if _, err := check.checker(); err != nil {
+			return nil, fmt.Errorf("check %s: %w", check.Name, err)
+		}
+		opts, err := check.options()
+		if err != nil {
+			return nil, fmt.Errorf("check %s: %w", check.Name, err)
+		}
+		registrations = append(registrations, registration{check: check, opts: opts})
+	}
+	return registrations, nil
+}
+
+// build returns the checker of the valid check c, running in the background
+// on the scheduler of registry if it has a period.
+func (c Check) build(registry *health.Registry) health.Checker {
+	checker, _ := c.checker()
+	period := time.Duration(c.Period)
+	scheduler := health.WithScheduler(registry.Scheduler())
+	switch {
+	case period > 0 && c.Threshold > 0:
+		checker = health.PeriodicThresholdChecker(checker, period, c.Threshold, health.RunImmediately(), scheduler)
+	case period > 0:
+		checker = health.PeriodicChecker(checker, period, health.RunImmediately(), scheduler)
+	case c.Threshold > 0:
+		checker = health.ThresholdChecker(checker, c.Threshold)
+	}
+	return checker
+}
+
+// checker returns the checker described by c.
+func (c Check) checker() (health.Checker, error) {
+	timeout := time.Duration(c.Timeout)
+	switch c.Type {
+	case "http":
+		if c.URL == "" {
+			return nil, errors.New("missing url")
+		}
+		opts := []checks.HTTPCheckOption{checks.WithTimeout(timeout)}
+		if len(c.StatusCodes) > 0 {
+			opts = append(opts, checks.WithStatusCodes(c.StatusCodes...))
+		}
+		for key, value := range c.Headers {
+			opts = append(opts, checks.WithHeader(key, value))
+		}
+		return checks.HTTPGetCheck(c.URL, opts...), nil
+	case "tcp":
+		if c.Address == "" {
+			return nil, errors.New("missing address")
+		}
+		return checks.TCPChecker(c.Address, orDefault(timeout)), nil
+	case "dns":
+		if c.Host == "" {
+			return nil, errors.New("missing host")
+		}
+		return checks.DNSResolve(c.Host, orDefault(timeout)), nil
+	case "disk":
+		if c.Path == "" || c.MaxUsedPercent <= 0 {
+			return nil, errors.New("missing path or max_used_percent")
+		}
+		return checks.DiskUsage(c.Path, c.MaxUsedPercent), nil
+	case "file":
+		if c.Path == "" {
+			return nil, errors.New("missing path")
+		}
+		if c.MaxAge > 0 {
+			return checks.FileTouchedWithin(c.Path, time.Duration(c.MaxAge)), nil
+		}
+		return checks.FileExists(c.Path), nil
+	}
+	return nil, fmt.Errorf("unknown type %q", c.Type)
+}
+
+// options returns the registration options described by c.
+func (c Check) options() ([]health.CheckOption, error) {
+	var opts []health.CheckOption
+	if c.Timeout > 0 {
+		opts = append(opts, health.WithTimeout(time.Duration(c.Timeout)))
+	}
+
+	switch health.Severity(c.Severity) {
+	case "":
+	case health.Critical, health.Warning:
+		opts = append(opts, health.WithSeverity(health.Severity(c.Severity)))
+	default:
+		return nil, fmt.Errorf("unknown severity %q", c.Severity)
+	}
+
+	if len(c.Kinds) > 0 {
+		var kind health.Kind
+		for _, k := range c.Kinds {
+			switch k {
+			case "liveness":
+				kind |= health.Liveness
+			case "readiness":
+				kind |= health.Readiness
+			case "startup":
+				kind |= health.Startup
+			default:
+				return nil, fmt.Errorf("unknown kind %q", k)
+			}
+		}
+		opts = append(opts, health.WithKind(kind))
+	}
+	return opts, nil
+}
+
+// orDefault returns timeout, or DefaultTimeout if it is not set.
+func orDefault(timeout time.Duration) time.Duration {
+	if timeout <= 0 {
+		return DefaultTimeout
+	}
+	return timeout
+}
@@ -0,0 +1,98 @@
+package healthconfig
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/distribution/health"
+)
+
+func TestNewRegistry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	heartbeat := filepath.Join(dir, "heartbeat")
+	if err := os.WriteFile(heartbeat, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := Parse([]byte(`
+checks:
+  - name: upstream
+    type: http
+    url: ` + server.URL + `
+    status_codes: [418]
+    timeout: 1s
+  - name: missing
+    type: file
+    path: ` + filepath.Join(dir, "missing") + `
+    severity: warning
+  - name: heartbeat
+    type: file
+    path: ` + heartbeat + `
+    max_age: 1m
+    period: 1h
+    kinds: [liveness, readiness]
+`))
+	if err != nil {
+		t.Fatalf("error parsing configuration: %v", err)
+	}
+	if time.Duration(config.Checks[2].Period) != time.Hour {
+		t.Errorf("unexpected period %v", config.Checks[2].Period)
+	}
+
+	registry, err := config.NewRegistry()
+	if err != nil {
+		t.Fatalf("error creating registry: %v", err)
+	}
+	defer registry.Close()
+
+	status := registry.CheckStatus()
+	if len(status) != 3 || !status["upstream"].Healthy || status["missing"].Healthy || status["missing"].Severity != health.Warning || !status["heartbeat"].Healthy {
+		t.Errorf("unexpected status: %+v", status)
+	}
+	if live := registry.CheckStatusKind(t.Context(), health.Liveness); len(live) != 1 {
+		t.Errorf("expected only heartbeat to be a liveness check, got %+v", live)
+	}
+}
+
+func TestInvalidConfig(t *testing.T) {
+	for _, tc := range []struct {
+		config   string
+		expected string
+	}{
+		{`checks: [{name: a, type: ping}]`, `unknown type "ping"`},
+		{`checks: [{type: tcp, address: "db:5432"}]`, "missing name"},
+		{`checks: [{name: a, type: tcp}]`, "missing address"},
+		{`checks: [{name: a, type: dns, host: db}, {name: a, type: dns, host: db}]`, "duplicate name"},
+		{`checks: [{name: a, type: dns, host: db, severity: fatal}]`, `unknown severity "fatal"`},
+		{`checks: [{name: a, type: dns, host: db, kinds: [ready]}]`, `unknown kind "ready"`},
+	} {
+		config, err := Parse([]byte(tc.config))
+		if err != nil {
+			t.Fatalf("error parsing %s: %v", tc.config, err)
+		}
+		registry := health.NewRegistry()
+		if err := config.Register(registry); err == nil || !strings.Contains(err.Error(), tc.expected) {
+			t.Errorf("%s: expected error %q, got %v", tc.config, tc.expected, err)
+		}
+		if len(registry.CheckStatus()) != 0 {
+			t.Errorf("%s: expected nothing to be registered", tc.config)
+		}
+	}
+
+	if _, err := Parse([]byte(`checks: [{name: a, type: tcp, timeout: soon}]`)); err == nil {
+		t.Errorf("expected invalid duration to fail")
+	}
+	if _, err := Parse([]byte(`{"checks": [{"name": "a", "type": "tcp", "adress": "db:5432"}]}`)); err == nil {
+		t.Errorf("expected unknown field to fail")
+	}
+}
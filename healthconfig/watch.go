@@ -0,0 +1,170 @@
+package healthconfig
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/docker/distribution/health"
+)
+
+// DefaultInterval is how often a Watcher polls its configuration file by
+// default.
+const DefaultInterval = 10 * time.Second
+
+// Option configures a Watcher created by NewWatcher.
+type Option func(*Watcher)
+
+// WithInterval sets how often the configuration file is polled. The default
+// is DefaultInterval.
+func WithInterval(interval time.Duration) Option {
+	return func(w *Watcher) {
+		w.interval = interval
+	}
+}
+
+// WithLogger sets the logger failed reloads are logged to. By default
+// health.DefaultLogger is used.
+func WithLogger(logger health.Logger) Option {
+	return func(w *Watcher) {
+		w.logger = logger
+	}
+}
+
+// Watcher keeps the checks of a registry in sync with a configuration file,
+// so checks can be added, removed and changed without restarting the
+// service.
+type Watcher struct {
+	registry *health.Registry
+	path     string
+	interval time.Duration
+	logger   health.Logger
+
+	mu sync.Mutex
+	// data is the content of the file last applied.
+	data []byte
+	// applied are the checks registered from the file, by name.
+	applied map[string]applied
+}
+
+// applied is a check registered by a Watcher.
+type applied struct {
+	check   Check
+	checker health.Checker
+}
+
+// NewWatcher registers the checks of the configuration file at path in
+// registry, returning an error if the file can not be read or is invalid.
+// Call Run to apply the later changes of the file.
+func NewWatcher(registry *health.Registry, path string, opts ...Option) (*Watcher, error) {
+	w := &Watcher{
+		registry: registry,
+		path:     path,
+		interval: DefaultInterval,
+		applied:  make(map[string]applied),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	if err := w.Reload(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Run polls the configuration file until ctx is done, applying its changes.
+// Failed reloads are logged and leave the checks unchanged.
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.Reload(); err != nil {
+				w.log().Printf("healthconfig: error reloading %s: %v", w.path, err)
+			}
+		}
+	}
+}
+
+// Reload reads the configuration file and applies its changes: added checks
+// are registered, removed ones deregistered and changed ones replaced with
+// Registry.RegisterOrReplace. Nothing changes if the configuration is
+// invalid. Checks whose name is already registered by other means are
+// skipped and reported in the error, and retried by the next reload.
+func (w *Watcher) Reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		return err
+	}
+	if w.data != nil && bytes.Equal(data, w.data) {
+		return nil
+	}
+	config, err := Parse(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", w.path, err)
+	}
+	registrations, err := config.validate()
+	if err != nil {
+		return fmt.Errorf("%s: %w", w.path, err)
+	}
+
+	var errs []error
+	wanted := make(map[string]bool, len(registrations))
+	for _, r := range registrations {
+		name := r.check.Name
+		wanted[name] = true
+		old, ok := w.applied[name]
+		if ok && reflect.DeepEqual(old.check, r.check) {
+			continue
+		}
+
+		checker := r.check.build(w.registry)
+		if ok {
+			w.registry.RegisterOrReplace(name, checker, r.opts...)
+		} else if err := w.registry.TryRegister(name, checker, r.opts...); err != nil {
+			stop(checker)
+			errs = append(errs, err)
+			continue
+		}
+		w.applied[name] = applied{check: r.check, checker: checker}
+	}
+	for name := range w.applied {
+		if !wanted[name] {
+			w.registry.Deregister(name)
+			delete(w.applied, name)
+		}
+	}
+	if len(errs) > 0 {
+		// the skipped checks are registered on the next reload if their
+		// names are freed by then
+		return errors.Join(errs...)
+	}
+	w.data = data
+	return nil
+}
+
+// log returns the logger of the watcher.
+func (w *Watcher) log() health.Logger {
+	if w.logger != nil {
+		return w.logger
+	}
+	return health.DefaultLogger()
+}
+
+// stop stops checker if it runs in the background.
+func stop(checker health.Checker) {
+	if s, ok := checker.(health.StoppableChecker); ok {
+		s.Stop()
+	}
+}
@@ -0,0 +1,131 @@
+package healthconfig
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/distribution/health"
+)
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	heartbeat := filepath.Join(dir, "heartbeat")
+	if err := os.WriteFile(heartbeat, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "checks.yaml")
+	write := func(config string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`
+checks:
+  - name: heartbeat
+    type: file
+    path: ` + heartbeat + `
+  - name: missing
+    type: file
+    path: ` + filepath.Join(dir, "missing") + `
+`)
+	registry := health.NewRegistry()
+	defer registry.Close()
+	w, err := NewWatcher(registry, path)
+	if err != nil {
+		t.Fatalf("error creating watcher: %v", err)
+	}
+	if status := registry.CheckStatus(); len(status) != 2 || !status["heartbeat"].Healthy || status["missing"].Healthy {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+
+	// the missing check is removed, heartbeat changed and extra added
+	write(`
+checks:
+  - name: heartbeat
+    type: file
+    path: ` + heartbeat + `
+    severity: warning
+  - name: extra
+    type: file
+    path: ` + heartbeat + `
+`)
+	if err := w.Reload(); err != nil {
+		t.Fatalf("error reloading: %v", err)
+	}
+	status := registry.CheckStatus()
+	if len(status) != 2 || status["heartbeat"].Severity != health.Warning || status["heartbeat"].Generation != 1 || !status["extra"].Healthy || status["extra"].Generation != 0 {
+		t.Errorf("unexpected status after reload: %+v", status)
+	}
+
+	// an invalid configuration leaves the checks unchanged
+	write(`checks: [{name: heartbeat, type: ping}]`)
+	if err := w.Reload(); err == nil {
+		t.Errorf("expected invalid configuration to fail")
+	}
+	if status := registry.CheckStatus(); len(status) != 2 {
+		t.Errorf("unexpected status after invalid reload: %+v", status)
+	}
+
+	// names registered by other means are left alone
+	registry.Register("other", health.CheckFunc(func() health.Result { return health.Result{} }))
+	write(`checks: [{name: other, type: file, path: ` + heartbeat + `}]`)
+	if err := w.Reload(); !errors.Is(err, health.ErrAlreadyRegistered) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if status := registry.CheckStatus(); len(status) != 1 || status["other"].Generation != 0 {
+		t.Errorf("unexpected status after conflicting reload: %+v", status)
+	}
+
+	// the same configuration is applied again once the name is freed
+	registry.Deregister("other")
+	if err := w.Reload(); err != nil {
+		t.Fatalf("error reloading: %v", err)
+	}
+	if status := registry.CheckStatus(); len(status) != 1 || !status["other"].Healthy {
+		t.Errorf("unexpected status after retrying the reload: %+v", status)
+	}
+}
+
+func TestWatcherRun(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "checks.yaml")
+	if err := os.WriteFile(path, []byte(`checks: []`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	registry := health.NewRegistry()
+	w, err := NewWatcher(registry, path, WithInterval(time.Millisecond))
+	if err != nil {
+		t.Fatalf("error creating watcher: %v", err)
+	}
+	if _, err := NewWatcher(registry, filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Errorf("expected a missing file to fail")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.Run(ctx)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	if err := os.WriteFile(path, []byte(`checks: [{name: config, type: file, path: `+path+`}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(registry.CheckStatus()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("the change of the configuration file was not applied")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
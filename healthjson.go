@@ -0,0 +1,89 @@
+package health
+
+import (
+	"time"
+)
+
+// HealthJSONContentType is the media type of the "Health Check Response
+// Format for HTTP APIs" IETF draft.
+const HealthJSONContentType = "application/health+json"
+
+// HealthJSONResponse is a response body in the health+json format.
+type HealthJSONResponse struct {
+	Status    string                        `json:"status"`
+	Version   string                        `json:"version,omitempty"`
+	ReleaseID string                        `json:"releaseId,omitempty"`
+	Notes     []string                      `json:"notes,omitempty"`
+	Output    string                        `json:"output,omitempty"`
+	Checks    map[string][]HealthJSONDetail `json:"checks,omitempty"`
+}
+
+// HealthJSONDetail is the status of a single check in the health+json format.
+type HealthJSONDetail struct {
+	Status        string  `json:"status"`
+	ObservedValue float64 `json:"observedValue"`
+	ObservedUnit  string  `json:"observedUnit"`
+	Time          string  `json:"time,omitempty"`
+	Output        string  `json:"output,omitempty"`
+}
+
+// releaseInfo holds the service level fields of a health+json response.
+type releaseInfo struct {
+	version   string
+	releaseID string
+	notes     []string
+}
+
+// WithHealthJSON makes the handler respond in the health+json format unless
+// the client asks for another one. Without it, the format is only used for
+// requests that accept HealthJSONContentType.
+func WithHealthJSON() HandlerOption {
+	return func(c *handlerConfig) {
+		c.healthJSON = true
+	}
+}
+
+// WithReleaseInfo sets the version, release id and notes reported in
+// health+json responses. By default the version and commit of the build are
+// reported, see SetBuildInfo.
+func WithReleaseInfo(version, releaseID string, notes ...string) HandlerOption {
+	return func(c *handlerConfig) {
+		c.release = releaseInfo{
+			version:   version,
+			releaseID: releaseID,
+			notes:     notes,
+		}
+	}
+}
+
+// newHealthJSONResponse converts the status of the checks to the health+json
+// format. The checks' duration is reported as their observed value.
+func newHealthJSONResponse(checks Status, overall string, release releaseInfo) HealthJSONResponse {
+	resp := HealthJSONResponse{
+		Status:    overall,
+		Version:   release.version,
+		ReleaseID: release.releaseID,
+		Notes:     release.notes,
+		Checks:    make(map[string][]HealthJSONDetail, len(checks)),
+	}
+	if resp.Version == "" && resp.ReleaseID == "" {
+		if build := currentBuildInfo(); build != nil {
+			resp.Version = build.Version
+			resp.ReleaseID = build.Commit
+		}
+	}
+
+	for name, check := range checks {
+		detail := HealthJSONDetail{
+			Status:        check.Status(),
+			ObservedValue: check.DurationMs,
+			ObservedUnit:  "ms",
+			Output:        check.Message,
+		}
+		if !check.LastChecked.IsZero() {
+			detail.Time = check.LastChecked.UTC().Format(time.RFC3339Nano)
+		}
+		resp.Checks[name] = []HealthJSONDetail{detail}
+	}
+	return resp
+}
@@ -0,0 +1,54 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestHealthJSONNegotiation ensures the health+json format is served to
+// clients asking for it.
+func TestHealthJSONNegotiation(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("database", CheckFunc(func() Result {
+		return Result{Error: errors.New("failing"), Message: "connection refused"}
+	}))
+	handler := registry.Handler(WithReleaseInfo("1", "1.2.3", "canary"))
+
+	req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request.")
+	}
+	req.Header.Set("Accept", "application/json;q=0.5, application/health+json")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	if ct := recorder.Header().Get("Content-Type"); ct != HealthJSONContentType {
+		t.Errorf("unexpected content type: %q", ct)
+	}
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Did not get a 503.")
+	}
+
+	var resp HealthJSONResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("error decoding response: %v", err)
+	}
+	if resp.Status != StatusFail || resp.Version != "1" || resp.ReleaseID != "1.2.3" || len(resp.Notes) != 1 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	detail := resp.Checks["database"]
+	if len(detail) != 1 || detail[0].Status != StatusFail || detail[0].Output != "connection refused" || detail[0].ObservedUnit != "ms" {
+		t.Errorf("unexpected check details: %+v", detail)
+	}
+
+	// plain json clients keep getting the plain format
+	req.Header.Set("Accept", "application/json")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	if ct := recorder.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Errorf("unexpected content type: %q", ct)
+	}
+}
@@ -0,0 +1,309 @@
+// Package healthtest provides utilities for testing code using the health
+// package: fake checkers, assertions on a registry and a harness serving its
+// status endpoint.
+package healthtest
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/distribution/health"
+	"github.com/docker/distribution/health/healthapi"
+)
+
+// AlwaysHealthy returns a checker that always succeeds.
+func AlwaysHealthy() health.Checker {
+	return health.CheckFunc(func() health.Result {
+		return health.Result{}
+	})
+}
+
+// AlwaysFailing returns a checker that always fails with msg.
+func AlwaysFailing(msg string) health.Checker {
+	return health.CheckFunc(func() health.Result {
+		return failure(msg)
+	})
+}
+
+// Flaky returns a checker that fails randomly, with probability rate.
+func Flaky(rate float64) health.Checker {
+	return health.CheckFunc(func() health.Result {
+		if rand.Float64() < rate {
+			return failure("flaky failure")
+		}
+		return health.Result{}
+	})
+}
+
+// ManualChecker is a checker whose result is set by the test. It starts
+// healthy.
+type ManualChecker struct {
+	mu    sync.Mutex
+	res   health.Result
+	calls int
+}
+
+// NewManualChecker returns a healthy ManualChecker.
+func NewManualChecker() *ManualChecker {
+	return &ManualChecker{}
+}
+
+// Check implements the Checker interface
+func (m *ManualChecker) Check() health.Result {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls++
+	return m.res
+}
+
+// Set sets the result returned by the following runs.
+func (m *ManualChecker) Set(res health.Result) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.res = res
+}
+
+// SetHealthy makes the following runs succeed.
+func (m *ManualChecker) SetHealthy() {
+	m.Set(health.Result{})
+}
+
+// SetFailing makes the following runs fail with msg.
+func (m *ManualChecker) SetFailing(msg string) {
+	m.Set(failure(msg))
+}
+
+// Calls returns the number of times the check ran.
+func (m *ManualChecker) Calls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls
+}
+
+// AssertHealthy fails the test unless all the checks of registry are
+// healthy.
+func AssertHealthy(t testing.TB, registry *health.Registry) {
+	t.Helper()
+	if failing := failingChecks(registry.CheckStatus()); len(failing) > 0 {
+		t.Errorf("expected registry to be healthy, failing checks: %s", strings.Join(failing, "; "))
+	}
+}
+
+// AssertUnhealthy fails the test unless the named checks of registry fail,
+// or, without names, unless any check fails.
+func AssertUnhealthy(t testing.TB, registry *health.Registry, names ...string) {
+	t.Helper()
+	status := registry.CheckStatus()
+	if len(names) == 0 {
+		if len(failingChecks(status)) == 0 {
+			t.Errorf("expected registry to be unhealthy, all checks are healthy")
+		}
+		return
+	}
+	for _, name := range names {
+		check, ok := status[name]
+		switch {
+		case !ok:
+			t.Errorf("expected check %s to fail, it is not registered", name)
+		case check.Healthy:
+			t.Errorf("expected check %s to fail, it is healthy", name)
+		}
+	}
+}
+
+// failingChecks describes the failing checks in status, in order.
+func failingChecks(status health.Status) []string {
+	var failing []string
+	for name, check := range status {
+		if !check.Healthy {
+			failing = append(failing, name+": "+check.Message)
+		}
+	}
+	sort.Strings(failing)
+	return failing
+}
+
+// Server serves the status endpoint of a registry for the duration of a test.
+type Server struct {
+	*httptest.Server
+	t testing.TB
+}
+
+// NewServer starts a Server serving the status handler of registry created
+// with opts. It is closed when the test finishes.
+func NewServer(t testing.TB, registry *health.Registry, opts ...health.HandlerOption) *Server {
+	s := &Server{Server: httptest.NewServer(registry.Handler(opts...)), t: t}
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Get requests the status from the server and returns the status code and
+// the decoded checks. The test fails if the request fails.
+func (s *Server) Get() (int, health.Status) {
+	s.t.Helper()
+	req, err := http.NewRequest("GET", s.URL, nil)
+	if err != nil {
+		s.t.Fatalf("error creating request: %v", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := s.Client().Do(req)
+	if err != nil {
+		s.t.Fatalf("error requesting status: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var status health.Status
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		s.t.Fatalf("error decoding status: %v", err)
+	}
+	return resp.StatusCode, status
+}
+
+// FakeClock is a health.Clock that only moves when told to, for testing time
+// dependent behavior like TTLs, grace periods and periodic checks without
+// sleeping.
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// NewFakeClock returns a FakeClock set to now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now implements the health.Clock interface
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer implements the health.Clock interface
+func (c *FakeClock) NewTimer(d time.Duration) health.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{clock: c, c: make(chan time.Time, 1), deadline: c.now.Add(d), active: true}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+// Advance moves the clock forward by d, firing the timers that are due.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	for _, t := range c.timers {
+		if t.active && !t.deadline.After(c.now) {
+			t.active = false
+			t.c <- c.now
+		}
+	}
+}
+
+// Timers returns the number of timers waiting to fire. Tests can poll it to
+// know when a periodic checker is waiting for its next run.
+func (c *FakeClock) Timers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	active := 0
+	for _, t := range c.timers {
+		if t.active {
+			active++
+		}
+	}
+	return active
+}
+
+// fakeTimer is a health.Timer created by a FakeClock.
+type fakeTimer struct {
+	clock    *FakeClock
+	c        chan time.Time
+	deadline time.Time
+	active   bool
+}
+
+// C implements the health.Timer interface
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+// Stop implements the health.Timer interface
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	active := t.active
+	t.active = false
+	return active
+}
+
+// Reset implements the health.Timer interface
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	active := t.active
+	t.active = true
+	t.deadline = t.clock.now.Add(d)
+	return active
+}
+
+// failure returns an unhealthy Result carrying msg as both the error and the
+// message.
+func failure(msg string) health.Result {
+	return health.Result{Error: errors.New(msg), Message: msg}
+}
+
+// MockChecker is a checker returning a scripted sequence of results. Once
+// the script is exhausted, the last result is repeated. Without results it
+// is healthy.
+type MockChecker struct {
+	mu      sync.Mutex
+	results []healthapi.Result
+	// next is the index of the next result.
+	next  int
+	calls int
+}
+
+// NewMockChecker returns a MockChecker returning results in order.
+func NewMockChecker(results ...healthapi.Result) *MockChecker {
+	return &MockChecker{results: results}
+}
+
+// Check implements the healthapi.Checker interface
+func (m *MockChecker) Check() healthapi.Result {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls++
+	if len(m.results) == 0 {
+		return healthapi.Result{}
+	}
+	if m.next == len(m.results) {
+		return m.results[m.next-1]
+	}
+	m.next++
+	return m.results[m.next-1]
+}
+
+// Push appends results to the script. If the script was exhausted, they are
+// returned next.
+func (m *MockChecker) Push(results ...healthapi.Result) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.results = append(m.results, results...)
+}
+
+// Calls returns the number of times the check ran.
+func (m *MockChecker) Calls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls
+}
@@ -0,0 +1,128 @@
+package healthtest
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/docker/distribution/health"
+	"github.com/docker/distribution/health/healthapi"
+)
+
+// recorder is a testing.TB recording failures instead of failing the test.
+type recorder struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failed = true
+}
+
+func TestCheckers(t *testing.T) {
+	if res := AlwaysHealthy().Check(); res.Error != nil {
+		t.Errorf("unexpected result: %+v", res)
+	}
+	if res := AlwaysFailing("down").Check(); res.Error == nil || res.Message != "down" {
+		t.Errorf("unexpected result: %+v", res)
+	}
+	if res := Flaky(0).Check(); res.Error != nil {
+		t.Errorf("expected Flaky(0) to succeed, got %+v", res)
+	}
+	if res := Flaky(1).Check(); res.Error == nil {
+		t.Errorf("expected Flaky(1) to fail")
+	}
+
+	m := NewManualChecker()
+	if res := m.Check(); res.Error != nil {
+		t.Errorf("expected manual checker to start healthy, got %+v", res)
+	}
+	m.SetFailing("down")
+	if res := m.Check(); res.Message != "down" || m.Calls() != 2 {
+		t.Errorf("unexpected result after %d calls: %+v", m.Calls(), res)
+	}
+}
+
+func TestAssertions(t *testing.T) {
+	registry := health.NewRegistry()
+	m := NewManualChecker()
+	registry.Register("manual", m)
+
+	r := &recorder{TB: t}
+	AssertHealthy(r, registry)
+	AssertUnhealthy(r, registry)
+	if !r.failed {
+		t.Errorf("expected AssertUnhealthy to fail on a healthy registry")
+	}
+
+	m.SetFailing("down")
+	r = &recorder{TB: t}
+	AssertUnhealthy(r, registry, "manual")
+	if r.failed {
+		t.Errorf("expected AssertUnhealthy to pass")
+	}
+	AssertHealthy(r, registry)
+	if !r.failed {
+		t.Errorf("expected AssertHealthy to fail on an unhealthy registry")
+	}
+}
+
+func TestServer(t *testing.T) {
+	registry := health.NewRegistry()
+	m := NewManualChecker()
+	registry.Register("manual", m)
+	s := NewServer(t, registry)
+
+	if code, status := s.Get(); code != http.StatusOK || !status["manual"].Healthy {
+		t.Errorf("unexpected response: %d %+v", code, status)
+	}
+	m.SetFailing("down")
+	if code, status := s.Get(); code != http.StatusServiceUnavailable || status["manual"].Message != "down" {
+		t.Errorf("unexpected response: %d %+v", code, status)
+	}
+}
+
+func TestFakeClock(t *testing.T) {
+	clock := NewFakeClock(time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC))
+	registry := health.NewRegistry(health.WithClock(clock))
+	m := NewManualChecker()
+	registry.RegisterPeriodicFunc("periodic", time.Minute, m.Check)
+	defer registry.Close()
+
+	for clock.Timers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	clock.Advance(time.Minute)
+	deadline := time.Now().Add(time.Second)
+	for m.Calls() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if m.Calls() != 1 {
+		t.Errorf("expected the periodic check to run once, ran %d times", m.Calls())
+	}
+}
+
+func TestMockChecker(t *testing.T) {
+	m := NewMockChecker(healthapi.Result{}, healthapi.Result{Error: errors.New("down"), Message: "down"})
+	var checker healthapi.Checker = m
+
+	if res := checker.Check(); res.Error != nil {
+		t.Errorf("expected first result to be healthy, got %+v", res)
+	}
+	for i := 0; i < 2; i++ {
+		if res := checker.Check(); res.Message != "down" {
+			t.Errorf("expected last result to repeat, got %+v", res)
+		}
+	}
+	m.Push(healthapi.Result{Message: "recovered"})
+	if res := checker.Check(); res.Message != "recovered" || m.Calls() != 4 {
+		t.Errorf("unexpected result after %d calls: %+v", m.Calls(), res)
+	}
+
+	registry := health.NewRegistry()
+	registry.Register("mock", NewMockChecker(healthapi.Result{Error: errors.New("down")}))
+	AssertUnhealthy(t, registry, "mock")
+}
@@ -0,0 +1,41 @@
+package health
+
+import "errors"
+
+// Healthy evaluates the checks and reports whether none of the critical ones
+// fails, the condition under which status handlers respond 200 OK.
+func (registry *Registry) Healthy() bool {
+	return registry.Err() == nil
+}
+
+// Err evaluates the checks and returns an error joining the errors of the
+// failing critical checks, each wrapped in a *CheckError naming its check, or
+// nil if there are none. Failing Warning checks are left out.
+func (registry *Registry) Err() error {
+	checks := registry.CheckStatus()
+	var errs []error
+	for _, name := range sortedNames(checks) {
+		check := checks[name]
+		if check.Status() != StatusFail {
+			continue
+		}
+		err := check.err
+		if err == nil {
+			err = errors.New(check.Message)
+		}
+		errs = append(errs, &CheckError{Name: name, Cause: err})
+	}
+	return errors.Join(errs...)
+}
+
+// Healthy reports whether none of the critical checks in the default registry
+// fails.
+func Healthy() bool {
+	return Default().Healthy()
+}
+
+// Err returns an error joining the errors of the failing critical checks in
+// the default registry.
+func Err() error {
+	return Default().Err()
+}
@@ -0,0 +1,31 @@
+package health
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestHealthyErr ensures the errors of failing critical checks are joined.
+func TestHealthyErr(t *testing.T) {
+	registry := NewRegistry()
+	db := NewStatusUpdater()
+	cache := NewStatusUpdater()
+	registry.Register("db", db)
+	registry.Register("cache", cache, WithSeverity(Warning))
+
+	if !registry.Healthy() || registry.Err() != nil {
+		t.Errorf("expected registry to be healthy, got %v", registry.Err())
+	}
+
+	cache.Update(Result{Error: errors.New("evicted")})
+	if !registry.Healthy() {
+		t.Errorf("expected failing warning checks to be ignored, got %v", registry.Err())
+	}
+
+	errRefused := errors.New("connection refused")
+	db.Update(Result{Error: errRefused})
+	err := registry.Err()
+	if registry.Healthy() || !errors.Is(err, errRefused) || err.Error() != "db: connection refused" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
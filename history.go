@@ -0,0 +1,131 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// DefaultHistorySize is the number of results kept per check by registries
+// created without a WithHistory option.
+const DefaultHistorySize = 20
+
+// HistoryPath is the path RegisterRoutes mounts HistoryHandler at.
+const HistoryPath = "/debug/health/history"
+
+// HistoryEntry is a past result of a check.
+type HistoryEntry struct {
+	Healthy   bool      `json:"healthy"`
+	Message   string    `json:"message,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+	// DurationMs is how long the check took in milliseconds.
+	DurationMs float64 `json:"duration_ms"`
+}
+
+// WithHistory sets the number of past results the registry keeps per check,
+// for post-incident analysis of flapping checks. Zero disables the history.
+func WithHistory(n int) RegistryOption {
+	return func(registry *Registry) {
+		registry.historySize = n
+	}
+}
+
+// record adds res to the history of the check, keeping the last size
+// results.
+func (rc *registeredCheck) record(res Result, size int) {
+	if size <= 0 {
+		return
+	}
+	entry := HistoryEntry{
+		Healthy:    res.Error == nil,
+		Message:    res.Message,
+		CheckedAt:  res.CheckedAt,
+		DurationMs: float64(res.Duration) / float64(time.Millisecond),
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if len(rc.history) < size {
+		rc.history = append(rc.history, entry)
+		return
+	}
+	rc.history[rc.historyNext] = entry
+	rc.historyNext = (rc.historyNext + 1) % len(rc.history)
+}
+
+// entries returns the history of the check, oldest first. Messages of failing
+// results are redacted if all is set, or if redacted is set and the check is
+// sensitive.
+func (rc *registeredCheck) entries(redacted, all bool) []HistoryEntry {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	entries := make([]HistoryEntry, 0, len(rc.history))
+	entries = append(entries, rc.history[rc.historyNext:]...)
+	entries = append(entries, rc.history[:rc.historyNext]...)
+	if all || redacted && rc.sensitive {
+		for i := range entries {
+			if !entries[i].Healthy {
+				entries[i].Message = RedactedMessage
+			}
+		}
+	}
+	return entries
+}
+
+// History returns the last results of the named check, oldest first, or nil
+// if no such check is registered. Only actual runs are recorded, not results
+// reused within the TTL or skipped checks.
+func (registry *Registry) History(name string) []HistoryEntry {
+	registry.mu.RLock()
+	rc, ok := registry.registeredChecks[name]
+	registry.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return rc.entries(false, false)
+}
+
+// History returns the last results of the named check in the default
+// registry.
+func History(name string) []HistoryEntry {
+	return Default().History(name)
+}
+
+// HistoryHandler responds with the history of every check as a JSON object
+// keyed by check name, or of the check named by the "check" query parameter.
+// Failing results of sensitive checks are redacted.
+func (registry *Registry) HistoryHandler(w http.ResponseWriter, r *http.Request) {
+	registry.serveHistory(w, r, false)
+}
+
+// serveHistory responds with the history of the checks, redacting the
+// failing results of all checks if redactAll is set.
+func (registry *Registry) serveHistory(w http.ResponseWriter, r *http.Request, redactAll bool) {
+	if r.Method != "GET" {
+		http.NotFound(w, r)
+		return
+	}
+
+	registry.mu.RLock()
+	history := make(map[string][]HistoryEntry, len(registry.registeredChecks))
+	for name, rc := range registry.registeredChecks {
+		if wanted := r.URL.Query().Get("check"); wanted == "" || wanted == name {
+			history[name] = rc.entries(true, redactAll)
+		}
+	}
+	registry.mu.RUnlock()
+
+	p, err := json.Marshal(history)
+	if err != nil {
+		registry.log().Printf("error serializing health history: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, registry.log(), http.StatusOK, "application/json; charset=utf-8", p)
+}
+
+// HistoryHandler responds with the history of the checks in the default
+// registry.
+func HistoryHandler(w http.ResponseWriter, r *http.Request) {
+	Default().HistoryHandler(w, r)
+}
@@ -0,0 +1,60 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// TestHistory ensures the last results of a check are kept in order.
+func TestHistory(t *testing.T) {
+	registry := NewRegistry(WithHistory(3))
+	updater := NewStatusUpdater()
+	registry.Register("check", updater, Sensitive())
+
+	for i := 0; i < 5; i++ {
+		res := Result{Message: strconv.Itoa(i)}
+		if i%2 == 1 {
+			res.Error = errors.New("failing")
+		}
+		updater.Update(res)
+		registry.CheckStatus()
+	}
+
+	history := registry.History("check")
+	if len(history) != 3 {
+		t.Fatalf("expected 3 entries, got %+v", history)
+	}
+	for i, entry := range history {
+		if entry.Message != strconv.Itoa(i+2) || entry.Healthy != (i%2 == 0) {
+			t.Errorf("unexpected entry %d: %+v", i, entry)
+		}
+	}
+	if registry.History("missing") != nil {
+		t.Errorf("expected no history for unknown checks")
+	}
+
+	req, err := http.NewRequest("GET", "https://fakeurl.com"+HistoryPath+"?check=check", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request.")
+	}
+	recorder := httptest.NewRecorder()
+	registry.HistoryHandler(recorder, req)
+	var served map[string][]HistoryEntry
+	if err := json.Unmarshal(recorder.Body.Bytes(), &served); err != nil {
+		t.Fatalf("error decoding history: %v", err)
+	}
+	if entries := served["check"]; len(entries) != 3 || entries[1].Message != RedactedMessage || entries[2].Message != "4" {
+		t.Errorf("unexpected served history: %+v", served)
+	}
+
+	registry = NewRegistry(WithHistory(0))
+	registry.Register("check", updater)
+	registry.CheckStatus()
+	if history := registry.History("check"); len(history) != 0 {
+		t.Errorf("expected history to be disabled, got %+v", history)
+	}
+}
@@ -0,0 +1,24 @@
+package health
+
+// Hook wraps a check when the registry runs it, to add cross-cutting
+// behaviour such as logging, metrics, tracing or retries. It is called with
+// the name the check is registered under and returns the checker to run in
+// its place, usually one that calls next.
+type Hook func(name string, next CheckerWithContext) CheckerWithContext
+
+// RegisterHook adds a hook wrapping every check of the registry, including
+// checks registered before the hook. Hooks registered first are the
+// outermost.
+func (registry *Registry) RegisterHook(hook Hook) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	// copy on write, checks being run keep using the hooks they started with
+	hooks := make([]Hook, len(registry.hooks), len(registry.hooks)+1)
+	copy(hooks, registry.hooks)
+	registry.hooks = append(hooks, hook)
+}
+
+// RegisterHook adds a hook wrapping every check of the default registry.
+func RegisterHook(hook Hook) {
+	Default().RegisterHook(hook)
+}
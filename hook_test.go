@@ -0,0 +1,31 @@
+package health
+
+import (
+	"context"
+	"testing"
+)
+
+// TestRegisterHook ensures hooks wrap every check in registration order.
+func TestRegisterHook(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("check", CheckFunc(func() Result {
+		return Result{Message: "check"}
+	}))
+
+	prefix := func(p string) Hook {
+		return func(name string, next CheckerWithContext) CheckerWithContext {
+			return CheckFuncWithContext(func(ctx context.Context) Result {
+				res := next.Check(ctx)
+				res.Message = p + "(" + name + ":" + res.Message + ")"
+				return res
+			})
+		}
+	}
+	registry.RegisterHook(prefix("outer"))
+	registry.RegisterHook(prefix("inner"))
+
+	msg := registry.CheckStatus()["check"].Message
+	if expected := "outer(check:inner(check:check))"; msg != expected {
+		t.Errorf("unexpected message: %q != %q", msg, expected)
+	}
+}
@@ -0,0 +1,39 @@
+// Package httpjson posts JSON documents to webhooks, for the sinks and
+// notification targets of the health packages.
+package httpjson
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// Post POSTs body encoded as JSON to the webhook at url, failing if it does
+// not respond with a 2xx status code. A nil client uses http.DefaultClient.
+func Post(ctx context.Context, client *http.Client, url string, body any) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	p, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(p))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.New("webhook returned unexpected status: " + strconv.Itoa(resp.StatusCode))
+	}
+	return nil
+}
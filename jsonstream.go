@@ -0,0 +1,130 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// streamBufferSize is how much of a streamed response is buffered before it
+// is written. Responses fitting in the buffer are written at once, with a
+// Content-Length.
+const streamBufferSize = 32 << 10
+
+// jsonStream writes a JSON response in chunks of streamBufferSize, so the
+// whole response is never held in memory. The headers are written with the
+// first chunk.
+type jsonStream struct {
+	w      http.ResponseWriter
+	status int
+	buf    []byte
+	// started is set once the headers are written.
+	started bool
+	err     error
+}
+
+// write appends p to the response, writing the buffer when full.
+func (s *jsonStream) write(p []byte) {
+	if s.err != nil {
+		return
+	}
+	s.buf = append(s.buf, p...)
+	if len(s.buf) >= streamBufferSize {
+		if !s.started {
+			s.started = true
+			s.w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			s.w.WriteHeader(s.status)
+		}
+		_, s.err = s.w.Write(s.buf)
+		s.buf = s.buf[:0]
+	}
+}
+
+// value appends v serialized as JSON to the response.
+func (s *jsonStream) value(v any) error {
+	p, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	s.write(p)
+	return nil
+}
+
+// checks appends the checks as a JSON object to the response, one check at
+// a time in the order of their names. The output is the same as
+// json.Marshal's.
+func (s *jsonStream) checks(checks Status) error {
+	if checks == nil {
+		s.write([]byte("null"))
+		return nil
+	}
+	s.write([]byte("{"))
+	for i, name := range sortedNames(checks) {
+		if i > 0 {
+			s.write([]byte(","))
+		}
+		if err := s.value(name); err != nil {
+			return err
+		}
+		s.write([]byte(":"))
+		if err := s.value(checks[name]); err != nil {
+			return err
+		}
+	}
+	s.write([]byte("}"))
+	return nil
+}
+
+// envelopeFields is a StatusEnvelope without its checks: the nil Checks
+// field hides the one of StatusEnvelope and is omitted.
+type envelopeFields struct {
+	*StatusEnvelope
+	Checks *struct{} `json:"checks,omitempty"`
+}
+
+// envelope appends envelope as a JSON object to the response. The fields
+// other than the checks are serialized by json.Marshal, then the checks, the
+// last field of StatusEnvelope, are streamed.
+func (s *jsonStream) envelope(envelope *StatusEnvelope) error {
+	p, err := json.Marshal(envelopeFields{StatusEnvelope: envelope})
+	if err != nil {
+		return err
+	}
+	s.write(p[:len(p)-1])
+	s.write([]byte(`,"checks":`))
+	if err := s.checks(envelope.Checks); err != nil {
+		return err
+	}
+	s.write([]byte("}"))
+	return nil
+}
+
+// streamResponse completes the request with the checks, wrapped in envelope
+// if set, as JSON. The checks are serialized one at a time in the order of
+// their names and written as they are, so large registries do not need the
+// whole response in memory. Like statusResponse, it responds with an error
+// if the checks can not be serialized, unless part of the response was
+// already written.
+func streamResponse(w http.ResponseWriter, logger Logger, status int, checks Status, envelope *StatusEnvelope) {
+	s := &jsonStream{w: w, status: status}
+	var err error
+	if envelope != nil {
+		err = s.envelope(envelope)
+	} else {
+		err = s.checks(checks)
+	}
+
+	switch {
+	case err != nil && !s.started:
+		serializationFailed(w, logger, "application/json; charset=utf-8", err)
+	case err != nil:
+		logger.Printf("error serializing health status: %v", err)
+	case s.err != nil:
+		logger.Printf("error writing health status response body: %v", s.err)
+	case !s.started:
+		writeResponse(w, logger, status, "application/json; charset=utf-8", s.buf)
+	default:
+		if _, err := w.Write(s.buf); err != nil {
+			logger.Printf("error writing health status response body: %v", err)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package health
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestStreamResponse ensures streamed responses are identical to
+// json.Marshal's output.
+func TestStreamResponse(t *testing.T) {
+	lastSuccess := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for _, n := range []int{0, 3, 1000} {
+		checks := make(Status, n)
+		for i := 0; i < n; i++ {
+			checks["check<"+strconv.Itoa(i)+">"] = HealthCheck{
+				Healthy:     i%2 == 0,
+				Message:     "message & " + strconv.Itoa(i),
+				Severity:    Critical,
+				State:       StateHealthy,
+				DurationMs:  float64(i) / 3,
+				LastChecked: lastSuccess,
+				LastSuccess: &lastSuccess,
+				Details:     map[string]any{"b": i, "a": []string{"x"}},
+			}
+		}
+		envelope := &StatusEnvelope{
+			Status:    StatusFail,
+			Checks:    checks,
+			Timestamp: lastSuccess,
+			Score:     33.3,
+			Build:     &BuildInfo{Version: "1.0.0"},
+		}
+
+		for _, tc := range []struct {
+			name     string
+			envelope *StatusEnvelope
+			body     any
+		}{
+			{"bare", nil, checks},
+			{"envelope", envelope, envelope},
+		} {
+			expected, err := json.Marshal(tc.body)
+			if err != nil {
+				t.Fatalf("error serializing: %v", err)
+			}
+			recorder := httptest.NewRecorder()
+			streamResponse(recorder, DefaultLogger(), http.StatusTeapot, checks, tc.envelope)
+			if recorder.Code != http.StatusTeapot {
+				t.Errorf("%d %s: unexpected status code: %d", n, tc.name, recorder.Code)
+			}
+			if !bytes.Equal(recorder.Body.Bytes(), expected) {
+				t.Errorf("%d %s: unexpected body:\n%s\nexpected:\n%s", n, tc.name, recorder.Body.String(), expected)
+			}
+			streamed := len(expected) > streamBufferSize
+			if (recorder.Header().Get("Content-Length") == "") != streamed {
+				t.Errorf("%d %s: unexpected Content-Length %q for %d bytes", n, tc.name, recorder.Header().Get("Content-Length"), len(expected))
+			}
+		}
+	}
+}
+
+// TestStreamResponseError ensures checks failing to serialize are reported.
+func TestStreamResponseError(t *testing.T) {
+	var logs bytes.Buffer
+	logger := log.New(&logs, "", 0)
+
+	checks := Status{
+		"broken": {Details: map[string]any{"ch": make(chan int)}},
+	}
+	recorder := httptest.NewRecorder()
+	streamResponse(recorder, logger, http.StatusOK, checks, nil)
+	if recorder.Code != http.StatusInternalServerError || !strings.Contains(recorder.Body.String(), "server_error") {
+		t.Errorf("expected a server error, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	// checks are streamed in order, so the broken one comes after the buffer
+	// was written
+	checks = Status{"zzz": checks["broken"]}
+	for i := 0; i < 1000; i++ {
+		checks["check"+strconv.Itoa(i)] = HealthCheck{Message: strings.Repeat("x", 100)}
+	}
+	logs.Reset()
+	recorder = httptest.NewRecorder()
+	streamResponse(recorder, logger, http.StatusOK, checks, nil)
+	if recorder.Code != http.StatusOK || json.Valid(recorder.Body.Bytes()) {
+		t.Errorf("expected a truncated response, got %d", recorder.Code)
+	}
+	if !strings.Contains(logs.String(), "error serializing health status") {
+		t.Errorf("expected the error to be logged, got %q", logs.String())
+	}
+}
@@ -0,0 +1,154 @@
+// Package k8s serves the startup, readiness and liveness probes of
+// Kubernetes from a health.Registry, with the semantics each probe needs:
+//
+//   - the startup probe fails until its checks succeeded once, and passes
+//     from then on, so slow starts are not mistaken for failures without
+//     evaluating the checks for the rest of the life of the process
+//   - the readiness probe always includes the readiness checks, so it fails
+//     while the registry is draining or in maintenance
+//   - the liveness probe only evaluates the checks registered with the
+//     health.Liveness kind, so failing dependencies get the pod taken out
+//     of rotation rather than restarted
+//
+// Which kinds of checks participate in which probe can be changed with
+// options.
+//
+//	mux := http.NewServeMux()
+//	k8s.RegisterProbes(mux, health.DefaultRegistry)
+package k8s
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/docker/distribution/health"
+)
+
+// Paths of the probes mounted by RegisterProbes.
+const (
+	StartupPath   = "/startupz"
+	ReadinessPath = health.ReadyzPath
+	LivenessPath  = health.LivezPath
+)
+
+// Probes are the handlers of the three probes.
+type Probes struct {
+	Startup   http.Handler
+	Readiness http.Handler
+	Liveness  http.Handler
+}
+
+// config holds the configuration of the probes.
+type config struct {
+	startup, readiness, liveness health.Kind
+	handlerOpts                  []health.HandlerOption
+}
+
+// Option configures the probes.
+type Option func(*config)
+
+// WithStartupKind sets the kinds of checks evaluated by the startup probe.
+// The default is health.Startup.
+func WithStartupKind(kind health.Kind) Option {
+	return func(c *config) {
+		c.startup = kind
+	}
+}
+
+// WithReadinessKind sets the kinds of checks evaluated by the readiness
+// probe in addition to health.Readiness, e.g. health.Startup to keep the
+// service out of rotation while starting up.
+func WithReadinessKind(kind health.Kind) Option {
+	return func(c *config) {
+		c.readiness = kind
+	}
+}
+
+// WithLivenessKind sets the kinds of checks evaluated by the liveness probe.
+// The default is health.Liveness. Including health.Readiness makes failing
+// dependencies restart the pod, which rarely helps.
+func WithLivenessKind(kind health.Kind) Option {
+	return func(c *config) {
+		c.liveness = kind
+	}
+}
+
+// WithHandlerOptions creates the handlers of the probes with opts, e.g. to
+// require authentication or set status codes.
+func WithHandlerOptions(opts ...health.HandlerOption) Option {
+	return func(c *config) {
+		c.handlerOpts = append(c.handlerOpts, opts...)
+	}
+}
+
+// NewProbes returns the probes for the checks in registry.
+func NewProbes(registry *health.Registry, opts ...Option) *Probes {
+	c := config{
+		startup:   health.Startup,
+		readiness: health.Readiness,
+		liveness:  health.Liveness,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	handler := func(kind health.Kind) http.Handler {
+		return registry.Handler(append(append([]health.HandlerOption(nil), c.handlerOpts...), health.ForKind(kind))...)
+	}
+	// degraded and initializing checks fail the startup probe, so it only
+	// latches once its checks all pass
+	startup := &startupHandler{probe: registry.Handler(append(append([]health.HandlerOption(nil), c.handlerOpts...),
+		health.ForKind(c.startup),
+		health.WithDegradedStatus(http.StatusServiceUnavailable),
+		health.WithInitializingStatus(http.StatusServiceUnavailable))...)}
+	return &Probes{
+		Startup:   health.Authorized(startup, c.handlerOpts...),
+		Readiness: handler(c.readiness | health.Readiness),
+		Liveness:  handler(c.liveness),
+	}
+}
+
+// Register mounts the probes on mux at StartupPath, ReadinessPath and
+// LivenessPath.
+func (p *Probes) Register(mux *http.ServeMux) {
+	mux.Handle(StartupPath, p.Startup)
+	mux.Handle(ReadinessPath, p.Readiness)
+	mux.Handle(LivenessPath, p.Liveness)
+}
+
+// RegisterProbes mounts the probes for the checks in registry on mux.
+func RegisterProbes(mux *http.ServeMux, registry *health.Registry, opts ...Option) {
+	NewProbes(registry, opts...).Register(mux)
+}
+
+// startupHandler serves probe until it succeeds once, and then reports that
+// the service started. The requests are authorized before it is called.
+type startupHandler struct {
+	probe   http.Handler
+	started atomic.Bool
+}
+
+// ServeHTTP implements http.Handler
+func (h *startupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.started.Load() {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte("started\n"))
+		return
+	}
+	sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+	h.probe.ServeHTTP(sw, r)
+	if sw.status >= 200 && sw.status < 300 {
+		h.started.Store(true)
+	}
+}
+
+// statusWriter records the status code of a response.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader implements http.ResponseWriter
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
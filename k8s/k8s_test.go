@@ -0,0 +1,121 @@
+package k8s
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/docker/distribution/health"
+)
+
+func TestProbes(t *testing.T) {
+	var warm atomic.Bool
+	var runs atomic.Int32
+	registry := health.NewRegistry()
+	registry.Register("cache warm", health.CheckFunc(func() health.Result {
+		runs.Add(1)
+		if !warm.Load() {
+			return health.Result{Error: errors.New("warming up")}
+		}
+		return health.Result{}
+	}), health.WithKind(health.Startup))
+	registry.Register("db", health.CheckFunc(func() health.Result {
+		return health.Result{Error: errors.New("down")}
+	}))
+	registry.Register("deadlock", health.CheckFunc(func() health.Result { return health.Result{} }), health.WithKind(health.Liveness))
+
+	mux := http.NewServeMux()
+	RegisterProbes(mux, registry)
+	get := func(path string) int {
+		req, err := http.NewRequest("GET", "https://fakeurl.com"+path, nil)
+		if err != nil {
+			t.Fatalf("Failed to create request.")
+		}
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	if code := get(StartupPath); code != http.StatusServiceUnavailable {
+		t.Errorf("expected startup to fail while warming up, got %d", code)
+	}
+	warm.Store(true)
+	if code := get(StartupPath); code != http.StatusOK {
+		t.Errorf("expected startup to pass once warm, got %d", code)
+	}
+	warm.Store(false)
+	if code := get(StartupPath); code != http.StatusOK || runs.Load() != 2 {
+		t.Errorf("expected startup to keep passing without evaluating checks, got %d after %d runs", code, runs.Load())
+	}
+
+	if code := get(ReadinessPath); code != http.StatusServiceUnavailable {
+		t.Errorf("expected readiness to fail with a failing dependency, got %d", code)
+	}
+	if code := get(LivenessPath); code != http.StatusOK {
+		t.Errorf("expected liveness to ignore dependencies, got %d", code)
+	}
+}
+
+func TestReadinessHonorsDrain(t *testing.T) {
+	registry := health.NewRegistry()
+	registry.Register("warm", health.CheckFunc(func() health.Result { return health.Result{} }), health.WithKind(health.Startup))
+	probes := NewProbes(registry, WithReadinessKind(health.Startup))
+
+	req, err := http.NewRequest("GET", "https://fakeurl.com"+ReadinessPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to create request.")
+	}
+	recorder := httptest.NewRecorder()
+	probes.Readiness.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected readiness to pass, got %d", recorder.Code)
+	}
+
+	registry.SetDraining(true)
+	recorder = httptest.NewRecorder()
+	probes.Readiness.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected readiness to fail while draining, got %d", recorder.Code)
+	}
+}
+
+func TestStartupLatch(t *testing.T) {
+	var ready atomic.Bool
+	registry := health.NewRegistry()
+	registry.Register("optional", health.CheckFunc(func() health.Result {
+		if !ready.Load() {
+			return health.Result{Error: errors.New("not yet")}
+		}
+		return health.Result{}
+	}), health.WithKind(health.Startup), health.WithSeverity(health.Warning))
+	probes := NewProbes(registry, WithHandlerOptions(health.WithBearerToken("token"), health.WithSuccessStatus(http.StatusNoContent)))
+	get := func(token string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("GET", "https://fakeurl.com"+StartupPath, nil)
+		if err != nil {
+			t.Fatalf("Failed to create request.")
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		recorder := httptest.NewRecorder()
+		probes.Startup.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	if recorder := get("token"); recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected startup to fail on a failing warning check, got %d %s", recorder.Code, recorder.Body)
+	}
+	ready.Store(true)
+	if recorder := get("token"); recorder.Code != http.StatusNoContent {
+		t.Errorf("expected startup to pass once all checks pass, got %d %s", recorder.Code, recorder.Body)
+	}
+	ready.Store(false)
+	if recorder := get("token"); recorder.Body.String() != "started\n" {
+		t.Errorf("expected startup to stay started, got %d %s", recorder.Code, recorder.Body)
+	}
+	if recorder := get(""); recorder.Code != http.StatusUnauthorized {
+		t.Errorf("expected startup to require authorization once started, got %d", recorder.Code)
+	}
+}
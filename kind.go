@@ -0,0 +1,96 @@
+package health
+
+import (
+	"context"
+	"net/http"
+)
+
+// Kind classifies a check by the kind of probe it participates in. Kinds can
+// be combined, e.g. Liveness|Readiness.
+type Kind uint8
+
+const (
+	// Liveness checks report whether the process is able to make progress at
+	// all. A failing liveness check usually gets the process restarted.
+	Liveness Kind = 1 << iota
+
+	// Readiness checks report whether the process is able to serve traffic.
+	// This is the kind of checks registered without a WithKind option.
+	Readiness
+
+	// Startup checks report whether the process has finished starting up.
+	Startup
+)
+
+// String returns the name of the kind.
+func (k Kind) String() string {
+	switch k {
+	case Liveness:
+		return "liveness"
+	case Readiness:
+		return "readiness"
+	case Startup:
+		return "startup"
+	}
+	return "unknown"
+}
+
+// WithKind sets the kind of probes the check participates in.
+func WithKind(kind Kind) CheckOption {
+	return func(rc *registeredCheck) {
+		rc.kind = kind
+	}
+}
+
+// ofKind returns a filter selecting checks of the given kind.
+func ofKind(kind Kind) func(*registeredCheck) bool {
+	return func(rc *registeredCheck) bool {
+		return rc.kind&kind != 0
+	}
+}
+
+// ForKind makes the handler evaluate only the checks of the given kind.
+func ForKind(kind Kind) HandlerOption {
+	return func(c *handlerConfig) {
+		c.include = ofKind(kind)
+	}
+}
+
+// CheckStatusKind returns a map with the current results of the checks of the
+// given kind.
+func (registry *Registry) CheckStatusKind(ctx context.Context, kind Kind) Status {
+	return registry.checkStatus(ctx, ofKind(kind))
+}
+
+// LiveHandler responds like StatusHandler, evaluating only Liveness checks.
+func (registry *Registry) LiveHandler(w http.ResponseWriter, r *http.Request) {
+	registry.Handler(ForKind(Liveness)).ServeHTTP(w, r)
+}
+
+// ReadyHandler responds like StatusHandler, evaluating only Readiness checks.
+func (registry *Registry) ReadyHandler(w http.ResponseWriter, r *http.Request) {
+	registry.Handler(ForKind(Readiness)).ServeHTTP(w, r)
+}
+
+// StartupHandler responds like StatusHandler, evaluating only Startup checks.
+func (registry *Registry) StartupHandler(w http.ResponseWriter, r *http.Request) {
+	registry.Handler(ForKind(Startup)).ServeHTTP(w, r)
+}
+
+// LiveHandler responds with the status of the Liveness checks in the default
+// registry.
+func LiveHandler(w http.ResponseWriter, r *http.Request) {
+	Default().LiveHandler(w, r)
+}
+
+// ReadyHandler responds with the status of the Readiness checks in the
+// default registry.
+func ReadyHandler(w http.ResponseWriter, r *http.Request) {
+	Default().ReadyHandler(w, r)
+}
+
+// StartupHandler responds with the status of the Startup checks in the
+// default registry.
+func StartupHandler(w http.ResponseWriter, r *http.Request) {
+	Default().StartupHandler(w, r)
+}
@@ -0,0 +1,50 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestKindHandlers ensures each probe handler only evaluates checks of its
+// kind.
+func TestKindHandlers(t *testing.T) {
+	registry := NewRegistry()
+	failing := CheckFunc(func() Result {
+		return Result{Error: errors.New("failing")}
+	})
+	passing := CheckFunc(func() Result {
+		return Result{}
+	})
+
+	registry.Register("database", failing)
+	registry.Register("deadlock", passing, WithKind(Liveness))
+	registry.Register("warmup", failing, WithKind(Startup|Readiness))
+
+	for _, tc := range []struct {
+		name    string
+		handler http.HandlerFunc
+		code    int
+	}{
+		{"live", registry.LiveHandler, http.StatusOK},
+		{"ready", registry.ReadyHandler, http.StatusServiceUnavailable},
+		{"startup", registry.StartupHandler, http.StatusServiceUnavailable},
+	} {
+		req, err := http.NewRequest("GET", "https://fakeurl.com/"+tc.name, nil)
+		if err != nil {
+			t.Fatalf("Failed to create request.")
+		}
+		recorder := httptest.NewRecorder()
+		tc.handler(recorder, req)
+
+		if recorder.Code != tc.code {
+			t.Errorf("%s: unexpected response code: %d != %d", tc.name, recorder.Code, tc.code)
+		}
+	}
+
+	if status := registry.CheckStatusKind(context.Background(), Readiness); len(status) != 2 {
+		t.Errorf("expected 2 readiness checks, got %v", status)
+	}
+}
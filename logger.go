@@ -0,0 +1,70 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"sync"
+)
+
+// Logger logs errors the package can not return, like failures writing a
+// response. It is implemented by *log.Logger.
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
+// stdLogger logs with the standard logger of the log package.
+type stdLogger struct{}
+
+// Printf implements the Logger interface
+func (stdLogger) Printf(format string, v ...any) {
+	log.Printf(format, v...)
+}
+
+// slogLogger adapts a *slog.Logger to the Logger interface.
+type slogLogger struct {
+	logger *slog.Logger
+	level  slog.Level
+}
+
+// Printf implements the Logger interface
+func (l slogLogger) Printf(format string, v ...any) {
+	l.logger.Log(context.Background(), l.level, fmt.Sprintf(format, v...))
+}
+
+// NewSlogLogger returns a Logger logging messages to logger at the given
+// level.
+func NewSlogLogger(logger *slog.Logger, level slog.Level) Logger {
+	return slogLogger{logger: logger, level: level}
+}
+
+var (
+	defaultLoggerMu sync.RWMutex
+	defaultLogger   Logger = stdLogger{}
+)
+
+// SetDefaultLogger sets the logger used by registries created without a
+// WithLogger option, including DefaultRegistry, and by the subpackages. By
+// default messages go to the standard logger of the log package. Libraries
+// embedding this package can silence it with log.New(io.Discard, "", 0).
+func SetDefaultLogger(logger Logger) {
+	defaultLoggerMu.Lock()
+	defer defaultLoggerMu.Unlock()
+	defaultLogger = logger
+}
+
+// DefaultLogger returns the logger set with SetDefaultLogger.
+func DefaultLogger() Logger {
+	defaultLoggerMu.RLock()
+	defer defaultLoggerMu.RUnlock()
+	return defaultLogger
+}
+
+// log returns the logger of the registry.
+func (registry *Registry) log() Logger {
+	if registry.logger != nil {
+		return registry.logger
+	}
+	return DefaultLogger()
+}
@@ -0,0 +1,45 @@
+package health
+
+import (
+	"bytes"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestLoggers ensures handler errors go to the configured loggers.
+func TestLoggers(t *testing.T) {
+	unserializable := CheckFunc(func() Result {
+		return Result{Details: map[string]any{"channel": make(chan int)}}
+	})
+	get := func(registry *Registry) {
+		req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+		if err != nil {
+			t.Fatalf("Failed to create request.")
+		}
+		registry.StatusHandler(httptest.NewRecorder(), req)
+	}
+
+	var global bytes.Buffer
+	SetDefaultLogger(log.New(&global, "", 0))
+	defer SetDefaultLogger(stdLogger{})
+
+	registry := NewRegistry()
+	registry.Register("check", unserializable)
+	get(registry)
+	if !strings.Contains(global.String(), "error serializing health status") {
+		t.Errorf("expected error in default logger, got %q", global.String())
+	}
+
+	var own bytes.Buffer
+	registry = NewRegistry(WithLogger(NewSlogLogger(slog.New(slog.NewTextHandler(&own, nil)), slog.LevelError)))
+	registry.Register("check", unserializable)
+	global.Reset()
+	get(registry)
+	if !strings.Contains(own.String(), "level=ERROR") || global.Len() != 0 {
+		t.Errorf("expected error only in registry logger, got %q and %q", own.String(), global.String())
+	}
+}
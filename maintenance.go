@@ -0,0 +1,137 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const (
+	// MaintenanceCheckName is the name of the failing readiness check
+	// reported while a registry is in maintenance mode.
+	MaintenanceCheckName = "maintenance"
+	// MaintenancePath is the conventional path of MaintenanceHandler. It is
+	// not mounted by RegisterRoutes, as it has to be protected.
+	MaintenancePath = "/debug/health/maintenance"
+)
+
+// maintenance is the maintenance mode of a registry.
+type maintenance struct {
+	reason string
+	since  time.Time
+}
+
+// maintenanceChecker returns the check reported while the registry is in
+// maintenance mode.
+func maintenanceChecker(registry *Registry) CheckerWithContext {
+	return CheckFuncWithContext(func(ctx context.Context) Result {
+		registry.mu.RLock()
+		m := registry.maintenance
+		registry.mu.RUnlock()
+		if m == nil {
+			return Result{}
+		}
+		return Result{
+			Error:   errors.New("maintenance: " + m.reason),
+			Message: m.reason,
+			Details: map[string]any{"since": m.since.UTC()},
+		}
+	})
+}
+
+// SetMaintenance puts the registry in maintenance mode for reason. Until
+// ClearMaintenance is called, a failing readiness check named
+// MaintenanceCheckName is reported with reason as its message, so readiness
+// fails regardless of the other checks while they keep being reported.
+func (registry *Registry) SetMaintenance(reason string) {
+	if reason == "" {
+		reason = "service is under maintenance"
+	}
+	registry.mu.Lock()
+	registry.maintenance = &maintenance{reason: reason, since: registry.clock.Now()}
+	registry.mu.Unlock()
+	registry.log().Printf("health: maintenance mode enabled: %s", reason)
+}
+
+// ClearMaintenance ends the maintenance mode of the registry.
+func (registry *Registry) ClearMaintenance() {
+	registry.mu.Lock()
+	enabled := registry.maintenance != nil
+	registry.maintenance = nil
+	registry.mu.Unlock()
+	if enabled {
+		registry.log().Printf("health: maintenance mode disabled")
+	}
+}
+
+// Maintenance returns the reason the registry is in maintenance mode, and
+// whether it is.
+func (registry *Registry) Maintenance() (string, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	if registry.maintenance == nil {
+		return "", false
+	}
+	return registry.maintenance.reason, true
+}
+
+// SetMaintenance puts the default registry in maintenance mode.
+func SetMaintenance(reason string) {
+	Default().SetMaintenance(reason)
+}
+
+// ClearMaintenance ends the maintenance mode of the default registry.
+func ClearMaintenance() {
+	Default().ClearMaintenance()
+}
+
+// maintenanceState is the response body of MaintenanceHandler.
+type maintenanceState struct {
+	Maintenance bool       `json:"maintenance"`
+	Reason      string     `json:"reason,omitempty"`
+	Since       *time.Time `json:"since,omitempty"`
+}
+
+// MaintenanceHandler returns an http.Handler toggling the maintenance mode of
+// the registry: POST enables it with the reason form value, DELETE disables
+// it. Every request is answered with the current mode. Only the
+// authorization options among opts apply, and as anyone reaching the handler
+// can fail readiness, one should be given.
+func (registry *Registry) MaintenanceHandler(opts ...HandlerOption) http.Handler {
+	var c handlerConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !c.authorize(w, r) {
+			return
+		}
+		switch r.Method {
+		case "GET":
+		case "POST":
+			registry.SetMaintenance(r.FormValue("reason"))
+		case "DELETE":
+			registry.ClearMaintenance()
+		default:
+			w.Header().Set("Allow", "GET, POST, DELETE")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		var state maintenanceState
+		registry.mu.RLock()
+		if m := registry.maintenance; m != nil {
+			since := m.since.UTC()
+			state = maintenanceState{Maintenance: true, Reason: m.reason, Since: &since}
+		}
+		registry.mu.RUnlock()
+		statusResponse(w, r, registry.log(), http.StatusOK, "application/json; charset=utf-8", state)
+	})
+}
+
+// MaintenanceHandler returns an http.Handler toggling the maintenance mode of
+// the default registry.
+func MaintenanceHandler(opts ...HandlerOption) http.Handler {
+	return Default().MaintenanceHandler(opts...)
+}
@@ -0,0 +1,100 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// TestSetMaintenance ensures maintenance mode fails readiness with its reason
+// but not liveness.
+func TestSetMaintenance(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("db", CheckFunc(func() Result { return Result{} }))
+	registry.Register("deadlock", CheckFunc(func() Result { return Result{} }), WithKind(Liveness))
+
+	registry.SetMaintenance("upgrading the database")
+	if reason, ok := registry.Maintenance(); !ok || reason != "upgrading the database" {
+		t.Fatalf("registry was expected to be in maintenance, got %q, %v", reason, ok)
+	}
+
+	ready := registry.CheckStatusKind(context.Background(), Readiness)
+	if check, ok := ready[MaintenanceCheckName]; !ok || check.Healthy || check.Message != "upgrading the database" {
+		t.Errorf("readiness was expected to fail in maintenance: %v", ready)
+	}
+	if !ready["db"].Healthy {
+		t.Errorf("other checks were expected to still be reported: %v", ready)
+	}
+	live := registry.CheckStatusKind(context.Background(), Liveness)
+	if _, ok := live[MaintenanceCheckName]; ok {
+		t.Errorf("liveness was expected to be unaffected by maintenance: %v", live)
+	}
+
+	req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request.")
+	}
+	recorder := httptest.NewRecorder()
+	registry.Handler(ForKind(Readiness)).ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 in maintenance, got %d", recorder.Code)
+	}
+
+	registry.ClearMaintenance()
+	if _, ok := registry.CheckStatus()[MaintenanceCheckName]; ok {
+		t.Errorf("maintenance check was expected to be gone")
+	}
+}
+
+// TestMaintenanceHandler ensures the handler toggles maintenance mode for
+// authorized requests only.
+func TestMaintenanceHandler(t *testing.T) {
+	registry := NewRegistry()
+	handler := registry.MaintenanceHandler(WithBearerToken("secret"))
+
+	do := func(method string, body string, token string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(method, "https://fakeurl.com"+MaintenancePath, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Failed to create request.")
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	form := url.Values{"reason": {"deploying"}}.Encode()
+	if recorder := do("POST", form, ""); recorder.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without token, got %d", recorder.Code)
+	}
+	if _, ok := registry.Maintenance(); ok {
+		t.Fatalf("unauthorized request was not expected to enable maintenance")
+	}
+
+	recorder := do("POST", form, "secret")
+	var state maintenanceState
+	if err := json.Unmarshal(recorder.Body.Bytes(), &state); err != nil {
+		t.Fatalf("error decoding response: %v", err)
+	}
+	if !state.Maintenance || state.Reason != "deploying" || state.Since == nil {
+		t.Errorf("expected maintenance to be enabled, got %+v", state)
+	}
+	if reason, ok := registry.Maintenance(); !ok || reason != "deploying" {
+		t.Errorf("registry was expected to be in maintenance, got %q, %v", reason, ok)
+	}
+
+	do("DELETE", "", "secret")
+	if _, ok := registry.Maintenance(); ok {
+		t.Errorf("maintenance was expected to be disabled")
+	}
+	if recorder := do("PUT", "", "secret"); recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for PUT, got %d", recorder.Code)
+	}
+}
@@ -0,0 +1,20 @@
+package health
+
+// Metadata describes the component behind a check. It is static and set at
+// registration with WithMetadata, so the health page doubles as an inventory
+// of the dependencies of the service.
+type Metadata struct {
+	// ComponentType is the kind of component, like "database" or "cache".
+	ComponentType string `json:"component_type,omitempty"`
+	// Version is the version of the component.
+	Version string `json:"version,omitempty"`
+	// RunbookURL links to the documentation on operating the component.
+	RunbookURL string `json:"runbook_url,omitempty"`
+}
+
+// WithMetadata reports metadata with the results of the check.
+func WithMetadata(metadata Metadata) CheckOption {
+	return func(rc *registeredCheck) {
+		rc.metadata = &metadata
+	}
+}
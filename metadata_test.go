@@ -0,0 +1,42 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestMetadata ensures the metadata of checks is reported in responses.
+func TestMetadata(t *testing.T) {
+	metadata := Metadata{
+		ComponentType: "database",
+		Version:       "15.4",
+		RunbookURL:    "https://runbooks.example.com/postgres",
+	}
+	registry := NewRegistry()
+	registry.Register("postgres", CheckFunc(func() Result { return Result{Error: errors.New("down")} }), WithMetadata(metadata))
+	registry.Register("plain", CheckFunc(func() Result { return Result{} }))
+	registry.Register("dependent", CheckFunc(func() Result { return Result{} }), WithDependencies("postgres"), WithMetadata(metadata))
+
+	req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request.")
+	}
+	recorder := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(recorder, req)
+
+	var status Status
+	if err := json.Unmarshal(recorder.Body.Bytes(), &status); err != nil {
+		t.Fatalf("error decoding response: %v", err)
+	}
+	for _, name := range []string{"postgres", "dependent"} {
+		if got := status[name].Metadata; got == nil || *got != metadata {
+			t.Errorf("expected metadata %+v for %s, got %+v", metadata, name, got)
+		}
+	}
+	if status["plain"].Metadata != nil {
+		t.Errorf("expected no metadata for check registered without it, got %+v", status["plain"].Metadata)
+	}
+}
@@ -0,0 +1,89 @@
+package health
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// MetricsPath is the path MetricsHandler is mounted at by RegisterRoutes.
+const MetricsPath = "/debug/health/metrics"
+
+// OpenMetricsContentType is the media type of the responses of
+// MetricsHandler.
+const OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// MetricsHandler evaluates the checks and responds with their status in the
+// OpenMetrics text format, so they can be scraped without the Prometheus
+// client library:
+//
+//	health_check_status{check="db",severity="critical"} 1
+//	health_check_duration_seconds{check="db"} 0.0012
+//	health_check_consecutive_failures{check="db"} 0
+//	health_score 100
+func (registry *Registry) MetricsHandler(w http.ResponseWriter, r *http.Request) {
+	registry.serveMetrics(w, r, false)
+}
+
+// serveMetrics responds with the status of the checks as OpenMetrics. The
+// metrics carry no messages, so there is nothing to redact.
+func (registry *Registry) serveMetrics(w http.ResponseWriter, r *http.Request, _ bool) {
+	if r.Method != "GET" {
+		http.NotFound(w, r)
+		return
+	}
+	checks := registry.checkStatus(r.Context(), func(*registeredCheck) bool { return true })
+	names := sortedNames(checks)
+
+	var buf bytes.Buffer
+	family := func(name, help string, value func(HealthCheck) string, labels func(string, HealthCheck) string) {
+		buf.WriteString("# TYPE " + name + " gauge\n")
+		buf.WriteString("# HELP " + name + " " + help + "\n")
+		for _, check := range names {
+			buf.WriteString(name + "{" + labels(check, checks[check]) + "} " + value(checks[check]) + "\n")
+		}
+	}
+	checkLabel := func(name string, _ HealthCheck) string {
+		return `check="` + escapeLabel(name) + `"`
+	}
+
+	family("health_check_status", "Whether the check is healthy (1) or not (0).",
+		func(check HealthCheck) string {
+			if check.Healthy {
+				return "1"
+			}
+			return "0"
+		},
+		func(name string, check HealthCheck) string {
+			return checkLabel(name, check) + `,severity="` + escapeLabel(string(check.Severity)) + `"`
+		})
+	family("health_check_duration_seconds", "How long the last run of the check took.",
+		func(check HealthCheck) string {
+			return strconv.FormatFloat(check.DurationMs/1000, 'g', -1, 64)
+		}, checkLabel)
+	family("health_check_consecutive_failures", "The number of consecutive failed runs of the check.",
+		func(check HealthCheck) string {
+			return strconv.Itoa(check.failures)
+		}, checkLabel)
+	buf.WriteString("# TYPE health_score gauge\n")
+	buf.WriteString("# HELP health_score The weighted health of the service between 0 and 100.\n")
+	buf.WriteString("health_score " + strconv.FormatFloat(score(checks), 'g', -1, 64) + "\n")
+	buf.WriteString("# EOF\n")
+
+	writeResponse(w, registry.log(), http.StatusOK, OpenMetricsContentType, buf.Bytes())
+}
+
+// MetricsHandler responds with the status of the checks in the default
+// registry in the OpenMetrics text format.
+func MetricsHandler(w http.ResponseWriter, r *http.Request) {
+	Default().MetricsHandler(w, r)
+}
+
+// labelEscaper escapes label values in the OpenMetrics text format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel escapes a label value.
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
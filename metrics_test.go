@@ -0,0 +1,48 @@
+package health
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMetricsHandler ensures the checks are rendered in the OpenMetrics text
+// format.
+func TestMetricsHandler(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("db", CheckFunc(func() Result {
+		return Result{CheckedAt: time.Now(), Duration: 1500 * time.Microsecond}
+	}))
+	registry.Register(`cache "eu"`, CheckFunc(func() Result { return Result{Error: errors.New("down")} }), WithSeverity(Warning))
+	registry.CheckStatus()
+
+	req, err := http.NewRequest("GET", "https://fakeurl.com"+MetricsPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to create request.")
+	}
+	recorder := httptest.NewRecorder()
+	registry.MetricsHandler(recorder, req)
+
+	if recorder.Header().Get("Content-Type") != OpenMetricsContentType {
+		t.Errorf("unexpected content type %q", recorder.Header().Get("Content-Type"))
+	}
+	body := recorder.Body.String()
+	for _, line := range []string{
+		"# TYPE health_check_status gauge",
+		`health_check_status{check="cache \"eu\"",severity="warning"} 0`,
+		`health_check_status{check="db",severity="critical"} 1`,
+		`health_check_duration_seconds{check="db"} 0.0015`,
+		`health_check_consecutive_failures{check="cache \"eu\""} 2`,
+		`health_score 50`,
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("expected %q in the response:\n%s", line, body)
+		}
+	}
+	if !strings.HasSuffix(body, "# EOF\n") {
+		t.Errorf("expected the response to end with # EOF:\n%s", body)
+	}
+}
@@ -0,0 +1,85 @@
+package health
+
+import (
+	"net/http"
+	"strings"
+)
+
+// middleware sheds the traffic of a handler while the checks of a registry
+// fail.
+type middleware struct {
+	registry *Registry
+	// allowed are the paths always served, matching by prefix when they
+	// end with a slash.
+	allowed []string
+}
+
+// MiddlewareOption configures a middleware created by Middleware.
+type MiddlewareOption func(*middleware)
+
+// WithAllowedPaths adds paths that are served even while checks fail. Paths
+// ending with a slash allow every path below them. The endpoints mounted by
+// RegisterRoutes are always allowed.
+func WithAllowedPaths(paths ...string) MiddlewareOption {
+	return func(m *middleware) {
+		m.allowed = append(m.allowed, paths...)
+	}
+}
+
+// Middleware returns a function wrapping an application handler so that it
+// responds 503 Service Unavailable while critical checks of the registry
+// fail, shedding load until the service recovers. The checks are evaluated
+// on every request, so expensive ones should run in the background, see
+// PeriodicChecker. The draining and maintenance checks are ignored, letting
+// in-flight work complete. Requests for the health endpoints, and for the
+// paths allowed with WithAllowedPaths, are always passed through. A nil
+// registry is the default registry.
+func Middleware(registry *Registry, opts ...MiddlewareOption) func(http.Handler) http.Handler {
+	m := &middleware{
+		registry: registry,
+		allowed: []string{
+			DefaultPath, DefaultPath + "/",
+			HealthzPath, ReadyzPath, LivezPath,
+		},
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !m.allow(r) && !m.healthy(r) {
+				http.Error(w, "service unavailable: failing health checks", http.StatusServiceUnavailable)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Handler wraps handler so that it responds 503 Service Unavailable while
+// critical checks of the default registry fail, see Middleware.
+func Handler(handler http.Handler) http.Handler {
+	return Middleware(nil)(handler)
+}
+
+// allow reports whether r is for an allowed path.
+func (m *middleware) allow(r *http.Request) bool {
+	for _, path := range m.allowed {
+		if r.URL.Path == path || strings.HasSuffix(path, "/") && strings.HasPrefix(r.URL.Path, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// healthy reports whether no critical check of the registry fails.
+func (m *middleware) healthy(r *http.Request) bool {
+	registry := m.registry
+	if registry == nil {
+		registry = Default()
+	}
+	checks := registry.checkStatus(r.Context(), func(rc *registeredCheck) bool {
+		return rc != registry.drainCheck && rc != registry.maintenanceCheck
+	})
+	return overallStatus(checks) != StatusFail
+}
@@ -0,0 +1,61 @@
+package health
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestMiddleware ensures the middleware sheds traffic while critical checks
+// fail, except for allowed paths.
+func TestMiddleware(t *testing.T) {
+	registry := NewRegistry()
+	warning := NewStatusUpdater()
+	critical := NewStatusUpdater()
+	registry.Register("warning", warning, WithSeverity(Warning))
+	registry.Register("critical", critical)
+
+	mux := http.NewServeMux()
+	registry.RegisterRoutes(mux)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	handler := Middleware(registry, WithAllowedPaths("/admin/"))(mux)
+
+	get := func(path string) int {
+		req, err := http.NewRequest("GET", "https://fakeurl.com"+path, nil)
+		if err != nil {
+			t.Fatalf("Failed to create request.")
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	warning.Update(Result{Error: errors.New("slow")})
+	registry.SetDraining(true)
+	if code := get("/api"); code != http.StatusNoContent {
+		t.Errorf("expected traffic to be served with failing warnings while draining, got %d", code)
+	}
+	registry.SetDraining(false)
+
+	critical.Update(Result{Error: errors.New("down")})
+	for path, expected := range map[string]int{
+		"/api":         http.StatusServiceUnavailable,
+		"/admin/stats": http.StatusNoContent,
+		"/admin":       http.StatusServiceUnavailable,
+		ReadyzPath:     http.StatusServiceUnavailable,
+		LivezPath:      http.StatusOK,
+		HistoryPath:    http.StatusOK,
+	} {
+		if code := get(path); code != expected {
+			t.Errorf("%s: expected %d, got %d", path, expected, code)
+		}
+	}
+
+	critical.Update(Result{})
+	if code := get("/api"); code != http.StatusNoContent {
+		t.Errorf("expected traffic to be served once recovered, got %d", code)
+	}
+}
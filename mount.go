@@ -0,0 +1,131 @@
+package health
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MountSeparator separates the prefix of a mounted registry from the names
+// of its checks.
+const MountSeparator = "/"
+
+// Mount adds the checks of child to the registry, named with prefix and
+// MountSeparator in front of their names, so libraries can ship a registry of
+// their own checks that applications mount. The checks are evaluated by
+// child, with its options, whenever the registry is, and count towards its
+// aggregate status. Checks can not depend on the checks of other registries.
+// Mount panics if the prefix is already mounted or child contains the
+// registry.
+func (registry *Registry) Mount(prefix string, child *Registry) {
+	if child == registry || child.contains(registry) {
+		panic("Registry mounted within itself")
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if _, ok := registry.mounts[prefix]; ok {
+		panic("Registry already mounted at " + prefix)
+	}
+	if registry.mounts == nil {
+		registry.mounts = make(map[string]*Registry)
+	}
+	registry.mounts[prefix] = child
+}
+
+// Unmount removes the registry mounted at prefix.
+func (registry *Registry) Unmount(prefix string) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	delete(registry.mounts, prefix)
+}
+
+// Mount adds the checks of child to the default registry under prefix.
+func Mount(prefix string, child *Registry) {
+	Default().Mount(prefix, child)
+}
+
+// Unmount removes the registry mounted at prefix from the default registry.
+func Unmount(prefix string) {
+	Default().Unmount(prefix)
+}
+
+// contains reports whether other is mounted in the registry, directly or
+// not.
+func (registry *Registry) contains(other *Registry) bool {
+	for _, child := range registry.mounted() {
+		if child == other || child.contains(other) {
+			return true
+		}
+	}
+	return false
+}
+
+// mounted returns the registries mounted in the registry by prefix.
+func (registry *Registry) mounted() map[string]*Registry {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	mounts := make(map[string]*Registry, len(registry.mounts))
+	for prefix, child := range registry.mounts {
+		mounts[prefix] = child
+	}
+	return mounts
+}
+
+// lookup returns the check registered as name, in the registry or in the
+// registries mounted in it, or nil if there is none.
+func (registry *Registry) lookup(name string) *registeredCheck {
+	registry.mu.RLock()
+	rc, ok := registry.registeredChecks[name]
+	switch {
+	case ok:
+	case name == DrainingCheckName:
+		rc = registry.drainCheck
+	case name == MaintenanceCheckName:
+		rc = registry.maintenanceCheck
+	}
+	registry.mu.RUnlock()
+	if rc != nil {
+		return rc
+	}
+
+	for prefix, child := range registry.mounted() {
+		if rest, ok := strings.CutPrefix(name, prefix+MountSeparator); ok {
+			if rc := child.lookup(rest); rc != nil {
+				return rc
+			}
+		}
+	}
+	return nil
+}
+
+// mountedStatus evaluates the checks of the mounted registries selected by
+// include, prefixing their names.
+func (registry *Registry) mountedStatus(ctx context.Context, include func(*registeredCheck) bool) Status {
+	mounts := registry.mounted()
+	prefixes := make([]string, 0, len(mounts))
+	for prefix := range mounts {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+
+	results := make([]Status, len(prefixes))
+	var wg sync.WaitGroup
+	for i, prefix := range prefixes {
+		wg.Add(1)
+		go func(i int, child *Registry) {
+			defer wg.Done()
+			results[i] = child.checkStatus(ctx, include)
+		}(i, mounts[prefix])
+	}
+	wg.Wait()
+
+	status := make(Status)
+	for i, prefix := range prefixes {
+		for name, check := range results[i] {
+			status[prefix+MountSeparator+name] = check
+		}
+	}
+	return status
+}
@@ -0,0 +1,51 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// TestMount ensures the checks of mounted registries are namespaced and
+// roll up into the aggregate status.
+func TestMount(t *testing.T) {
+	storage := NewRegistry()
+	storage.Register("disk", CheckFunc(func() Result { return Result{} }))
+	s3 := NewRegistry()
+	s3.Register("bucket", CheckFunc(func() Result { return Result{Error: errors.New("forbidden")} }), WithKind(Liveness))
+	storage.Mount("s3", s3)
+
+	registry := NewRegistry()
+	registry.Register("db", CheckFunc(func() Result { return Result{} }))
+	registry.Mount("storage", storage)
+
+	status := registry.CheckStatus()
+	if len(status) != 3 || !status["storage/disk"].Healthy || status["storage/s3/bucket"].Healthy {
+		t.Errorf("expected the namespaced checks of the mounted registries, got %v", status)
+	}
+	if registry.Healthy() {
+		t.Errorf("expected failing mounted checks to fail the registry")
+	}
+	if ready := registry.CheckStatusKind(context.Background(), Readiness); len(ready) != 2 {
+		t.Errorf("expected filters to apply to mounted checks, got %v", ready)
+	}
+
+	assertPanics(t, func() { s3.Mount("loop", registry) }, "mounting a registry within itself")
+	assertPanics(t, func() { registry.Mount("storage", NewRegistry()) }, "mounting twice at the same prefix")
+
+	registry.Unmount("storage")
+	if status := registry.CheckStatus(); len(status) != 1 {
+		t.Errorf("expected the mounted checks to be gone, got %v", status)
+	}
+}
+
+// assertPanics ensures f panics.
+func assertPanics(t *testing.T, f func(), what string) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected %s to panic", what)
+		}
+	}()
+	f()
+}
@@ -0,0 +1,98 @@
+package health
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Exit codes of Nagios plugins, returned by Status.Nagios.
+const (
+	NagiosOK       = 0
+	NagiosWarning  = 1
+	NagiosCritical = 2
+	NagiosUnknown  = 3
+)
+
+// nagiosLabels are the service states of the exit codes.
+var nagiosLabels = [...]string{"OK", "WARNING", "CRITICAL", "UNKNOWN"}
+
+// NagiosLabel returns the service state of a Nagios plugin exit code, like
+// "WARNING", or "UNKNOWN" for codes outside the convention.
+func NagiosLabel(code int) string {
+	if code < 0 || code >= len(nagiosLabels) {
+		return nagiosLabels[NagiosUnknown]
+	}
+	return nagiosLabels[code]
+}
+
+// Nagios formats the checks following the Nagios plugin conventions, so they
+// can be consumed by Nagios-compatible schedulers such as Icinga. It returns
+// the plugin output and its exit code: NagiosOK, NagiosWarning if only
+// warning checks fail or checks are degraded, and NagiosCritical if critical
+// checks fail. The first line of the output summarizes the status with the
+// durations of the checks as perfdata, the following lines list the checks
+// that are not healthy:
+//
+//	HEALTH CRITICAL - 1 of 2 checks failing | 'cache'=0.210ms 'database'=1.500ms
+//	CRITICAL database: connection refused
+func (s Status) Nagios() (string, int) {
+	code := NagiosOK
+	switch s.Overall() {
+	case StatusWarn:
+		code = NagiosWarning
+	case StatusFail:
+		code = NagiosCritical
+	}
+
+	var (
+		failing  int
+		details  strings.Builder
+		perfdata []string
+	)
+	for _, name := range sortedNames(s) {
+		check := s[name]
+		perfdata = append(perfdata, nagiosLabel(name)+"="+strconv.FormatFloat(check.DurationMs, 'f', 3, 64)+"ms")
+		status := check.Status()
+		if status == StatusPass {
+			continue
+		}
+		if !check.Healthy {
+			failing++
+		}
+		label := "WARNING"
+		if status == StatusFail {
+			label = "CRITICAL"
+		}
+		details.WriteString("\n" + label + " " + name)
+		if check.Message != "" {
+			details.WriteString(": " + nagiosEscape(check.Message))
+		}
+	}
+
+	summary := "HEALTH " + NagiosLabel(code) + " - "
+	switch {
+	case len(s) == 0:
+		summary += "no checks"
+	case failing > 0:
+		summary += strconv.Itoa(failing) + " of " + strconv.Itoa(len(s)) + " checks failing"
+	case code == NagiosWarning:
+		summary += "checks degraded"
+	default:
+		summary += strconv.Itoa(len(s)) + " checks passing"
+	}
+	if len(perfdata) > 0 {
+		summary += " | " + strings.Join(perfdata, " ")
+	}
+	return summary + details.String(), code
+}
+
+// nagiosLabel quotes name as a perfdata label.
+func nagiosLabel(name string) string {
+	return "'" + strings.ReplaceAll(name, "'", "''") + "'"
+}
+
+// nagiosEscape keeps message on a single line without the perfdata
+// separator.
+func nagiosEscape(message string) string {
+	return strings.NewReplacer("|", "/", "\n", " ", "\r", " ").Replace(message)
+}
@@ -0,0 +1,61 @@
+package health
+
+import (
+	"testing"
+)
+
+// TestNagios ensures checks are formatted following the Nagios plugin
+// conventions.
+func TestNagios(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		checks Status
+		output string
+		code   int
+	}{
+		{
+			name:   "empty",
+			checks: Status{},
+			output: "HEALTH OK - no checks",
+			code:   NagiosOK,
+		},
+		{
+			name: "passing",
+			checks: Status{
+				"db":    {Healthy: true, Severity: Critical, DurationMs: 1.5},
+				"cache": {Healthy: true, Severity: Critical, DurationMs: 0.25},
+			},
+			output: "HEALTH OK - 2 checks passing | 'cache'=0.250ms 'db'=1.500ms",
+			code:   NagiosOK,
+		},
+		{
+			name: "degraded",
+			checks: Status{
+				"db": {Healthy: true, Severity: Critical, State: StateDegraded, Message: "replica lagging"},
+			},
+			output: "HEALTH WARNING - checks degraded | 'db'=0.000ms\nWARNING db: replica lagging",
+			code:   NagiosWarning,
+		},
+		{
+			name: "failing",
+			checks: Status{
+				"db":          {Healthy: false, Severity: Critical, Message: "connection refused"},
+				"cache":       {Healthy: false, Severity: Warning, Message: "a | b\nc"},
+				"it's a test": {Healthy: true, Severity: Critical},
+			},
+			output: "HEALTH CRITICAL - 2 of 3 checks failing | 'cache'=0.000ms 'db'=0.000ms 'it''s a test'=0.000ms\n" +
+				"WARNING cache: a / b c\n" +
+				"CRITICAL db: connection refused",
+			code: NagiosCritical,
+		},
+	} {
+		output, code := tc.checks.Nagios()
+		if output != tc.output || code != tc.code {
+			t.Errorf("%s: unexpected output (%d):\n%s\nexpected (%d):\n%s", tc.name, code, output, tc.code, tc.output)
+		}
+	}
+
+	if NagiosLabel(NagiosWarning) != "WARNING" || NagiosLabel(42) != "UNKNOWN" {
+		t.Errorf("unexpected labels")
+	}
+}
@@ -0,0 +1,293 @@
+// Package notify sends notifications to webhooks when the checks of a
+// health.Registry change state.
+//
+// A Notifier watches a registry and notifies its targets when a check, or the
+// aggregate status of the registry, changes between healthy and unhealthy:
+//
+//	n := notify.New(health.DefaultRegistry,
+//		notify.WithTarget(notify.Slack("https://hooks.slack.com/services/...")),
+//		notify.WithDebounce(30*time.Second))
+//	go n.Run(ctx)
+//
+// Like health.Registry.Watch, changes are only observed when the checks are
+// evaluated. The checks are evaluated again on every change to tell whether
+// the aggregate status changed.
+package notify
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/docker/distribution/health"
+)
+
+// Event is a state change sent to the targets.
+type Event struct {
+	// Check is the name of the check that changed, or empty if the event is
+	// about the aggregate status of the registry.
+	Check   string    `json:"check,omitempty"`
+	Healthy bool      `json:"healthy"`
+	Message string    `json:"message,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// Target receives events.
+type Target interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+// TargetFunc is a convenience type to create functions that implement the
+// Target interface
+type TargetFunc func(ctx context.Context, event Event) error
+
+// Notify implements the Target interface
+func (tf TargetFunc) Notify(ctx context.Context, event Event) error {
+	return tf(ctx, event)
+}
+
+// Notifier notifies targets of state changes of the checks in a registry.
+type Notifier struct {
+	registry *health.Registry
+	targets  []Target
+	checks   map[string]bool
+	debounce time.Duration
+	attempts int
+	backoff  time.Duration
+	logger   health.Logger
+}
+
+// Option configures a Notifier created by New.
+type Option func(*Notifier)
+
+// WithTarget adds a target to notify.
+func WithTarget(target Target) Option {
+	return func(n *Notifier) {
+		n.targets = append(n.targets, target)
+	}
+}
+
+// WithChecks limits the notifications about individual checks to the named
+// ones. Changes of the aggregate status are always notified.
+func WithChecks(names ...string) Option {
+	return func(n *Notifier) {
+		n.checks = make(map[string]bool, len(names))
+		for _, name := range names {
+			n.checks[name] = true
+		}
+	}
+}
+
+// WithDebounce delays notifications until the state has been stable for d.
+// Changes that revert within d are not notified.
+func WithDebounce(d time.Duration) Option {
+	return func(n *Notifier) {
+		n.debounce = d
+	}
+}
+
+// WithRetry makes up to attempts attempts to notify a target, waiting backoff
+// between them, doubling it after every attempt.
+func WithRetry(attempts int, backoff time.Duration) Option {
+	return func(n *Notifier) {
+		n.attempts = attempts
+		n.backoff = backoff
+	}
+}
+
+// WithLogger sets the logger failed notifications are logged to. By default
+// health.DefaultLogger is used.
+func WithLogger(logger health.Logger) Option {
+	return func(n *Notifier) {
+		n.logger = logger
+	}
+}
+
+// New returns a Notifier for the checks in registry. It does nothing until
+// Run is called.
+func New(registry *health.Registry, opts ...Option) *Notifier {
+	n := &Notifier{
+		registry: registry,
+		attempts: 1,
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	return n
+}
+
+// Run watches the registry and notifies the targets of changes until ctx is
+// done. Every target is notified in the order of the changes, by a worker of
+// its own so a slow target does not delay the others. Notifications in
+// flight are waited for before Run returns.
+func (n *Notifier) Run(ctx context.Context) {
+	var (
+		changes = n.registry.Watch(ctx)
+		healthy = true
+		current = map[string]Event{}
+		sent    = map[string]bool{}
+		timers  = map[string]*time.Timer{}
+		fire    = make(chan string)
+		workers = make([]*worker, len(n.targets))
+		wg      sync.WaitGroup
+	)
+	for i, target := range n.targets {
+		workers[i] = &worker{target: target, wake: make(chan struct{}, 1)}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n.work(ctx, workers[i])
+		}()
+	}
+	defer wg.Wait()
+	defer func() {
+		for _, t := range timers {
+			t.Stop()
+		}
+	}()
+
+	// sent holds the last healthy state notified per key, every key starts
+	// out healthy.
+	isSent := func(key string) bool {
+		healthy, ok := sent[key]
+		return !ok || healthy
+	}
+	dispatch := func(key string) {
+		event := current[key]
+		if event.Healthy == isSent(key) {
+			return
+		}
+		sent[key] = event.Healthy
+		for _, w := range workers {
+			w.push(event)
+		}
+	}
+	update := func(key string, event Event) {
+		current[key] = event
+		if n.debounce <= 0 {
+			dispatch(key)
+			return
+		}
+		if t, ok := timers[key]; ok {
+			t.Stop()
+		}
+		timers[key] = time.AfterFunc(n.debounce, func() {
+			select {
+			case fire <- key:
+			case <-ctx.Done():
+			}
+		})
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case key := <-fire:
+			delete(timers, key)
+			dispatch(key)
+		case change, ok := <-changes:
+			if !ok {
+				return
+			}
+			event := Event{
+				Check:   change.Name,
+				Healthy: change.Healthy,
+				Message: change.Message,
+				Time:    change.Time,
+			}
+			if n.checks == nil || n.checks[change.Name] {
+				update(change.Name, event)
+			}
+
+			// the aggregate status is evaluated again rather than derived
+			// from the changes, which Watch drops when it falls behind
+			wasHealthy := healthy
+			healthy = n.registry.CheckStatusContext(ctx).Overall() != health.StatusFail
+			if ctx.Err() != nil {
+				return
+			}
+			if healthy != wasHealthy {
+				update("", Event{Healthy: healthy, Message: event.Message, Time: event.Time})
+			}
+		}
+	}
+}
+
+// worker queues the events of a target.
+type worker struct {
+	target Target
+	// wake is signaled when an event is queued.
+	wake chan struct{}
+
+	mu    sync.Mutex
+	queue []Event
+}
+
+// push queues event without blocking.
+func (w *worker) push(event Event) {
+	w.mu.Lock()
+	w.queue = append(w.queue, event)
+	w.mu.Unlock()
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// pop returns the next queued event, and false if there is none.
+func (w *worker) pop() (Event, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.queue) == 0 {
+		return Event{}, false
+	}
+	event := w.queue[0]
+	w.queue = w.queue[1:]
+	return event, true
+}
+
+// work sends the events queued in w one at a time until ctx is done.
+func (n *Notifier) work(ctx context.Context, w *worker) {
+	for {
+		event, ok := w.pop()
+		if !ok {
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.wake:
+			}
+			continue
+		}
+		n.send(ctx, w.target, event)
+	}
+}
+
+// send notifies target of event, retrying failed attempts.
+func (n *Notifier) send(ctx context.Context, target Target, event Event) {
+	backoff := n.backoff
+	for attempt := 1; ; attempt++ {
+		err := target.Notify(ctx, event)
+		if err == nil {
+			return
+		}
+		if attempt >= n.attempts {
+			n.log().Printf("error sending health notification: %v", err)
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// log returns the logger of the notifier.
+func (n *Notifier) log() health.Logger {
+	if n.logger != nil {
+		return n.logger
+	}
+	return health.DefaultLogger()
+}
@@ -0,0 +1,206 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/distribution/health"
+)
+
+// recorder is a Target recording the events it receives.
+type recorder struct {
+	mu     sync.Mutex
+	events []Event
+	fail   int
+}
+
+func (r *recorder) Notify(ctx context.Context, event Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.fail > 0 {
+		r.fail--
+		return errors.New("target unavailable")
+	}
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *recorder) received() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Event(nil), r.events...)
+}
+
+// waitFor waits until r received n events.
+func (r *recorder) waitFor(t *testing.T, n int) []Event {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if events := r.received(); len(events) >= n {
+			return events
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d events, got %v", n, r.received())
+	return nil
+}
+
+func TestNotifier(t *testing.T) {
+	registry := health.NewRegistry()
+	updater := health.NewStatusUpdater()
+	registry.Register("database", updater)
+
+	target := &recorder{fail: 1}
+	n := New(registry, WithTarget(target), WithRetry(2, time.Millisecond))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go n.Run(ctx)
+	time.Sleep(10 * time.Millisecond)
+
+	updater.Update(health.Result{Error: errors.New("failing"), Message: "connection refused"})
+	registry.CheckStatus()
+	events := target.waitFor(t, 2)
+
+	seen := map[string]Event{}
+	for _, event := range events {
+		seen[event.Check] = event
+	}
+	if event, ok := seen["database"]; !ok || event.Healthy || event.Message != "connection refused" {
+		t.Errorf("unexpected check event: %+v", event)
+	}
+	if event, ok := seen[""]; !ok || event.Healthy {
+		t.Errorf("unexpected aggregate event: %+v", event)
+	}
+}
+
+func TestNotifierDebounce(t *testing.T) {
+	registry := health.NewRegistry()
+	updater := health.NewStatusUpdater()
+	registry.Register("database", updater)
+
+	target := &recorder{}
+	n := New(registry, WithTarget(target), WithChecks(), WithDebounce(50*time.Millisecond))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		n.Run(ctx)
+		close(done)
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	// a flap shorter than the debounce is not notified
+	updater.Update(health.Result{Error: errors.New("failing")})
+	registry.CheckStatus()
+	updater.Update(health.Result{})
+	registry.CheckStatus()
+	time.Sleep(100 * time.Millisecond)
+	if events := target.received(); len(events) != 0 {
+		t.Errorf("unexpected events: %v", events)
+	}
+
+	updater.Update(health.Result{Error: errors.New("failing")})
+	registry.CheckStatus()
+	events := target.waitFor(t, 1)
+	if len(events) != 1 || events[0].Check != "" || events[0].Healthy {
+		t.Errorf("expected a single aggregate event, got %v", events)
+	}
+
+	cancel()
+	<-done
+}
+
+// TestNotifierOrder ensures a target receives the events of a check in the
+// order of its changes, even when it is slow.
+func TestNotifierOrder(t *testing.T) {
+	registry := health.NewRegistry()
+	updater := health.NewStatusUpdater()
+	registry.Register("database", updater)
+
+	target := &recorder{}
+	slow := TargetFunc(func(ctx context.Context, event Event) error {
+		if event.Check == "" {
+			return nil
+		}
+		time.Sleep(5 * time.Millisecond)
+		return target.Notify(ctx, event)
+	})
+	n := New(registry, WithTarget(slow))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go n.Run(ctx)
+	time.Sleep(10 * time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		updater.Update(health.Result{Error: errors.New("failing")})
+		registry.CheckStatus()
+		updater.Update(health.Result{})
+		registry.CheckStatus()
+	}
+	events := target.waitFor(t, 6)
+	for i, event := range events {
+		if event.Healthy != (i%2 == 1) {
+			t.Fatalf("events out of order: %v", events)
+		}
+	}
+}
+
+// TestNotifierWarning ensures failing warning checks are notified without
+// changing the aggregate status.
+func TestNotifierWarning(t *testing.T) {
+	registry := health.NewRegistry()
+	updater := health.NewStatusUpdater()
+	registry.Register("cache", updater, health.WithSeverity(health.Warning))
+
+	target := &recorder{}
+	n := New(registry, WithTarget(target))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go n.Run(ctx)
+	time.Sleep(10 * time.Millisecond)
+
+	updater.Update(health.Result{Error: errors.New("failing")})
+	registry.CheckStatus()
+	target.waitFor(t, 1)
+	time.Sleep(20 * time.Millisecond)
+	if events := target.received(); len(events) != 1 || events[0].Check != "cache" || events[0].Healthy {
+		t.Errorf("expected a single check event, got %v", events)
+	}
+}
+
+// TestNotifierAggregate ensures the aggregate status follows the status of
+// the registry, even when changes were not observed.
+func TestNotifierAggregate(t *testing.T) {
+	registry := health.NewRegistry()
+	database := health.NewStatusUpdater()
+	registry.Register("database", database)
+	database.Update(health.Result{Error: errors.New("failing")})
+	// the failure happens before the notifier watches the registry
+	registry.CheckStatus()
+
+	cache := health.NewStatusUpdater()
+	registry.Register("cache", cache)
+	target := &recorder{}
+	n := New(registry, WithTarget(target), WithChecks())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go n.Run(ctx)
+	time.Sleep(10 * time.Millisecond)
+
+	cache.Update(health.Result{Error: errors.New("failing")})
+	registry.CheckStatus()
+	target.waitFor(t, 1)
+	cache.Update(health.Result{})
+	registry.CheckStatus()
+	time.Sleep(20 * time.Millisecond)
+	if events := target.received(); len(events) != 1 || events[0].Healthy {
+		t.Errorf("expected the registry to stay unhealthy while database fails, got %v", events)
+	}
+
+	database.Update(health.Result{})
+	registry.CheckStatus()
+	if events := target.waitFor(t, 2); !events[1].Healthy {
+		t.Errorf("expected the registry to recover, got %v", events)
+	}
+}
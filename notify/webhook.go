@@ -0,0 +1,37 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/docker/distribution/health/internal/httpjson"
+)
+
+// Webhook returns a Target POSTing events as JSON to url. A nil client uses
+// http.DefaultClient.
+func Webhook(url string, client *http.Client) Target {
+	return TargetFunc(func(ctx context.Context, event Event) error {
+		return httpjson.Post(ctx, client, url, event)
+	})
+}
+
+// Slack returns a Target posting events as messages to a Slack incoming
+// webhook.
+func Slack(webhookURL string) Target {
+	return TargetFunc(func(ctx context.Context, event Event) error {
+		subject := "Service"
+		if event.Check != "" {
+			subject = "Check " + event.Check
+		}
+		text := subject + " is healthy"
+		if !event.Healthy {
+			text = subject + " is unhealthy"
+		}
+		if event.Message != "" {
+			text += ": " + event.Message
+		}
+		return httpjson.Post(ctx, nil, webhookURL, struct {
+			Text string `json:"text"`
+		}{text})
+	})
+}
@@ -0,0 +1,39 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhook(t *testing.T) {
+	var received Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	event := Event{Check: "database", Message: "connection refused"}
+	if err := Webhook(server.URL, nil).Notify(context.Background(), event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if received.Check != "database" || received.Healthy || received.Message != "connection refused" {
+		t.Errorf("unexpected event received: %+v", received)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	if err := Webhook(failing.URL, failing.Client()).Notify(context.Background(), event); err == nil {
+		t.Errorf("webhook returning 500 was expected to fail")
+	}
+}
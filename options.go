@@ -0,0 +1,67 @@
+package health
+
+import (
+	"time"
+)
+
+// RegistryOption configures a Registry created by NewRegistry.
+type RegistryOption func(*Registry)
+
+// WithMaxConcurrency limits the number of checks the registry runs
+// concurrently when computing its status. A value of zero or less, the
+// default, runs all checks at once.
+func WithMaxConcurrency(n int) RegistryOption {
+	return func(registry *Registry) {
+		registry.maxConcurrency = n
+	}
+}
+
+// WithDefaultTimeout limits how long a check may take, unless it was
+// registered with a WithTimeout option. Checks that time out are reported as
+// failing. By default checks have no timeout.
+func WithDefaultTimeout(timeout time.Duration) RegistryOption {
+	return func(registry *Registry) {
+		registry.timeout = timeout
+	}
+}
+
+// WithResultTTL makes the registry reuse the result of a check for ttl
+// instead of running it again, protecting expensive checks from frequent
+// polling. By default checks run on every evaluation.
+func WithResultTTL(ttl time.Duration) RegistryOption {
+	return func(registry *Registry) {
+		registry.ttl = ttl
+	}
+}
+
+// WithClock sets the clock the registry uses to timestamp and cache results,
+// for the grace period and for its scheduler, see Registry.Scheduler.
+func WithClock(clock Clock) RegistryOption {
+	return func(registry *Registry) {
+		registry.clock = clock
+	}
+}
+
+// WithLogger sets the logger of the registry and its handlers. By default the
+// DefaultLogger is used.
+func WithLogger(logger Logger) RegistryOption {
+	return func(registry *Registry) {
+		registry.logger = logger
+	}
+}
+
+// WithSchedulerOptions configures the scheduler of the registry, see
+// Registry.Scheduler.
+func WithSchedulerOptions(opts ...SchedulerOption) RegistryOption {
+	return func(registry *Registry) {
+		registry.schedulerOpts = append(registry.schedulerOpts, opts...)
+	}
+}
+
+// WithTimeout limits how long the check may take, overriding the registry's
+// default timeout.
+func WithTimeout(timeout time.Duration) CheckOption {
+	return func(rc *registeredCheck) {
+		rc.timeout = timeout
+	}
+}
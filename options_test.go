@@ -0,0 +1,119 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestWithDefaultTimeout ensures slow checks are reported as failing once
+// they exceed their timeout.
+func TestWithDefaultTimeout(t *testing.T) {
+	registry := NewRegistry(WithDefaultTimeout(10 * time.Millisecond))
+	slow := CheckFuncWithContext(func(ctx context.Context) Result {
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+		}
+		return Result{}
+	})
+	ignoring := CheckFunc(func() Result {
+		time.Sleep(50 * time.Millisecond)
+		return Result{}
+	})
+	registry.RegisterWithContext("slow", slow)
+	registry.Register("ignoring", ignoring)
+	registry.RegisterWithContext("patient", slow, WithTimeout(time.Millisecond*20))
+
+	start := time.Now()
+	status := registry.CheckStatus()
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("checks were expected to time out, took %v", elapsed)
+	}
+	for _, name := range []string{"slow", "ignoring", "patient"} {
+		if check := status[name]; check.Healthy {
+			t.Errorf("%s: check was expected to time out", name)
+		}
+	}
+	if msg := status["slow"].Message; msg != "check timed out after 10ms" {
+		t.Errorf("unexpected message: %q", msg)
+	}
+}
+
+// TestWithResultTTL ensures results are reused while they are fresh.
+func TestWithResultTTL(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)}
+	registry := NewRegistry(WithResultTTL(time.Minute), WithClock(clock))
+	runs := 0
+	registry.Register("check", CheckFunc(func() Result {
+		runs++
+		return Result{}
+	}))
+
+	registry.CheckStatus()
+	clock.Add(30 * time.Second)
+	status := registry.CheckStatus()
+	if runs != 1 {
+		t.Errorf("check was expected to run once within the TTL, ran %d times", runs)
+	}
+	if !status["check"].LastChecked.Equal(time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected last checked time: %v", status["check"].LastChecked)
+	}
+
+	clock.Add(30 * time.Second)
+	registry.CheckStatus()
+	if runs != 2 {
+		t.Errorf("check was expected to run again after the TTL, ran %d times", runs)
+	}
+}
+
+// TestConcurrentRunsShared ensures concurrent evaluations share a single run
+// of a check.
+func TestConcurrentRunsShared(t *testing.T) {
+	registry := NewRegistry()
+	var mu sync.Mutex
+	runs := 0
+	release := make(chan struct{})
+	registry.Register("slow", CheckFunc(func() Result {
+		mu.Lock()
+		runs++
+		mu.Unlock()
+		<-release
+		return Result{Message: "done"}
+	}))
+
+	var wg sync.WaitGroup
+	results := make(chan Status, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- registry.CheckStatus()
+		}()
+	}
+	// give every evaluation time to join the run
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(results)
+
+	if runs != 1 {
+		t.Errorf("check was expected to run once, ran %d times", runs)
+	}
+	for status := range results {
+		if status["slow"].Message != "done" {
+			t.Errorf("unexpected shared result: %+v", status["slow"])
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	release = make(chan struct{})
+	go registry.CheckStatus()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if status := registry.CheckStatusContext(ctx); status["slow"].Healthy {
+		t.Errorf("expected waiting evaluation to be cancelled, got %+v", status["slow"])
+	}
+	close(release)
+}
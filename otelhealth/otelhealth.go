@@ -0,0 +1,70 @@
+// Package otelhealth traces the execution of health checks with
+// OpenTelemetry.
+//
+// Every check run gets its own span. When the checks are run by the status
+// handler, the spans are children of the span of the incoming request, if
+// the handler is instrumented, e.g. with otelhttp.
+//
+//	health.RegisterHook(otelhealth.Hook())
+package otelhealth
+
+import (
+	"context"
+
+	"github.com/docker/distribution/health"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies this package as the instrumentation library.
+const instrumentationName = "github.com/docker/distribution/health/otelhealth"
+
+// config holds the configuration of a Hook.
+type config struct {
+	tracerProvider trace.TracerProvider
+}
+
+// Option configures a Hook.
+type Option func(*config)
+
+// WithTracerProvider sets the TracerProvider creating the spans. By default
+// the global TracerProvider is used.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// Hook returns a health.Hook that starts a span for each check run, recording
+// the check name, its outcome and message.
+func Hook(opts ...Option) health.Hook {
+	c := config{
+		tracerProvider: otel.GetTracerProvider(),
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	tracer := c.tracerProvider.Tracer(instrumentationName)
+
+	return func(name string, next health.CheckerWithContext) health.CheckerWithContext {
+		return health.CheckFuncWithContext(func(ctx context.Context) health.Result {
+			ctx, span := tracer.Start(ctx, "health.check "+name,
+				trace.WithAttributes(attribute.String("health.check.name", name)))
+			defer span.End()
+
+			res := next.Check(ctx)
+
+			span.SetAttributes(attribute.Bool("health.check.healthy", res.Error == nil))
+			if res.Message != "" {
+				span.SetAttributes(attribute.String("health.check.message", res.Message))
+			}
+			if res.Error != nil {
+				span.RecordError(res.Error)
+				span.SetStatus(codes.Error, res.Error.Error())
+			}
+			return res
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package otelhealth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/docker/distribution/health"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestHook(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	registry := health.NewRegistry()
+	registry.RegisterHook(Hook(WithTracerProvider(tp)))
+	registry.Register("database", health.CheckFunc(func() health.Result {
+		return health.Result{Error: errors.New("connection refused")}
+	}))
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "request")
+	registry.CheckStatusContext(ctx)
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "health.check database" {
+		t.Errorf("unexpected span name: %q", span.Name())
+	}
+	if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("check span was expected to be a child of the request span")
+	}
+	if span.Status().Code != codes.Error || span.Status().Description != "connection refused" {
+		t.Errorf("unexpected span status: %+v", span.Status())
+	}
+}
@@ -0,0 +1,154 @@
+package health
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OverridePath is the conventional path of OverrideHandler, followed by the
+// name of the check. It is not mounted by RegisterRoutes, as it has to be
+// protected.
+const OverridePath = "/debug/health/override/"
+
+// override pins the result of a check until it expires.
+type override struct {
+	healthy bool
+	reason  string
+	until   time.Time
+}
+
+// result returns the result reported instead of running the check.
+func (o *override) result() Result {
+	res := Result{
+		Details: map[string]any{"overridden_until": o.until.UTC()},
+	}
+	if o.healthy {
+		res.Message = "overridden healthy: " + o.reason
+	} else {
+		res.Message = "overridden unhealthy: " + o.reason
+		res.Error = errors.New(res.Message)
+	}
+	return res
+}
+
+// overridden returns the override of the check in effect at now, if any.
+func (rc *registeredCheck) overridden(now time.Time) *override {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.override == nil || !now.Before(rc.override.until) {
+		return nil
+	}
+	return rc.override
+}
+
+// setOverride replaces the override of the check, dropping the cached
+// result so it applies at once.
+func (rc *registeredCheck) setOverride(o *override) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.override = o
+	rc.invalidate()
+}
+
+// Override pins the named check healthy or unhealthy for ttl, instead of
+// running it, e.g. to silence a dependency known to be failing during an
+// incident. The reason is reported in the check's message and logged.
+func (registry *Registry) Override(name string, healthy bool, ttl time.Duration, reason string) error {
+	rc, err := registry.registered(name)
+	if err != nil {
+		return err
+	}
+	rc.setOverride(&override{
+		healthy: healthy,
+		reason:  reason,
+		until:   registry.clock.Now().Add(ttl),
+	})
+	registry.prefetch(name, rc)
+	registry.log().Printf("health: check %s overridden as healthy=%t for %v: %s", name, healthy, ttl, reason)
+	return nil
+}
+
+// ClearOverride runs the named check again, ending its override.
+func (registry *Registry) ClearOverride(name string) error {
+	rc, err := registry.registered(name)
+	if err != nil {
+		return err
+	}
+	rc.setOverride(nil)
+	registry.prefetch(name, rc)
+	registry.log().Printf("health: override of check %s cleared", name)
+	return nil
+}
+
+// Override pins the named check of the default registry healthy or
+// unhealthy for ttl.
+func Override(name string, healthy bool, ttl time.Duration, reason string) error {
+	return Default().Override(name, healthy, ttl, reason)
+}
+
+// ClearOverride ends the override of the named check of the default
+// registry.
+func ClearOverride(name string) error {
+	return Default().ClearOverride(name)
+}
+
+// OverrideHandler returns an http.Handler overriding the check named by the
+// last element of the path, or the name path value when mounted with a
+// pattern like OverridePath+"{name}". POST overrides the check with the
+// healthy, ttl and reason form values, like
+//
+//	healthy=false&ttl=30m&reason=known+outage
+//
+// and DELETE clears the override. Every change is logged with the remote
+// address and basic auth user of the request. Only the authorization options
+// among opts apply, and one should be given.
+func (registry *Registry) OverrideHandler(opts ...HandlerOption) http.Handler {
+	var c handlerConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !c.authorize(w, r) {
+			return
+		}
+		name := r.PathValue("name")
+		if name == "" {
+			name = r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		}
+
+		var err error
+		switch r.Method {
+		case "POST":
+			healthy, perr := strconv.ParseBool(r.FormValue("healthy"))
+			ttl, terr := time.ParseDuration(r.FormValue("ttl"))
+			if perr != nil || terr != nil || ttl <= 0 {
+				http.Error(w, "healthy and a positive ttl are required", http.StatusBadRequest)
+				return
+			}
+			err = registry.Override(name, healthy, ttl, r.FormValue("reason"))
+		case "DELETE":
+			err = registry.ClearOverride(name)
+		default:
+			w.Header().Set("Allow", "POST, DELETE")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		user, _, _ := r.BasicAuth()
+		registry.log().Printf("health: %s of check %s override by %q from %s", r.Method, name, user, r.RemoteAddr)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// OverrideHandler returns an http.Handler overriding the checks of the
+// default registry.
+func OverrideHandler(opts ...HandlerOption) http.Handler {
+	return Default().OverrideHandler(opts...)
+}
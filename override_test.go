@@ -0,0 +1,99 @@
+package health
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestOverride ensures overridden checks report the pinned result until the
+// override expires or is cleared.
+func TestOverride(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)}
+	registry := NewRegistry(WithClock(clock), WithLogger(log.New(&bytes.Buffer{}, "", 0)))
+	calls := 0
+	registry.Register("db", CheckFunc(func() Result {
+		calls++
+		return Result{Error: errors.New("down")}
+	}))
+
+	if err := registry.Override("missing", true, time.Minute, ""); !errors.Is(err, ErrNotRegistered) {
+		t.Errorf("expected ErrNotRegistered, got %v", err)
+	}
+
+	if err := registry.Override("db", true, time.Minute, "known outage"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	check := registry.CheckStatus()["db"]
+	if !check.Healthy || check.Message != "overridden healthy: known outage" || calls != 0 {
+		t.Errorf("expected the overridden result without running the check, got %+v after %d calls", check, calls)
+	}
+
+	clock.Add(time.Minute)
+	if check := registry.CheckStatus()["db"]; check.Healthy || calls != 1 {
+		t.Errorf("expected the check to run once the override expired, got %+v", check)
+	}
+
+	registry.Override("db", true, time.Hour, "known outage")
+	registry.ClearOverride("db")
+	if check := registry.CheckStatus()["db"]; check.Healthy {
+		t.Errorf("expected the check to run once the override was cleared, got %+v", check)
+	}
+}
+
+// TestOverrideHandler ensures the handler overrides checks for authorized
+// requests and logs who did.
+func TestOverrideHandler(t *testing.T) {
+	var logs bytes.Buffer
+	registry := NewRegistry(WithLogger(log.New(&logs, "", 0)))
+	registry.Register("db", CheckFunc(func() Result { return Result{} }))
+	mux := http.NewServeMux()
+	mux.Handle(OverridePath+"{name}", registry.OverrideHandler(WithBasicAuth("oncall", "secret")))
+
+	do := func(method, name string, form url.Values, authorized bool) int {
+		req, err := http.NewRequest(method, "https://fakeurl.com"+OverridePath+name, strings.NewReader(form.Encode()))
+		if err != nil {
+			t.Fatalf("Failed to create request.")
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if authorized {
+			req.SetBasicAuth("oncall", "secret")
+		}
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	form := url.Values{"healthy": {"false"}, "ttl": {"10m"}, "reason": {"drill"}}
+	if code := do("POST", "db", form, false); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without credentials, got %d", code)
+	}
+	if code := do("POST", "missing", form, true); code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown check, got %d", code)
+	}
+	if code := do("POST", "db", url.Values{"healthy": {"false"}}, true); code != http.StatusBadRequest {
+		t.Errorf("expected 400 without ttl, got %d", code)
+	}
+	if code := do("POST", "db", form, true); code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", code)
+	}
+	if check := registry.CheckStatus()["db"]; check.Healthy || check.Message != "overridden unhealthy: drill" {
+		t.Errorf("expected the check to be overridden unhealthy, got %+v", check)
+	}
+	if !strings.Contains(logs.String(), `override by "oncall"`) {
+		t.Errorf("expected the override to be logged with the user, got %q", logs.String())
+	}
+
+	if code := do("DELETE", "db", nil, true); code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", code)
+	}
+	if check := registry.CheckStatus()["db"]; !check.Healthy {
+		t.Errorf("expected the override to be cleared, got %+v", check)
+	}
+}
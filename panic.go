@@ -0,0 +1,29 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// safeCheck runs check, turning a panic into a failing Result instead of
+// crashing the process. The panic value and the stack are reported in the
+// details under "panic" and "stack".
+func safeCheck(ctx context.Context, check CheckerWithContext) (res Result) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg := fmt.Sprintf("check panicked: %v", r)
+			res = Result{
+				Error:   errors.New(msg),
+				Message: msg,
+				Code:    CodePanic,
+				Details: map[string]any{
+					"panic": fmt.Sprint(r),
+					"stack": string(debug.Stack()),
+				},
+			}
+		}
+	}()
+	return check.Check(ctx)
+}
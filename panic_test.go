@@ -0,0 +1,35 @@
+package health
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestPanickingCheck ensures a panicking check is reported as failing, with
+// the panic in its details.
+func TestPanickingCheck(t *testing.T) {
+	panicking := CheckFunc(func() Result {
+		var m map[string]int
+		m["boom"]++
+		return Result{}
+	})
+
+	for _, opts := range [][]RegistryOption{nil, {WithDefaultTimeout(time.Second)}} {
+		registry := NewRegistry(opts...)
+		registry.Register("check", panicking)
+		check := registry.CheckStatus()["check"]
+		if check.Healthy || !strings.HasPrefix(check.Message, "check panicked: assignment to entry in nil map") {
+			t.Errorf("unexpected status: %+v", check)
+		}
+		if stack, _ := check.Details["stack"].(string); !strings.Contains(stack, "TestPanickingCheck") {
+			t.Errorf("expected the stack in the details, got %+v", check.Details)
+		}
+	}
+
+	periodic := PeriodicChecker(panicking, time.Hour, RunImmediately())
+	defer periodic.Stop()
+	if res := periodic.Check(); res.Error == nil {
+		t.Errorf("expected the periodic check to fail")
+	}
+}
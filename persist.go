@@ -0,0 +1,254 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// StateStore persists the state of a registry across restarts, see
+// WithStateStore. Implementations can keep it in a file, see FileStore, or
+// under a key of a KV store.
+type StateStore interface {
+	// Load returns the last saved state. It returns an error matching
+	// fs.ErrNotExist if nothing was saved yet.
+	Load(ctx context.Context) ([]byte, error)
+	// Save replaces the saved state with state.
+	Save(ctx context.Context, state []byte) error
+}
+
+// fileStore is a StateStore keeping the state in a file.
+type fileStore struct {
+	path string
+}
+
+// FileStore returns a StateStore keeping the state in the file at path. The
+// file is replaced atomically when the state is saved.
+func FileStore(path string) StateStore {
+	return fileStore{path: path}
+}
+
+// Load implements the StateStore interface
+func (s fileStore) Load(context.Context) ([]byte, error) {
+	return os.ReadFile(s.path)
+}
+
+// Save implements the StateStore interface
+func (s fileStore) Save(_ context.Context, state []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(state); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), s.path)
+}
+
+// WithStateStore persists the last results of the checks, their overrides,
+// whether they are disabled, and the maintenance mode of the registry in
+// store. The registry restores them when it is created, so a restart does
+// not report every check as healthy until it ran: checks running in the
+// background, like periodic checks and status updaters, report their
+// restored result until they report one of their own. The state is saved
+// when the registry is closed, or with SaveState.
+func WithStateStore(store StateStore) RegistryOption {
+	return func(registry *Registry) {
+		registry.stateStore = store
+	}
+}
+
+// savedState is the persisted state of a registry.
+type savedState struct {
+	Checks      map[string]savedCheck `json:"checks,omitempty"`
+	Maintenance *savedMaintenance     `json:"maintenance,omitempty"`
+}
+
+// savedCheck is the persisted state of a check.
+type savedCheck struct {
+	Healthy     bool           `json:"healthy"`
+	Degraded    bool           `json:"degraded,omitempty"`
+	Message     string         `json:"message,omitempty"`
+	Code        Code           `json:"code,omitempty"`
+	CheckedAt   time.Time      `json:"checked_at"`
+	LastSuccess *time.Time     `json:"last_success,omitempty"`
+	Failures    int            `json:"failures,omitempty"`
+	Disabled    bool           `json:"disabled,omitempty"`
+	Override    *savedOverride `json:"override,omitempty"`
+}
+
+// savedOverride is the persisted override of a check.
+type savedOverride struct {
+	Healthy bool      `json:"healthy"`
+	Reason  string    `json:"reason"`
+	Until   time.Time `json:"until"`
+}
+
+// savedMaintenance is the persisted maintenance mode of a registry.
+type savedMaintenance struct {
+	Reason string    `json:"reason"`
+	Since  time.Time `json:"since"`
+}
+
+// SaveState saves the state of the registry in the store set with
+// WithStateStore. It does nothing without one.
+func (registry *Registry) SaveState(ctx context.Context) error {
+	if registry.stateStore == nil {
+		return nil
+	}
+	p, err := json.Marshal(registry.savedState())
+	if err != nil {
+		return err
+	}
+	return registry.stateStore.Save(ctx, p)
+}
+
+// SaveState saves the state of the default registry.
+func SaveState(ctx context.Context) error {
+	return Default().SaveState(ctx)
+}
+
+// savedState returns the state of the registry to persist. The state
+// restored for checks that are not registered is kept.
+func (registry *Registry) savedState() savedState {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	now := registry.clock.Now()
+	state := savedState{Checks: make(map[string]savedCheck, len(registry.registeredChecks))}
+	for name, saved := range registry.restored {
+		state.Checks[name] = saved
+	}
+	for name, rc := range registry.registeredChecks {
+		if saved, ok := rc.saved(now); ok {
+			state.Checks[name] = saved
+		}
+	}
+	if m := registry.maintenance; m != nil {
+		state.Maintenance = &savedMaintenance{Reason: m.reason, Since: m.since}
+	}
+	return state
+}
+
+// saved returns the state of the check to persist, and whether there is
+// any.
+func (rc *registeredCheck) saved(now time.Time) (savedCheck, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	last := rc.last
+	if last.CheckedAt.IsZero() && rc.restored != nil {
+		last = *rc.restored
+	}
+	saved := savedCheck{
+		Healthy:   last.Error == nil,
+		Degraded:  last.Error == nil && last.Degraded,
+		Message:   last.Message,
+		Code:      last.Code,
+		CheckedAt: last.CheckedAt,
+		Disabled:  rc.disabled,
+	}
+	if !saved.Healthy {
+		saved.Failures = rc.failures
+		if saved.Message == "" {
+			saved.Message = last.Error.Error()
+		}
+	}
+	if !rc.lastSuccess.IsZero() {
+		lastSuccess := rc.lastSuccess
+		saved.LastSuccess = &lastSuccess
+	}
+	if o := rc.override; o != nil && now.Before(o.until) {
+		saved.Override = &savedOverride{Healthy: o.healthy, Reason: o.reason, Until: o.until}
+	}
+	return saved, !saved.CheckedAt.IsZero() || saved.Disabled || saved.Override != nil
+}
+
+// loadState restores the state saved in the store of the registry, if any.
+// The state of the checks is restored as they are registered.
+func (registry *Registry) loadState() {
+	if registry.stateStore == nil {
+		return
+	}
+	p, err := registry.stateStore.Load(context.Background())
+	if errors.Is(err, fs.ErrNotExist) {
+		return
+	}
+	var state savedState
+	if err == nil {
+		err = json.Unmarshal(p, &state)
+	}
+	if err != nil {
+		registry.log().Printf("health: error restoring saved state: %v", err)
+		return
+	}
+
+	registry.restored = state.Checks
+	if m := state.Maintenance; m != nil {
+		registry.maintenance = &maintenance{reason: m.Reason, since: m.Since}
+	}
+}
+
+// restore applies the state restored for the check registered as name.
+// registry.mu must be held, and rc not be registered yet.
+func (registry *Registry) restore(name string, rc *registeredCheck) {
+	saved, ok := registry.restored[name]
+	if !ok {
+		return
+	}
+	delete(registry.restored, name)
+
+	rc.disabled = saved.Disabled
+	if o := saved.Override; o != nil && registry.clock.Now().Before(o.Until) {
+		rc.override = &override{healthy: o.Healthy, reason: o.Reason, until: o.Until}
+	}
+	if saved.LastSuccess != nil {
+		rc.lastSuccess = *saved.LastSuccess
+	}
+	if saved.CheckedAt.IsZero() {
+		return
+	}
+	res := Result{
+		Message:   saved.Message,
+		Degraded:  saved.Degraded,
+		Code:      saved.Code,
+		CheckedAt: saved.CheckedAt,
+		Details:   map[string]any{"restored": true},
+	}
+	if !saved.Healthy {
+		if res.Message == "" {
+			res.Message = "restored unhealthy state"
+		}
+		res.Error = errors.New(res.Message)
+		rc.unhealthy, rc.failures = true, saved.Failures
+	}
+	rc.restored = &res
+}
+
+// warm returns the restored result of the check instead of res while the
+// check runs in the background and did not report a result of its own yet.
+func (rc *registeredCheck) warm(res Result) Result {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.restored == nil {
+		return res
+	}
+	check := any(rc.check)
+	if a, ok := check.(checkerAdapter); ok {
+		check = a.Checker
+	}
+	if _, background := check.(Updater); background && res.CheckedAt.IsZero() {
+		return *rc.restored
+	}
+	rc.restored = nil
+	return res
+}
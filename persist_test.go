@@ -0,0 +1,90 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestStateStore ensures the state of a registry is restored after a
+// restart.
+func TestStateStore(t *testing.T) {
+	store := FileStore(filepath.Join(t.TempDir(), "state.json"))
+
+	registry := NewRegistry(WithStateStore(store))
+	database := NewStatusUpdater()
+	registry.Register("database", database)
+	registry.Register("cache", NewStatusUpdater())
+	registry.Register("queue", NewStatusUpdater())
+	replica := NewStatusUpdater()
+	registry.Register("replica", replica)
+	registry.Register("sync", CheckFunc(func() Result {
+		return Result{Error: errors.New("down")}
+	}))
+	database.Update(Result{Error: errors.New("connection refused"), Code: CodeConnRefused})
+	replica.Update(Result{Degraded: true, Message: "lagging"})
+	if err := registry.Override("cache", false, time.Hour, "incident"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := registry.Disable("queue"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	registry.SetMaintenance("migration")
+	registry.CheckStatus()
+	if err := registry.Close(); err != nil {
+		t.Fatalf("error closing the registry: %v", err)
+	}
+
+	restarted := NewRegistry(WithStateStore(store))
+	database = NewStatusUpdater()
+	restarted.Register("database", database)
+	restarted.Register("cache", NewStatusUpdater())
+	restarted.Register("queue", NewStatusUpdater())
+	restarted.Register("replica", NewStatusUpdater())
+	restarted.Register("sync", CheckFunc(func() Result {
+		return Result{}
+	}))
+
+	status := restarted.CheckStatus()
+	if check := status["database"]; check.Healthy || check.Message != "connection refused" || check.Code != CodeConnRefused || check.Details["restored"] != true {
+		t.Errorf("expected the failure of database to be restored, got %+v", check)
+	}
+	if check := status["cache"]; check.Healthy || check.Message != "overridden unhealthy: incident" {
+		t.Errorf("expected the override of cache to be restored, got %+v", check)
+	}
+	if check := status["replica"]; !check.Healthy || check.State != StateDegraded || check.Code != CodeDegraded || check.Status() != StatusWarn {
+		t.Errorf("expected replica to be restored degraded, got %+v", check)
+	}
+	if check := status["queue"]; !check.Disabled {
+		t.Errorf("expected queue to stay disabled, got %+v", check)
+	}
+	if check := status["sync"]; !check.Healthy {
+		t.Errorf("expected a check run on request to report its own result, got %+v", check)
+	}
+	if reason, ok := restarted.Maintenance(); !ok || reason != "migration" {
+		t.Errorf("expected the maintenance mode to be restored, got %q", reason)
+	}
+
+	database.Update(Result{})
+	if check := restarted.CheckStatus()["database"]; !check.Healthy {
+		t.Errorf("expected database to report its own result once updated, got %+v", check)
+	}
+}
+
+// TestStateStoreMissing ensures a registry starts without saved state.
+func TestStateStoreMissing(t *testing.T) {
+	store := FileStore(filepath.Join(t.TempDir(), "state.json"))
+	registry := NewRegistry(WithStateStore(store))
+	registry.Register("check", NewStatusUpdater())
+	if status := registry.CheckStatus(); !status["check"].Healthy {
+		t.Errorf("unexpected status: %+v", status)
+	}
+	if err := registry.SaveState(context.Background()); err != nil {
+		t.Errorf("error saving state: %v", err)
+	}
+	if err := NewRegistry().SaveState(context.Background()); err != nil {
+		t.Errorf("expected saving without a store to do nothing, got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package health
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ELBUserAgent is the User-Agent prefix of the health checks of AWS Elastic
+// Load Balancers.
+const ELBUserAgent = "ELB-HealthChecker/"
+
+// probeAgent is the handler serving the requests of a User-Agent.
+type probeAgent struct {
+	prefix  string
+	opts    []HandlerOption
+	handler http.Handler
+}
+
+// WithSuccessStatus sets the status code of responses whose checks all
+// pass. The default is 200 OK.
+func WithSuccessStatus(code int) HandlerOption {
+	return func(c *handlerConfig) {
+		c.successStatus = code
+	}
+}
+
+// WithProbeAgent serves the requests whose User-Agent starts with prefix
+// with opts applied on top of the other options of the handler, to tell
+// load balancer probes from humans, e.g. responding minimally to the former.
+// The first matching prefix applies.
+func WithProbeAgent(prefix string, opts ...HandlerOption) HandlerOption {
+	return func(c *handlerConfig) {
+		c.agents = append(c.agents, &probeAgent{prefix: prefix, opts: opts})
+	}
+}
+
+// WithELB tunes the handler for the health checks of AWS Application and
+// Network Load Balancers: their requests get a minimal response, with
+// opts applied, e.g. WithDegradedStatus to match the success codes of the
+// target group. Other requests are served as usual.
+func WithELB(opts ...HandlerOption) HandlerOption {
+	return WithProbeAgent(ELBUserAgent, append([]HandlerOption{WithMinimalResponse()}, opts...)...)
+}
+
+// agent returns the handler for the User-Agent of r, or nil if no prefix
+// matches.
+func (c *handlerConfig) agent(r *http.Request) http.Handler {
+	ua := r.UserAgent()
+	for _, agent := range c.agents {
+		if strings.HasPrefix(ua, agent.prefix) {
+			return agent.handler
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package health
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestProbeAgents ensures load balancer probes get their own responses.
+func TestProbeAgents(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("cache", CheckFunc(func() Result { return Result{Error: errors.New("down")} }), WithSeverity(Warning))
+	handler := registry.Handler(WithSuccessStatus(http.StatusNoContent), WithELB(WithDegradedStatus(http.StatusAccepted)))
+
+	get := func(userAgent string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+		if err != nil {
+			t.Fatalf("Failed to create request.")
+		}
+		req.Header.Set("User-Agent", userAgent)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	recorder := get("ELB-HealthChecker/2.0")
+	if recorder.Code != http.StatusAccepted || recorder.Body.String() != StatusWarn+"\n" {
+		t.Errorf("expected a minimal response with the probe's status code, got %d %q", recorder.Code, recorder.Body.String())
+	}
+	recorder = get("Mozilla/5.0")
+	if recorder.Code != http.StatusOK || recorder.Header().Get("Content-Type") != "application/json; charset=utf-8" {
+		t.Errorf("expected the usual response for humans, got %d %q", recorder.Code, recorder.Header().Get("Content-Type"))
+	}
+
+	registry = NewRegistry()
+	registry.Register("ok", CheckFunc(func() Result { return Result{} }))
+	handler = registry.Handler(WithSuccessStatus(http.StatusNoContent), WithELB())
+	if recorder := get("ELB-HealthChecker/2.0"); recorder.Code != http.StatusNoContent {
+		t.Errorf("expected the success status code, got %d", recorder.Code)
+	}
+}
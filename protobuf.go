@@ -0,0 +1,147 @@
+package health
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// ProtobufContentType is the media type of protobuf responses, see
+// WithProtobuf.
+const ProtobufContentType = "application/x-protobuf"
+
+// WithProtobuf makes the handler respond with the StatusDocument message
+// defined in health.proto to requests accepting ProtobufContentType, for
+// monitoring agents polling at a high frequency.
+func WithProtobuf() HandlerOption {
+	return func(c *handlerConfig) {
+		c.protobuf = true
+	}
+}
+
+// fieldNumber is the number of a field of a protobuf message.
+type fieldNumber uint64
+
+// wireType is the encoding of a field of a protobuf message.
+type wireType uint64
+
+// Wire types used by the messages in health.proto.
+const (
+	varintType  wireType = 0
+	fixed64Type wireType = 1
+	bytesType   wireType = 2
+)
+
+// Field numbers of the messages in health.proto.
+const (
+	documentStatus    fieldNumber = 1
+	documentChecks    fieldNumber = 2
+	documentTimestamp fieldNumber = 3
+	documentScore     fieldNumber = 4
+
+	checkHealthy      fieldNumber = 1
+	checkMessage      fieldNumber = 2
+	checkSeverity     fieldNumber = 3
+	checkState        fieldNumber = 4
+	checkDurationMs   fieldNumber = 5
+	checkLastChecked  fieldNumber = 6
+	checkLastSuccess  fieldNumber = 7
+	checkInitializing fieldNumber = 8
+	checkDisabled     fieldNumber = 9
+	checkCode         fieldNumber = 10
+
+	// mapKey and mapValue are the fields of a map entry.
+	mapKey   fieldNumber = 1
+	mapValue fieldNumber = 2
+
+	timestampSeconds fieldNumber = 1
+	timestampNanos   fieldNumber = 2
+)
+
+// marshalProtobuf encodes the status of the checks as a StatusDocument.
+// Fields with default values are omitted, like proto3 does. Checks are
+// encoded in the order of their names so responses are deterministic.
+func marshalProtobuf(checks Status, overall string, now time.Time) []byte {
+	var b []byte
+	b = appendString(b, documentStatus, overall)
+	for _, name := range sortedNames(checks) {
+		var entry []byte
+		entry = appendString(entry, mapKey, name)
+		entry = appendMessage(entry, mapValue, marshalCheck(checks[name]))
+		b = appendMessage(b, documentChecks, entry)
+	}
+	b = appendMessage(b, documentTimestamp, marshalTimestamp(now))
+	if s := score(checks); s != 0 {
+		b = appendTag(b, documentScore, fixed64Type)
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(s))
+	}
+	return b
+}
+
+// marshalCheck encodes check as a Check message.
+func marshalCheck(check HealthCheck) []byte {
+	var b []byte
+	b = appendBool(b, checkHealthy, check.Healthy)
+	b = appendString(b, checkMessage, check.Message)
+	b = appendString(b, checkSeverity, string(check.Severity))
+	b = appendString(b, checkState, string(check.State))
+	if check.DurationMs != 0 {
+		b = appendTag(b, checkDurationMs, fixed64Type)
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(check.DurationMs))
+	}
+	if !check.LastChecked.IsZero() {
+		b = appendMessage(b, checkLastChecked, marshalTimestamp(check.LastChecked))
+	}
+	if check.LastSuccess != nil {
+		b = appendMessage(b, checkLastSuccess, marshalTimestamp(*check.LastSuccess))
+	}
+	b = appendBool(b, checkInitializing, check.Initializing)
+	b = appendBool(b, checkDisabled, check.Disabled)
+	b = appendString(b, checkCode, string(check.Code))
+	return b
+}
+
+// marshalTimestamp encodes t as a google.protobuf.Timestamp message.
+func marshalTimestamp(t time.Time) []byte {
+	var b []byte
+	if s := t.Unix(); s != 0 {
+		b = appendTag(b, timestampSeconds, varintType)
+		b = binary.AppendUvarint(b, uint64(s))
+	}
+	if n := t.Nanosecond(); n != 0 {
+		b = appendTag(b, timestampNanos, varintType)
+		b = binary.AppendUvarint(b, uint64(n))
+	}
+	return b
+}
+
+// appendString appends the string field num, unless s is empty.
+func appendString(b []byte, num fieldNumber, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = appendTag(b, num, bytesType)
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// appendBool appends the bool field num, unless v is false.
+func appendBool(b []byte, num fieldNumber, v bool) []byte {
+	if !v {
+		return b
+	}
+	b = appendTag(b, num, varintType)
+	return append(b, 1)
+}
+
+// appendMessage appends the encoded message m as the field num.
+func appendMessage(b []byte, num fieldNumber, m []byte) []byte {
+	b = appendTag(b, num, bytesType)
+	b = binary.AppendUvarint(b, uint64(len(m)))
+	return append(b, m...)
+}
+
+// appendTag appends the key of the field num encoded as typ.
+func appendTag(b []byte, num fieldNumber, typ wireType) []byte {
+	return binary.AppendUvarint(b, uint64(num)<<3|uint64(typ))
+}
@@ -0,0 +1,126 @@
+package health
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// decodeFields decodes the fields of a protobuf message, keyed by number.
+// Varints and fixed64 values are returned as uint64, bytes as []byte.
+func decodeFields(t *testing.T, b []byte) map[fieldNumber][]any {
+	t.Helper()
+	fields := make(map[fieldNumber][]any)
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			t.Fatalf("invalid tag")
+		}
+		b = b[n:]
+		num, typ := fieldNumber(tag>>3), wireType(tag&7)
+		var v any
+		switch typ {
+		case varintType:
+			v, n = binary.Uvarint(b)
+		case fixed64Type:
+			if len(b) < 8 {
+				n = -1
+				break
+			}
+			v, n = binary.LittleEndian.Uint64(b), 8
+		case bytesType:
+			var size uint64
+			size, n = binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < size {
+				n = -1
+				break
+			}
+			v, n = b[n:n+int(size)], n+int(size)
+		default:
+			t.Fatalf("unexpected wire type %v of field %d", typ, num)
+		}
+		if n <= 0 {
+			t.Fatalf("invalid field %d", num)
+		}
+		b = b[n:]
+		fields[num] = append(fields[num], v)
+	}
+	return fields
+}
+
+// TestProtobuf ensures the status is served as a StatusDocument to clients
+// accepting protobuf.
+func TestProtobuf(t *testing.T) {
+	registry := NewRegistry()
+	lastChecked := time.Date(2024, 5, 1, 12, 0, 0, 500, time.UTC)
+	registry.Register("database", CheckFunc(func() Result {
+		return Result{Message: "connection refused", Error: errors.New("connection refused"), CheckedAt: lastChecked, Duration: 1500 * time.Microsecond}
+	}))
+
+	req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+	if err != nil {
+		t.Errorf("Failed to create request.")
+	}
+	req.Header.Set("Accept", ProtobufContentType)
+
+	recorder := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(recorder, req)
+	if ct := recorder.Header().Get("Content-Type"); ct == ProtobufContentType {
+		t.Errorf("expected protobuf to be served only with WithProtobuf")
+	}
+
+	recorder = httptest.NewRecorder()
+	registry.Handler(WithProtobuf()).ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("unexpected status code: %d", recorder.Code)
+	}
+	if ct := recorder.Header().Get("Content-Type"); ct != ProtobufContentType {
+		t.Fatalf("unexpected content type: %q", ct)
+	}
+
+	doc := decodeFields(t, recorder.Body.Bytes())
+	if status := string(doc[documentStatus][0].([]byte)); status != StatusFail {
+		t.Errorf("unexpected status: %q", status)
+	}
+	if _, ok := doc[documentScore]; ok {
+		t.Errorf("expected a zero score to be omitted")
+	}
+	if len(doc[documentTimestamp]) != 1 {
+		t.Errorf("expected a timestamp")
+	}
+	if len(doc[documentChecks]) != 1 {
+		t.Fatalf("expected 1 check, got %d", len(doc[documentChecks]))
+	}
+
+	entry := decodeFields(t, doc[documentChecks][0].([]byte))
+	if name := string(entry[mapKey][0].([]byte)); name != "database" {
+		t.Errorf("unexpected check name: %q", name)
+	}
+	check := decodeFields(t, entry[mapValue][0].([]byte))
+	if _, ok := check[checkHealthy]; ok {
+		t.Errorf("expected healthy to be omitted for failing checks")
+	}
+	for num, expected := range map[fieldNumber]string{
+		checkMessage:  "connection refused",
+		checkSeverity: string(Critical),
+		checkState:    string(StateUnhealthy),
+	} {
+		if got := string(check[num][0].([]byte)); got != expected {
+			t.Errorf("unexpected field %d: %q != %q", num, got, expected)
+		}
+	}
+	if d := math.Float64frombits(check[checkDurationMs][0].(uint64)); d != 1.5 {
+		t.Errorf("unexpected duration: %v", d)
+	}
+	ts := decodeFields(t, check[checkLastChecked][0].([]byte))
+	if ts[timestampSeconds][0].(uint64) != uint64(lastChecked.Unix()) || ts[timestampNanos][0].(uint64) != 500 {
+		t.Errorf("unexpected last checked: %v", ts)
+	}
+	if _, ok := check[checkLastSuccess]; ok {
+		t.Errorf("expected last success to be omitted")
+	}
+}
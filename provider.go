@@ -0,0 +1,39 @@
+package health
+
+import (
+	"sort"
+
+	"github.com/docker/distribution/health/healthapi"
+)
+
+// CheckProvider exposes the health checks of a library, see
+// healthapi.CheckProvider.
+type CheckProvider = healthapi.CheckProvider
+
+// AddProvider registers the checks of provider, all with opts. Like
+// Register, it panics if a name is already registered, in which case none of
+// the checks are.
+func (registry *Registry) AddProvider(provider CheckProvider, opts ...CheckOption) {
+	checks := provider.HealthChecks()
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	for _, name := range names {
+		if _, ok := registry.registeredChecks[name]; ok {
+			panic("Check already exists: " + name)
+		}
+	}
+	for _, name := range names {
+		registry.install(name, newRegisteredCheck(WithContext(checks[name]), opts))
+	}
+}
+
+// AddProvider registers the checks of provider in the default registry.
+func AddProvider(provider CheckProvider, opts ...CheckOption) {
+	Default().AddProvider(provider, opts...)
+}
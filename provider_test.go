@@ -0,0 +1,35 @@
+package health
+
+import (
+	"errors"
+	"testing"
+)
+
+// clientChecks is a CheckProvider as a client library would implement it.
+type clientChecks struct{}
+
+func (clientChecks) HealthChecks() map[string]Checker {
+	return map[string]Checker{
+		"client-connection": CheckFunc(func() Result { return Result{} }),
+		"client-quota":      CheckFunc(func() Result { return Result{Error: errors.New("exhausted")} }),
+	}
+}
+
+// TestAddProvider ensures the checks of providers are registered with the
+// given options, and none are on conflicts.
+func TestAddProvider(t *testing.T) {
+	registry := NewRegistry()
+	registry.AddProvider(clientChecks{}, WithSeverity(Warning))
+
+	status := registry.CheckStatus()
+	if len(status) != 2 || !status["client-connection"].Healthy || status["client-quota"].Severity != Warning {
+		t.Errorf("expected the checks of the provider with the options, got %v", status)
+	}
+
+	registry = NewRegistry()
+	registry.Register("client-quota", CheckFunc(func() Result { return Result{} }))
+	assertPanics(t, func() { registry.AddProvider(clientChecks{}) }, "adding a provider with a registered name")
+	if _, ok := registry.CheckStatus()["client-connection"]; ok {
+		t.Errorf("expected no check of the provider to be registered on conflict")
+	}
+}
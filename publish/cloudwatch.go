@@ -0,0 +1,56 @@
+package publish
+
+import (
+	"context"
+	"time"
+
+	"github.com/docker/distribution/health"
+)
+
+// Datum is a CloudWatch metric data point.
+type Datum struct {
+	Name       string
+	Dimensions map[string]string
+	Value      float64
+	// Unit is a CloudWatch unit, like "None" or "Milliseconds".
+	Unit      string
+	Timestamp time.Time
+}
+
+// CloudWatchClient is the subset of a CloudWatch client the publisher
+// needs, so the package does not force an AWS SDK version on its users.
+// Implementing it on top of the PutMetricData operation of the SDK takes a
+// few lines.
+type CloudWatchClient interface {
+	PutMetricData(ctx context.Context, namespace string, data []Datum) error
+}
+
+// CloudWatch returns a StatusPublisher putting metrics in namespace:
+//
+//   - Healthy, 1 if the status of the service is not health.StatusFail
+//   - Score, the health score of the service
+//   - CheckHealthy and CheckDuration, with a Check dimension, per check
+func CloudWatch(client CloudWatchClient, namespace string) StatusPublisher {
+	return StatusPublisherFunc(func(ctx context.Context, report Report) error {
+		data := []Datum{
+			{Name: "Healthy", Value: boolValue(report.Status != health.StatusFail), Unit: "None", Timestamp: report.Timestamp},
+			{Name: "Score", Value: report.Score, Unit: "Percent", Timestamp: report.Timestamp},
+		}
+		for name, check := range report.Checks {
+			dimensions := map[string]string{"Check": name}
+			data = append(data,
+				Datum{Name: "CheckHealthy", Dimensions: dimensions, Value: boolValue(check.Healthy), Unit: "None", Timestamp: report.Timestamp},
+				Datum{Name: "CheckDuration", Dimensions: dimensions, Value: check.DurationMs, Unit: "Milliseconds", Timestamp: report.Timestamp},
+			)
+		}
+		return client.PutMetricData(ctx, namespace, data)
+	})
+}
+
+// boolValue returns 1 if b is set, 0 otherwise.
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
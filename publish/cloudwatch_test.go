@@ -0,0 +1,62 @@
+package publish
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/distribution/health"
+)
+
+// cloudWatchRecorder is a CloudWatchClient recording the data it receives.
+type cloudWatchRecorder struct {
+	namespace string
+	data      []Datum
+}
+
+func (c *cloudWatchRecorder) PutMetricData(ctx context.Context, namespace string, data []Datum) error {
+	c.namespace = namespace
+	c.data = data
+	return nil
+}
+
+func TestCloudWatch(t *testing.T) {
+	client := &cloudWatchRecorder{}
+	now := time.Now().UTC()
+	report := Report{
+		Status: health.StatusWarn,
+		Score:  75,
+		Checks: health.Status{
+			"database": {Healthy: true, DurationMs: 12},
+		},
+		Timestamp: now,
+	}
+	if err := CloudWatch(client, "Billing/Health").Publish(context.Background(), report); err != nil {
+		t.Fatalf("Publish() = %v", err)
+	}
+	if client.namespace != "Billing/Health" {
+		t.Errorf("namespace = %q, want Billing/Health", client.namespace)
+	}
+
+	want := map[string]float64{
+		"Healthy":                1,
+		"Score":                  75,
+		"CheckHealthy/database":  1,
+		"CheckDuration/database": 12,
+	}
+	if len(client.data) != len(want) {
+		t.Fatalf("got %d data points, want %d: %+v", len(client.data), len(want), client.data)
+	}
+	for _, datum := range client.data {
+		key := datum.Name
+		if check := datum.Dimensions["Check"]; check != "" {
+			key += "/" + check
+		}
+		if value, ok := want[key]; !ok || value != datum.Value {
+			t.Errorf("unexpected data point %s = %v", key, datum.Value)
+		}
+		if !datum.Timestamp.Equal(now) {
+			t.Errorf("data point %s has timestamp %v, want %v", key, datum.Timestamp, now)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+// Package publish pushes the status of a health.Registry on an interval, for
+// environments that do not scrape or poll the service.
+//
+//	go publish.Run(ctx, health.DefaultRegistry, time.Minute,
+//		publish.HTTPPush("https://push.example.com/health/billing", nil))
+package publish
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/docker/distribution/health"
+)
+
+// Report is the status of a registry at a point in time.
+type Report struct {
+	// Status is health.StatusPass, health.StatusWarn or health.StatusFail.
+	Status    string        `json:"status"`
+	Score     float64       `json:"score"`
+	Checks    health.Status `json:"checks"`
+	Timestamp time.Time     `json:"timestamp"`
+}
+
+// StatusPublisher pushes reports somewhere.
+type StatusPublisher interface {
+	Publish(ctx context.Context, report Report) error
+}
+
+// StatusPublisherFunc is a convenience type to create functions that
+// implement the StatusPublisher interface
+type StatusPublisherFunc func(ctx context.Context, report Report) error
+
+// Publish implements the StatusPublisher interface
+func (f StatusPublisherFunc) Publish(ctx context.Context, report Report) error {
+	return f(ctx, report)
+}
+
+// Run evaluates the checks of registry and publishes a report to every
+// publisher every interval, until ctx is done. Failures are logged to
+// health.DefaultLogger and retried on the next interval.
+func Run(ctx context.Context, registry *health.Registry, interval time.Duration, publishers ...StatusPublisher) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := Publish(ctx, registry, publishers...); err != nil {
+			health.DefaultLogger().Printf("error publishing health status: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Publish evaluates the checks of registry once and publishes a report to
+// every publisher. It returns the errors of the publishers joined.
+func Publish(ctx context.Context, registry *health.Registry, publishers ...StatusPublisher) error {
+	checks := registry.CheckStatusContext(ctx)
+	report := Report{
+		Status:    checks.Overall(),
+		Score:     checks.Score(),
+		Checks:    checks,
+		Timestamp: time.Now().UTC(),
+	}
+	var errs []error
+	for _, publisher := range publishers {
+		if err := publisher.Publish(ctx, report); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// HTTPPush returns a StatusPublisher POSTing reports as JSON to url. A nil
+// client uses http.DefaultClient.
+func HTTPPush(url string, client *http.Client) StatusPublisher {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return StatusPublisherFunc(func(ctx context.Context, report Report) error {
+		p, err := json.Marshal(report)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(p))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return errors.New("push returned unexpected status: " + strconv.Itoa(resp.StatusCode))
+		}
+		return nil
+	})
+}
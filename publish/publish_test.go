@@ -0,0 +1,126 @@
+package publish
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/distribution/health"
+)
+
+func TestPublish(t *testing.T) {
+	registry := health.NewRegistry()
+	registry.Register("database", health.CheckFunc(func() health.Result { return health.Result{} }))
+	registry.Register("cache", health.CheckFunc(func() health.Result { return health.Result{Error: errors.New("down")} }))
+
+	var got Report
+	ok := StatusPublisherFunc(func(ctx context.Context, report Report) error {
+		got = report
+		return nil
+	})
+	failing := StatusPublisherFunc(func(ctx context.Context, report Report) error {
+		return errors.New("unreachable")
+	})
+
+	err := Publish(context.Background(), registry, failing, ok)
+	if err == nil || !strings.Contains(err.Error(), "unreachable") {
+		t.Errorf("Publish() = %v, want the publisher's error", err)
+	}
+	if got.Status != health.StatusFail {
+		t.Errorf("report status = %q, want %q", got.Status, health.StatusFail)
+	}
+	if got.Score != 50 {
+		t.Errorf("report score = %v, want 50", got.Score)
+	}
+	if len(got.Checks) != 2 || got.Checks["cache"].Healthy || !got.Checks["database"].Healthy {
+		t.Errorf("report checks = %v", got.Checks)
+	}
+	if got.Timestamp.IsZero() {
+		t.Error("report has no timestamp")
+	}
+}
+
+func TestRun(t *testing.T) {
+	registry := health.NewRegistry()
+	registry.Register("database", health.CheckFunc(func() health.Result { return health.Result{} }))
+
+	var (
+		mu      sync.Mutex
+		reports int
+	)
+	publisher := StatusPublisherFunc(func(ctx context.Context, report Report) error {
+		mu.Lock()
+		defer mu.Unlock()
+		reports++
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		Run(ctx, registry, time.Millisecond, publisher)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		n := reports
+		mu.Unlock()
+		if n >= 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("published %d reports, want at least 3", n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after the context was cancelled")
+	}
+}
+
+func TestHTTPPush(t *testing.T) {
+	var got Report
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got %s request with content type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("error decoding report: %v", err)
+		}
+		if got.Status == health.StatusFail {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	publisher := HTTPPush(server.URL, nil)
+	report := Report{
+		Status:    health.StatusPass,
+		Score:     100,
+		Checks:    health.Status{"database": {Healthy: true}},
+		Timestamp: time.Now().UTC(),
+	}
+	if err := publisher.Publish(context.Background(), report); err != nil {
+		t.Fatalf("Publish() = %v", err)
+	}
+	if got.Status != health.StatusPass || !got.Checks["database"].Healthy {
+		t.Errorf("server received %+v", got)
+	}
+
+	report.Status = health.StatusFail
+	if err := publisher.Publish(context.Background(), report); err == nil {
+		t.Error("expected an error for a 502 response")
+	}
+}
@@ -0,0 +1,49 @@
+package health
+
+// RedactedMessage replaces the message of failing checks whose errors are
+// redacted from HTTP responses.
+const RedactedMessage = "check failed"
+
+// Sensitive marks the check's messages as possibly containing secrets, like
+// DSNs, hostnames or stack traces. When the check fails, HTTP responses report
+// RedactedMessage and no details instead. The full result is still returned
+// by CheckStatus.
+func Sensitive() CheckOption {
+	return func(rc *registeredCheck) {
+		rc.sensitive = true
+	}
+}
+
+// WithRedactedErrors makes the handler redact all failing checks, as if they
+// were registered with the Sensitive option.
+func WithRedactedErrors(redacted bool) HandlerOption {
+	return func(c *handlerConfig) {
+		c.redact = redacted
+	}
+}
+
+// Redacted returns a copy of the status with the message and details of
+// failing Sensitive checks replaced, as status handlers report them, for
+// publishing the status elsewhere.
+func (s Status) Redacted() Status {
+	checks := make(Status, len(s))
+	for name, check := range s {
+		checks[name] = check
+	}
+	return redact(checks, false)
+}
+
+// redact returns checks with the message and details of failing sensitive
+// checks replaced, or of all failing checks if all is set. checks is
+// modified in place.
+func redact(checks Status, all bool) Status {
+	for name, check := range checks {
+		if check.Healthy || !(all || check.sensitive) {
+			continue
+		}
+		check.Message = RedactedMessage
+		check.Details = nil
+		checks[name] = check
+	}
+	return checks
+}
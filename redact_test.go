@@ -0,0 +1,53 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestRedaction ensures failing sensitive checks are redacted from responses
+// but not from CheckStatus.
+func TestRedaction(t *testing.T) {
+	const secret = "dial postgres://user:secret@db:5432"
+	failing := CheckFunc(func() Result {
+		return Result{Error: errors.New(secret), Message: secret, Details: map[string]any{"dsn": secret}}
+	})
+	registry := NewRegistry()
+	registry.Register("sensitive", failing, Sensitive())
+	registry.Register("public", failing)
+	registry.Register("healthy", CheckFunc(func() Result { return Result{Message: "ok"} }), Sensitive())
+
+	get := func(opts ...HandlerOption) Status {
+		req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+		if err != nil {
+			t.Fatalf("Failed to create request.")
+		}
+		recorder := httptest.NewRecorder()
+		registry.Handler(opts...).ServeHTTP(recorder, req)
+		var status Status
+		if err := json.Unmarshal(recorder.Body.Bytes(), &status); err != nil {
+			t.Fatalf("error decoding response: %v", err)
+		}
+		return status
+	}
+
+	status := get()
+	if status["sensitive"].Message != RedactedMessage || status["sensitive"].Details != nil {
+		t.Errorf("expected sensitive check to be redacted, got %+v", status["sensitive"])
+	}
+	if status["public"].Message != secret || status["healthy"].Message != "ok" {
+		t.Errorf("expected other checks not to be redacted, got %+v", status)
+	}
+
+	status = get(WithRedactedErrors(true))
+	if status["public"].Message != RedactedMessage || status["healthy"].Message != "ok" {
+		t.Errorf("expected failing checks to be redacted, got %+v", status)
+	}
+
+	if registry.CheckStatus()["sensitive"].Message != secret {
+		t.Errorf("expected CheckStatus not to be redacted")
+	}
+}
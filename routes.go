@@ -0,0 +1,56 @@
+package health
+
+import "net/http"
+
+// Paths mounted by RegisterRoutes.
+const (
+	HealthzPath = "/healthz"
+	ReadyzPath  = "/readyz"
+	LivezPath   = "/livez"
+)
+
+// RegisterRoutes mounts the status handlers of the registry on mux, created
+// with opts. The authorizers and the redaction configured by opts apply to
+// every route:
+//
+//   - HealthzPath and DefaultPath serve the status of all checks
+//   - ReadyzPath serves the status of the Readiness checks
+//   - LivezPath serves the status of the Liveness checks
+//   - HistoryPath serves the history of the checks, see HistoryHandler
+//   - MetricsPath serves the status of the checks as OpenMetrics, see
+//     MetricsHandler
+//   - TracePath serves the last executions of the checks, see TraceHandler
+//   - StatsPath serves the statistics of the checks, see StatsHandler
+//   - StreamPath streams the status of all checks, see Stream
+func (registry *Registry) RegisterRoutes(mux *http.ServeMux, opts ...HandlerOption) {
+	all := registry.Handler(opts...)
+	mux.Handle(HealthzPath, all)
+	mux.Handle(DefaultPath, all)
+	mux.Handle(ReadyzPath, registry.Handler(append(opts, ForKind(Readiness))...))
+	mux.Handle(LivezPath, registry.Handler(append(opts, ForKind(Liveness))...))
+	mux.Handle(HistoryPath, protected(registry.serveHistory, opts))
+	mux.Handle(MetricsPath, protected(registry.serveMetrics, opts))
+	mux.Handle(TracePath, protected(registry.serveTrace, opts))
+	mux.Handle(StatsPath, protected(registry.serveStats, opts))
+	mux.Handle(StreamPath, registry.Stream(DefaultStreamInterval, DefaultStreamHeartbeat, opts...))
+}
+
+// RegisterRoutes mounts the status handlers of the default registry on mux.
+func RegisterRoutes(mux *http.ServeMux, opts ...HandlerOption) {
+	Default().RegisterRoutes(mux, opts...)
+}
+
+// protected returns a handler calling serve for the requests the authorizers
+// configured by opts accept. serve is told whether all failing checks are
+// redacted, see WithRedactedErrors.
+func protected(serve func(w http.ResponseWriter, r *http.Request, redactAll bool), opts []HandlerOption) http.Handler {
+	var c handlerConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c.authorize(w, r) {
+			serve(w, r, c.redact)
+		}
+	})
+}
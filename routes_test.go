@@ -0,0 +1,82 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRegisterRoutes ensures every route serves the checks of its kind.
+func TestRegisterRoutes(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("live", CheckFunc(func() Result { return Result{} }), WithKind(Liveness))
+	registry.Register("ready", CheckFunc(func() Result { return Result{Error: errors.New("failing")} }))
+
+	mux := http.NewServeMux()
+	registry.RegisterRoutes(mux)
+
+	for path, expected := range map[string]struct {
+		code   int
+		checks int
+	}{
+		HealthzPath: {http.StatusServiceUnavailable, 2},
+		DefaultPath: {http.StatusServiceUnavailable, 2},
+		ReadyzPath:  {http.StatusServiceUnavailable, 1},
+		LivezPath:   {http.StatusOK, 1},
+	} {
+		req, err := http.NewRequest("GET", "https://fakeurl.com"+path, nil)
+		if err != nil {
+			t.Fatalf("Failed to create request.")
+		}
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, req)
+
+		var status Status
+		if err := json.Unmarshal(recorder.Body.Bytes(), &status); err != nil {
+			t.Fatalf("%s: error decoding response: %v", path, err)
+		}
+		if recorder.Code != expected.code || len(status) != expected.checks {
+			t.Errorf("%s: expected %d with %d checks, got %d %+v", path, expected.code, expected.checks, recorder.Code, status)
+		}
+	}
+}
+
+// TestRegisterRoutesProtected ensures the authorizers and the redaction
+// configured for RegisterRoutes apply to the debug routes.
+func TestRegisterRoutesProtected(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("database", CheckFunc(func() Result {
+		return Result{Error: errors.New("dial postgres://admin:secret@db"), Message: "dial postgres://admin:secret@db"}
+	}))
+	registry.CheckStatus()
+
+	mux := http.NewServeMux()
+	registry.RegisterRoutes(mux, WithBearerToken("token"), WithRedactedErrors(true))
+
+	// the stream ends with the request
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	for _, path := range []string{HistoryPath, MetricsPath, TracePath, StatsPath, StreamPath} {
+		req, err := http.NewRequestWithContext(ctx, "GET", "https://fakeurl.com"+path, nil)
+		if err != nil {
+			t.Fatalf("Failed to create request.")
+		}
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, req)
+		if recorder.Code != http.StatusUnauthorized {
+			t.Errorf("%s: expected unauthenticated requests to be rejected, got %d", path, recorder.Code)
+		}
+
+		req.Header.Set("Authorization", "Bearer token")
+		recorder = httptest.NewRecorder()
+		mux.ServeHTTP(recorder, req)
+		if recorder.Code != http.StatusOK || strings.Contains(recorder.Body.String(), "secret") {
+			t.Errorf("%s: expected a redacted response, got %d %s", path, recorder.Code, recorder.Body)
+		}
+	}
+}
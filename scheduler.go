@@ -0,0 +1,319 @@
+package health
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// Scheduler runs periodic checks from a single goroutine and timer, instead
+// of one goroutine per check, for services registering many of them. Every
+// registry owns one running the checks registered with RegisterPeriodicFunc
+// and RegisterPeriodicThresholdFunc, other periodic checkers join one with
+// the WithScheduler option.
+type Scheduler struct {
+	clock Clock
+	// sem bounds the number of checks running at the same time, nil for no
+	// limit.
+	sem chan struct{}
+
+	startOnce sync.Once
+	timer     Timer
+	// wake interrupts the wait of the loop when the queue changes.
+	wake chan struct{}
+	done chan struct{}
+	// running counts the checks being run.
+	running sync.WaitGroup
+
+	mu     sync.Mutex
+	queue  scheduleQueue
+	checks map[*scheduledCheck]struct{}
+	paused bool
+	closed bool
+}
+
+// SchedulerOption configures a Scheduler created by NewScheduler.
+type SchedulerOption func(*Scheduler)
+
+// WithSchedulerConcurrency limits the number of checks the scheduler runs
+// at the same time. Checks that are due wait for a slot. A value of zero or
+// less, the default, means no limit.
+func WithSchedulerConcurrency(n int) SchedulerOption {
+	return func(s *Scheduler) {
+		if n > 0 {
+			s.sem = make(chan struct{}, n)
+		} else {
+			s.sem = nil
+		}
+	}
+}
+
+// WithSchedulerClock sets the clock timing the runs of the checks. By
+// default the time package is used.
+func WithSchedulerClock(clock Clock) SchedulerOption {
+	return func(s *Scheduler) {
+		s.clock = clock
+	}
+}
+
+// NewScheduler creates a new scheduler. It starts running checks once the
+// first one is scheduled.
+func NewScheduler(opts ...SchedulerOption) *Scheduler {
+	s := &Scheduler{
+		clock:  realClock{},
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+		checks: make(map[*scheduledCheck]struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// scheduledCheck is a periodic checker run by a Scheduler.
+type scheduledCheck struct {
+	Updater
+	scheduler *Scheduler
+	check     Checker
+	period    time.Duration
+	opts      periodicOptions
+	ctx       context.Context
+	cancel    context.CancelFunc
+	// owned is set if the scheduler was created for this check alone, and
+	// is closed when the check is stopped.
+	owned bool
+
+	// next is when the check runs next, index its position in the queue or
+	// -1 while it is not queued, and failures counts its consecutive failed
+	// runs. They are guarded by the scheduler's mutex.
+	next     time.Time
+	index    int
+	failures int
+}
+
+// Schedule runs check every period on the scheduler until the returned
+// checker is stopped, ctx is done or the scheduler is closed. It supports the
+// same options as PeriodicChecker, except the clock of the scheduler is used.
+func (s *Scheduler) Schedule(ctx context.Context, check Checker, period time.Duration, opts ...PeriodicOption) StoppableChecker {
+	o := periodicOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return s.schedule(ctx, check, period, o, false)
+}
+
+// schedule schedules check. If owned is set, the scheduler is closed when the
+// check is stopped.
+func (s *Scheduler) schedule(ctx context.Context, check Checker, period time.Duration, o periodicOptions, owned bool) StoppableChecker {
+	ctx, cancel := context.WithCancel(ctx)
+	sc := &scheduledCheck{
+		Updater:   NewStatusUpdater(),
+		scheduler: s,
+		check:     check,
+		period:    period,
+		opts:      o,
+		ctx:       ctx,
+		cancel:    cancel,
+		owned:     owned,
+		index:     -1,
+	}
+	if o.immediate {
+		sc.run()
+	}
+
+	s.startOnce.Do(s.start)
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		cancel()
+		return sc
+	}
+	s.checks[sc] = struct{}{}
+	sc.next = s.clock.Now().Add(o.next(period, sc.failures))
+	heap.Push(&s.queue, sc)
+	s.mu.Unlock()
+	s.notify()
+
+	context.AfterFunc(ctx, sc.Stop)
+	return sc
+}
+
+// Stop implements the StoppableChecker interface
+func (sc *scheduledCheck) Stop() {
+	s := sc.scheduler
+	s.mu.Lock()
+	delete(s.checks, sc)
+	if sc.index >= 0 {
+		heap.Remove(&s.queue, sc.index)
+	}
+	s.mu.Unlock()
+	sc.cancel()
+	if sc.owned {
+		s.stop()
+	}
+}
+
+// run runs the check once, recording the result and counting failures.
+func (sc *scheduledCheck) run() {
+	clock := sc.scheduler.clock
+	start := clock.Now()
+	res := safeCheck(sc.ctx, WithContext(sc.check))
+	sc.Update(timed(res, start, clock.Now()))
+
+	sc.scheduler.mu.Lock()
+	defer sc.scheduler.mu.Unlock()
+	if res.Error != nil {
+		sc.failures++
+	} else {
+		sc.failures = 0
+	}
+}
+
+// Pause stops starting checks until Resume is called. Checks already running
+// complete. Checks that became due while paused run once resumed.
+func (s *Scheduler) Pause() {
+	s.mu.Lock()
+	s.paused = true
+	s.mu.Unlock()
+	s.notify()
+}
+
+// Resume starts running checks again after Pause.
+func (s *Scheduler) Resume() {
+	s.mu.Lock()
+	s.paused = false
+	s.mu.Unlock()
+	s.notify()
+}
+
+// Close stops running checks, cancels the contexts of the ones that are
+// running and waits for them to return. The checks keep reporting their last
+// result. Checks scheduled afterwards are never run.
+func (s *Scheduler) Close() error {
+	s.stop()
+	s.running.Wait()
+	return nil
+}
+
+// stop stops the loop of the scheduler and cancels all checks.
+func (s *Scheduler) stop() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.done)
+	checks := s.checks
+	s.checks = nil
+	for _, sc := range s.queue {
+		sc.index = -1
+	}
+	s.queue = nil
+	s.mu.Unlock()
+
+	for sc := range checks {
+		sc.cancel()
+	}
+}
+
+// notify wakes the loop up to look at the queue again.
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// start creates the timer and starts the loop of the scheduler.
+func (s *Scheduler) start() {
+	s.timer = s.clock.NewTimer(time.Hour)
+	s.timer.Stop()
+	go s.loop()
+}
+
+// loop starts the checks that are due and waits for the next one.
+func (s *Scheduler) loop() {
+	defer s.timer.Stop()
+	for {
+		s.mu.Lock()
+		now := s.clock.Now()
+		for !s.paused && len(s.queue) > 0 && !s.queue[0].next.After(now) {
+			sc := heap.Pop(&s.queue).(*scheduledCheck)
+			s.running.Add(1)
+			go s.execute(sc)
+		}
+		wait := time.Duration(-1)
+		if !s.paused && len(s.queue) > 0 {
+			wait = s.queue[0].next.Sub(now)
+		}
+		s.mu.Unlock()
+
+		if wait >= 0 {
+			s.timer.Reset(wait)
+		} else {
+			s.timer.Stop()
+		}
+		select {
+		case <-s.timer.C():
+		case <-s.wake:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// execute runs the check once a slot is available and queues it again.
+func (s *Scheduler) execute(sc *scheduledCheck) {
+	defer s.running.Done()
+	if s.sem != nil {
+		select {
+		case s.sem <- struct{}{}:
+			defer func() { <-s.sem }()
+		case <-sc.ctx.Done():
+			return
+		}
+	}
+	if sc.ctx.Err() != nil {
+		return
+	}
+	sc.run()
+
+	s.mu.Lock()
+	if _, ok := s.checks[sc]; ok {
+		sc.next = s.clock.Now().Add(sc.opts.next(sc.period, sc.failures))
+		heap.Push(&s.queue, sc)
+	}
+	s.mu.Unlock()
+	s.notify()
+}
+
+// scheduleQueue is a min-heap of checks ordered by their next run.
+type scheduleQueue []*scheduledCheck
+
+func (q scheduleQueue) Len() int { return len(q) }
+
+func (q scheduleQueue) Less(i, j int) bool { return q[i].next.Before(q[j].next) }
+
+func (q scheduleQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *scheduleQueue) Push(x any) {
+	sc := x.(*scheduledCheck)
+	sc.index = len(*q)
+	*q = append(*q, sc)
+}
+
+func (q *scheduleQueue) Pop() any {
+	old := *q
+	sc := old[len(old)-1]
+	old[len(old)-1] = nil
+	sc.index = -1
+	*q = old[:len(old)-1]
+	return sc
+}
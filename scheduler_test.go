@@ -0,0 +1,150 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestSchedulerRunsChecks ensures many checks share the scheduler and run at
+// their own period.
+func TestSchedulerRunsChecks(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)}
+	scheduler := NewScheduler(WithSchedulerClock(clock))
+	defer scheduler.Close()
+
+	var fast, slow atomic.Int32
+	scheduler.Schedule(context.Background(), CheckFunc(func() Result {
+		fast.Add(1)
+		return Result{}
+	}), time.Second)
+	scheduler.Schedule(context.Background(), CheckFunc(func() Result {
+		slow.Add(1)
+		return Result{}
+	}), 3*time.Second)
+
+	for i := 1; i <= 3; i++ {
+		clock.waitForTimers(t, 1)
+		clock.Add(time.Second)
+		waitFor(t, func() bool { return fast.Load() == int32(i) })
+	}
+	waitFor(t, func() bool { return slow.Load() == 1 })
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	if len(clock.timers) != 1 {
+		t.Errorf("expected the scheduler to use a single timer, got %d", len(clock.timers))
+	}
+}
+
+// TestSchedulerPause ensures paused schedulers do not start checks until
+// resumed.
+func TestSchedulerPause(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)}
+	scheduler := NewScheduler(WithSchedulerClock(clock))
+	defer scheduler.Close()
+
+	var runs atomic.Int32
+	scheduler.Schedule(context.Background(), CheckFunc(func() Result {
+		runs.Add(1)
+		return Result{}
+	}), time.Second)
+	clock.waitForTimers(t, 1)
+
+	scheduler.Pause()
+	clock.Add(time.Minute)
+	time.Sleep(10 * time.Millisecond)
+	if runs.Load() != 0 {
+		t.Fatalf("check ran while the scheduler was paused")
+	}
+
+	scheduler.Resume()
+	waitFor(t, func() bool { return runs.Load() == 1 })
+}
+
+// TestSchedulerConcurrency ensures the scheduler bounds the number of checks
+// running at the same time.
+func TestSchedulerConcurrency(t *testing.T) {
+	scheduler := NewScheduler(WithSchedulerConcurrency(2))
+	defer scheduler.Close()
+
+	var (
+		mu      sync.Mutex
+		running int
+		max     int
+		runs    atomic.Int32
+	)
+	for i := 0; i < 10; i++ {
+		scheduler.Schedule(context.Background(), CheckFunc(func() Result {
+			mu.Lock()
+			running++
+			if running > max {
+				max = running
+			}
+			mu.Unlock()
+			time.Sleep(5 * time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+			runs.Add(1)
+			return Result{}
+		}), time.Millisecond)
+	}
+	waitFor(t, func() bool { return runs.Load() >= 20 })
+
+	mu.Lock()
+	defer mu.Unlock()
+	if max > 2 {
+		t.Errorf("expected at most 2 checks running at the same time, got %d", max)
+	}
+}
+
+// TestSchedulerClose ensures closing waits for the running checks, which
+// keep reporting their last result, and stops running them.
+func TestSchedulerClose(t *testing.T) {
+	scheduler := NewScheduler()
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	var runs atomic.Int32
+	check := scheduler.Schedule(context.Background(), CheckFunc(func() Result {
+		started <- struct{}{}
+		<-release
+		runs.Add(1)
+		return Result{Message: "done"}
+	}), time.Millisecond)
+	<-started
+
+	closed := make(chan struct{})
+	go func() {
+		scheduler.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+		t.Fatalf("Close returned while a check was running")
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(release)
+	<-closed
+
+	time.Sleep(10 * time.Millisecond)
+	if runs.Load() != 1 {
+		t.Errorf("expected the check to stop running once closed, ran %d times", runs.Load())
+	}
+	if res := check.Check(); res.Message != "done" {
+		t.Errorf("expected the last result to be kept, got %+v", res)
+	}
+}
+
+// waitFor waits up to a second for cond to hold.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
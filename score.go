@@ -0,0 +1,52 @@
+package health
+
+// WithWeight sets the weight of the check in the score of the registry. The
+// default weight is 1, a weight of 0 leaves the check out of the score.
+func WithWeight(weight float64) CheckOption {
+	return func(rc *registeredCheck) {
+		rc.weight = weight
+	}
+}
+
+// Score evaluates the checks and returns the health of the service as a
+// number between 0 and 100, for orchestrators shifting traffic in
+// proportion to it. It is the weighted average of the checks, which count
+// as 100 when healthy, 50 when degraded and 0 when failing. Disabled checks
+// are left out. Without checks the score is 100.
+func (registry *Registry) Score() float64 {
+	return score(registry.CheckStatus())
+}
+
+// Score evaluates the checks in the default registry and returns the health
+// of the service as a number between 0 and 100.
+func Score() float64 {
+	return Default().Score()
+}
+
+// Score returns the weighted score of the checks between 0 and 100, see
+// Registry.Score.
+func (s Status) Score() float64 {
+	return score(s)
+}
+
+// score returns the weighted score of checks.
+func score(checks Status) float64 {
+	var total, sum float64
+	for _, check := range checks {
+		if check.Disabled {
+			continue
+		}
+		total += check.weight
+		switch {
+		case !check.Healthy:
+		case check.State == StateDegraded:
+			sum += check.weight / 2
+		default:
+			sum += check.weight
+		}
+	}
+	if total == 0 {
+		return 100
+	}
+	return 100 * sum / total
+}
@@ -0,0 +1,45 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestScore ensures the score is the weighted average of the checks.
+func TestScore(t *testing.T) {
+	registry := NewRegistry()
+	if score := registry.Score(); score != 100 {
+		t.Errorf("expected 100 without checks, got %v", score)
+	}
+
+	healthy := CheckFunc(func() Result { return Result{} })
+	registry.Register("db", healthy, WithWeight(3))
+	registry.Register("cache", CheckFunc(func() Result { return Result{Degraded: true} }), WithWeight(2))
+	registry.Register("queue", CheckFunc(func() Result { return Result{Error: errors.New("down")} }))
+	registry.Register("ignored", CheckFunc(func() Result { return Result{Error: errors.New("down")} }), WithWeight(0))
+	registry.Register("flagged", CheckFunc(func() Result { return Result{Error: errors.New("down")} }))
+	registry.Disable("flagged")
+
+	// (3*100 + 2*50 + 1*0) / 6
+	expected := 400.0 / 6
+	if score := registry.Score(); score != expected {
+		t.Errorf("expected %v, got %v", expected, score)
+	}
+
+	req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request.")
+	}
+	recorder := httptest.NewRecorder()
+	registry.Handler(WithEnvelope()).ServeHTTP(recorder, req)
+	var envelope StatusEnvelope
+	if err := json.Unmarshal(recorder.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("error decoding response: %v", err)
+	}
+	if envelope.Score != expected {
+		t.Errorf("expected score %v in the envelope, got %v", expected, envelope.Score)
+	}
+}
@@ -0,0 +1,46 @@
+package health
+
+import "net/http"
+
+// selection returns the checks the request selects among the checks of the
+// handler, with the "check" and "exclude" query parameters. Both may be
+// repeated:
+//
+//	/debug/health?check=db&check=cache
+//	/debug/health?exclude=slow-external
+//
+// Only the selected checks are run, so operators can probe checks ad hoc
+// without the cost of running all of them. The name of the first check the
+// request asks for that is not registered is returned instead.
+func (h *statusHandler) selection(r *http.Request) (func(*registeredCheck) bool, string) {
+	query := r.URL.Query()
+	names, excludes := query["check"], query["exclude"]
+	if len(names) == 0 && len(excludes) == 0 {
+		return h.include, ""
+	}
+
+	var selected map[*registeredCheck]bool
+	if len(names) > 0 {
+		selected = make(map[*registeredCheck]bool, len(names))
+		for _, name := range names {
+			rc := h.registry.lookup(name)
+			if rc == nil {
+				return nil, name
+			}
+			selected[rc] = true
+		}
+	}
+	excluded := make(map[*registeredCheck]bool, len(excludes))
+	for _, name := range excludes {
+		if rc := h.registry.lookup(name); rc != nil {
+			excluded[rc] = true
+		}
+	}
+
+	return func(rc *registeredCheck) bool {
+		if selected != nil && !selected[rc] {
+			return false
+		}
+		return !excluded[rc] && h.include(rc)
+	}, ""
+}
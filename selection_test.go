@@ -0,0 +1,78 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// TestSelection ensures requests can select the checks they run.
+func TestSelection(t *testing.T) {
+	registry := NewRegistry()
+	runs := make(map[string]*atomic.Int32)
+	register := func(registry *Registry, name string, opts ...CheckOption) {
+		n := &atomic.Int32{}
+		runs[name] = n
+		registry.Register(name, CheckFunc(func() Result {
+			n.Add(1)
+			return Result{}
+		}), opts...)
+	}
+	register(registry, "db")
+	register(registry, "cache")
+	register(registry, "slow-external", WithKind(Liveness))
+	child := NewRegistry()
+	register(child, "queue")
+	registry.Mount("lib", child)
+
+	get := func(handler http.Handler, query string) (int, []string) {
+		req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health?"+query, nil)
+		if err != nil {
+			t.Errorf("Failed to create request.")
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		var status Status
+		if recorder.Code == http.StatusOK {
+			if err := json.Unmarshal(recorder.Body.Bytes(), &status); err != nil {
+				t.Fatalf("error decoding response: %v", err)
+			}
+		}
+		return recorder.Code, sortedNames(status)
+	}
+
+	handler := registry.Handler()
+	for query, expected := range map[string]string{
+		"":                                "cache,db,lib/queue,slow-external",
+		"check=db&check=cache":            "cache,db",
+		"check=lib/queue":                 "lib/queue",
+		"exclude=slow-external":           "cache,db,lib/queue",
+		"exclude=lib/queue&exclude=db":    "cache,slow-external",
+		"check=db&check=cache&exclude=db": "cache",
+		"exclude=unknown":                 "cache,db,lib/queue,slow-external",
+	} {
+		code, names := get(handler, query)
+		if code != http.StatusOK || strings.Join(names, ",") != expected {
+			t.Errorf("%q: unexpected checks %d %v, expected %s", query, code, names, expected)
+		}
+	}
+
+	before := runs["slow-external"].Load()
+	get(handler, "check=db")
+	if runs["slow-external"].Load() != before {
+		t.Errorf("expected checks that are not selected not to run")
+	}
+
+	if code, _ := get(handler, "check=db&check=unknown"); code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown checks, got %d", code)
+	}
+
+	// the selection is restricted to the checks of the handler
+	code, names := get(registry.Handler(ForKind(Readiness)), "check=slow-external&check=db")
+	if code != http.StatusOK || strings.Join(names, ",") != "db" {
+		t.Errorf("unexpected checks %d %v", code, names)
+	}
+}
@@ -0,0 +1,36 @@
+package health
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestSelfProbe ensures the probe follows the status of the endpoint.
+func TestSelfProbe(t *testing.T) {
+	registry := NewRegistry()
+	updater := NewStatusUpdater()
+	registry.Register("check", updater)
+	server := httptest.NewServer(registry.Handler())
+	defer server.Close()
+
+	if err := SelfProbe(server.URL); err != nil {
+		t.Errorf("unexpected error probing healthy endpoint: %v", err)
+	}
+	if code, ok := handleHealthcheckFlag([]string{"--healthcheck"}, server.URL, io.Discard); !ok || code != 0 {
+		t.Errorf("expected flag to be handled with code 0, got %d %v", code, ok)
+	}
+
+	updater.Update(Result{Error: errors.New("failing")})
+	if err := SelfProbe(server.URL); err == nil {
+		t.Errorf("expected error probing unhealthy endpoint")
+	}
+	if code, ok := handleHealthcheckFlag([]string{"-v", HealthcheckFlag}, server.URL, io.Discard); !ok || code != 1 {
+		t.Errorf("expected flag to be handled with code 1, got %d %v", code, ok)
+	}
+
+	if _, ok := handleHealthcheckFlag([]string{"-v"}, server.URL, io.Discard); ok {
+		t.Errorf("expected no probe without the flag")
+	}
+}
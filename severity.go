@@ -0,0 +1,58 @@
+package health
+
+// Severity determines how a failing check affects the aggregate status.
+type Severity string
+
+const (
+	// Critical checks make the service unhealthy when they fail. This is the
+	// severity of checks registered without a WithSeverity option.
+	Critical Severity = "critical"
+
+	// Warning checks are reported when they fail, but leave the service
+	// healthy. Use them for dependencies the service can degrade without.
+	Warning Severity = "warning"
+)
+
+// WithSeverity sets the severity of the check.
+func WithSeverity(severity Severity) CheckOption {
+	return func(rc *registeredCheck) {
+		rc.severity = severity
+	}
+}
+
+// Status returns StatusPass if the check is healthy, StatusWarn if it
+// is degraded, or fails with Warning severity or while initializing, and
+// StatusFail otherwise, as reported by the status handlers.
+func (check HealthCheck) Status() string {
+	switch {
+	case check.Healthy && check.State == StateDegraded:
+		return StatusWarn
+	case check.Healthy:
+		return StatusPass
+	case check.Severity == Warning, check.Initializing:
+		return StatusWarn
+	}
+	return StatusFail
+}
+
+// Overall returns the aggregate status of the checks, StatusPass, StatusWarn
+// or StatusFail, as reported by the status handlers.
+func (s Status) Overall() string {
+	return overallStatus(s)
+}
+
+// overallStatus aggregates the status of the checks: StatusFail if any
+// critical check fails, StatusWarn if only warning checks fail or checks are
+// degraded, and StatusPass otherwise.
+func overallStatus(checks Status) string {
+	overall := StatusPass
+	for _, check := range checks {
+		switch check.Status() {
+		case StatusFail:
+			return StatusFail
+		case StatusWarn:
+			overall = StatusWarn
+		}
+	}
+	return overall
+}
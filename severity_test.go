@@ -0,0 +1,50 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestWarningSeverity ensures failing warning checks are reported without
+// failing the endpoint.
+func TestWarningSeverity(t *testing.T) {
+	registry := NewRegistry()
+	cache := NewStatusUpdater()
+	database := NewStatusUpdater()
+	registry.Register("cache", cache, WithSeverity(Warning))
+	registry.Register("database", database)
+	handler := registry.Handler(WithEnvelope())
+
+	get := func() (int, StatusEnvelope) {
+		req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+		if err != nil {
+			t.Fatalf("Failed to create request.")
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+
+		var env StatusEnvelope
+		if err := json.Unmarshal(recorder.Body.Bytes(), &env); err != nil {
+			t.Fatalf("error decoding envelope: %v", err)
+		}
+		return recorder.Code, env
+	}
+
+	cache.Update(Result{Error: errors.New("cache unavailable")})
+	code, env := get()
+	if code != http.StatusOK || env.Status != StatusWarn {
+		t.Errorf("unexpected response with failing warning check: %d %s", code, env.Status)
+	}
+	if check := env.Checks["cache"]; check.Healthy || check.Severity != Warning {
+		t.Errorf("unexpected cache check: %+v", check)
+	}
+
+	database.Update(Result{Error: errors.New("database unavailable")})
+	code, env = get()
+	if code != http.StatusServiceUnavailable || env.Status != StatusFail {
+		t.Errorf("unexpected response with failing critical check: %d %s", code, env.Status)
+	}
+}
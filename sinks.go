@@ -0,0 +1,228 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/docker/distribution/health/internal/httpjson"
+)
+
+// webhookQueueSize is the number of events a webhook sink buffers while
+// posting. Further events are dropped until it catches up.
+const webhookQueueSize = 64
+
+// SlogSink returns a Sink logging events to logger. Runs of checks are
+// logged at debug level, checks becoming unhealthy as warnings, and other
+// state changes and the registry closing as info.
+func SlogSink(logger *slog.Logger) Sink {
+	return SinkFunc(func(event Event) {
+		level := slog.LevelDebug
+		attrs := []slog.Attr{slog.String("event", string(event.Type))}
+		if event.Check != "" {
+			attrs = append(attrs, slog.String("check", event.Check))
+		}
+		switch event.Type {
+		case CheckCompleted, StateChanged:
+			attrs = append(attrs,
+				slog.String("state", string(event.State)),
+				slog.Duration("duration", event.Duration),
+			)
+			if event.Error != nil {
+				attrs = append(attrs,
+					slog.String("error", event.Error.Error()),
+					slog.Int("consecutive_failures", event.Failures),
+				)
+			}
+			if event.Type == StateChanged {
+				attrs = append(attrs, slog.String("previous_state", string(event.PreviousState)))
+				level = slog.LevelInfo
+				if event.State == StateUnhealthy {
+					level = slog.LevelWarn
+				}
+			}
+		case RegistryClosed:
+			level = slog.LevelInfo
+		}
+		logger.LogAttrs(context.Background(), level, "health "+string(event.Type), attrs...)
+	})
+}
+
+// MetricsSink is a Sink counting the runs, failures and state changes of the
+// checks, and totalling the time they ran. It implements expvar.Var, so it
+// can be served by /debug/vars with expvar.Publish.
+type MetricsSink struct {
+	mu     sync.Mutex
+	checks map[string]*CheckMetrics
+}
+
+// CheckMetrics are the metrics of a check counted by a MetricsSink.
+type CheckMetrics struct {
+	Runs         int64         `json:"runs"`
+	Failures     int64         `json:"failures"`
+	StateChanges int64         `json:"state_changes"`
+	Duration     time.Duration `json:"duration_ns"`
+}
+
+// NewMetricsSink returns an empty MetricsSink.
+func NewMetricsSink() *MetricsSink {
+	return &MetricsSink{checks: make(map[string]*CheckMetrics)}
+}
+
+// Handle implements the Sink interface
+func (m *MetricsSink) Handle(event Event) {
+	if event.Type != CheckCompleted && event.Type != StateChanged {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, ok := m.checks[event.Check]
+	if !ok {
+		c = &CheckMetrics{}
+		m.checks[event.Check] = c
+	}
+	if event.Type == StateChanged {
+		c.StateChanges++
+		return
+	}
+	c.Runs++
+	c.Duration += event.Duration
+	if !event.Healthy {
+		c.Failures++
+	}
+}
+
+// Metrics returns the metrics of every check that ran.
+func (m *MetricsSink) Metrics() map[string]CheckMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	metrics := make(map[string]CheckMetrics, len(m.checks))
+	for name, c := range m.checks {
+		metrics[name] = *c
+	}
+	return metrics
+}
+
+// String implements expvar.Var, returning the metrics as JSON.
+func (m *MetricsSink) String() string {
+	p, err := json.Marshal(m.Metrics())
+	if err != nil {
+		return "{}"
+	}
+	return string(p)
+}
+
+// WebhookEvent is the body a webhook sink posts for an event.
+type WebhookEvent struct {
+	Type          EventType `json:"type"`
+	Check         string    `json:"check,omitempty"`
+	Time          time.Time `json:"time"`
+	Healthy       bool      `json:"healthy"`
+	State         State     `json:"state,omitempty"`
+	PreviousState State     `json:"previous_state,omitempty"`
+	Code          Code      `json:"code,omitempty"`
+	Message       string    `json:"message,omitempty"`
+	Error         string    `json:"error,omitempty"`
+	DurationMs    float64   `json:"duration_ms"`
+	Failures      int       `json:"failures,omitempty"`
+}
+
+// WebhookSink is a Sink POSTing events as a WebhookEvent JSON body to a URL.
+// Events are posted in order by a background goroutine, and dropped when it
+// falls too far behind.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+	types  map[EventType]bool
+
+	// mu guards closed, events are not queued once the sink is closed.
+	mu     sync.RWMutex
+	closed bool
+	queue  chan Event
+	done   chan struct{}
+}
+
+// NewWebhookSink returns a WebhookSink posting the events of the given
+// types to url, or events of all types if none are given. A nil client uses
+// http.DefaultClient. Close stops it.
+func NewWebhookSink(url string, client *http.Client, types ...EventType) *WebhookSink {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	s := &WebhookSink{
+		url:    url,
+		client: client,
+		queue:  make(chan Event, webhookQueueSize),
+		done:   make(chan struct{}),
+	}
+	if len(types) > 0 {
+		s.types = make(map[EventType]bool, len(types))
+		for _, t := range types {
+			s.types[t] = true
+		}
+	}
+	go s.run()
+	return s
+}
+
+// Handle implements the Sink interface
+func (s *WebhookSink) Handle(event Event) {
+	if s.types != nil && !s.types[event.Type] {
+		return
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.queue <- event:
+	default:
+		DefaultLogger().Printf("health webhook sink falling behind, dropped %s event", event.Type)
+	}
+}
+
+// Close stops the sink after posting the events it queued.
+func (s *WebhookSink) Close() error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+	<-s.done
+	return nil
+}
+
+// run posts the queued events until the sink is closed.
+func (s *WebhookSink) run() {
+	defer close(s.done)
+	for event := range s.queue {
+		if err := s.post(event); err != nil {
+			DefaultLogger().Printf("error posting health %s event: %v", event.Type, err)
+		}
+	}
+}
+
+// post sends event to the webhook.
+func (s *WebhookSink) post(event Event) error {
+	body := WebhookEvent{
+		Type:          event.Type,
+		Check:         event.Check,
+		Time:          event.Time,
+		Healthy:       event.Healthy,
+		State:         event.State,
+		PreviousState: event.PreviousState,
+		Code:          event.Code,
+		Message:       event.Message,
+		DurationMs:    float64(event.Duration) / float64(time.Millisecond),
+		Failures:      event.Failures,
+	}
+	if event.Error != nil {
+		body.Error = event.Error.Error()
+	}
+	return httpjson.Post(context.Background(), s.client, s.url, body)
+}
@@ -0,0 +1,113 @@
+package health
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSlogSink(t *testing.T) {
+	var buf bytes.Buffer
+	registry := NewRegistry()
+	updater := NewStatusUpdater()
+	registry.Register("database", updater)
+	registry.AddSink(SlogSink(slog.New(slog.NewTextHandler(&buf, nil))))
+
+	registry.CheckStatus()
+	if buf.Len() != 0 {
+		t.Errorf("runs of checks should be logged at debug level: %s", buf.String())
+	}
+
+	updater.Update(Result{Error: errors.New("connection refused")})
+	registry.CheckStatus()
+	out := buf.String()
+	for _, expected := range []string{
+		"level=WARN",
+		"health state_changed",
+		"check=database",
+		"state=unhealthy",
+		"previous_state=healthy",
+		`error="connection refused"`,
+		"consecutive_failures=1",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %q in log output: %s", expected, out)
+		}
+	}
+}
+
+func TestMetricsSink(t *testing.T) {
+	registry := NewRegistry()
+	updater := NewStatusUpdater()
+	registry.Register("database", updater)
+	metrics := NewMetricsSink()
+	registry.AddSink(metrics)
+
+	registry.CheckStatus()
+	updater.Update(Result{Error: errors.New("connection refused")})
+	registry.CheckStatus()
+	registry.CheckStatus()
+
+	m := metrics.Metrics()["database"]
+	if m.Runs != 3 || m.Failures != 2 || m.StateChanges != 1 {
+		t.Errorf("unexpected metrics: %+v", m)
+	}
+
+	var vars map[string]CheckMetrics
+	if err := json.Unmarshal([]byte(metrics.String()), &vars); err != nil {
+		t.Fatalf("error decoding metrics: %v", err)
+	}
+	if vars["database"] != m {
+		t.Errorf("unexpected expvar metrics: %+v != %+v", vars["database"], m)
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []WebhookEvent
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event WebhookEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("error decoding event: %v", err)
+		}
+		mu.Lock()
+		received = append(received, event)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	registry := NewRegistry()
+	updater := NewStatusUpdater()
+	registry.Register("database", updater)
+	sink := NewWebhookSink(server.URL, nil, StateChanged)
+	registry.AddSink(sink)
+
+	registry.CheckStatus()
+	updater.Update(Result{Error: errors.New("connection refused")})
+	registry.CheckStatus()
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+	// events after Close are ignored
+	sink.Handle(Event{Type: StateChanged, Time: time.Now()})
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 1 {
+		t.Fatalf("expected 1 event, got %+v", received)
+	}
+	event := received[0]
+	if event.Type != StateChanged || event.Check != "database" || event.Healthy ||
+		event.State != StateUnhealthy || event.Error != "connection refused" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+}
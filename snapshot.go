@@ -0,0 +1,75 @@
+package health
+
+import "time"
+
+// CheckResult is the result of a check as seen by the application. It is a
+// superset of HealthCheck, keeping the error and using Go types rather than
+// their JSON representation.
+type CheckResult struct {
+	Healthy  bool
+	State    State
+	Error    error
+	Message  string
+	Severity Severity
+	Details  map[string]any
+	// Code is the machine readable reason of a failing or degraded check.
+	Code Code
+	// Metadata is the metadata the check was registered with, if any.
+	Metadata *Metadata
+	// Generation is the number of times the check was replaced.
+	Generation uint64
+
+	// Duration is how long the check took.
+	Duration time.Duration
+	// CheckedAt is when the check was run, LastSuccess when it last
+	// succeeded. LastSuccess is zero if it never did.
+	CheckedAt   time.Time
+	LastSuccess time.Time
+	// ConsecutiveFailures is the number of failed runs in a row, zero while
+	// the check is healthy.
+	ConsecutiveFailures int
+	// Initializing is set for failing checks during the registry's grace
+	// period.
+	Initializing bool
+}
+
+// Snapshot evaluates the checks like CheckStatus and returns their results
+// with all the information the registry has about them, for applications
+// making their own decisions based on health.
+func (registry *Registry) Snapshot() map[string]CheckResult {
+	checks := registry.CheckStatus()
+	snapshot := make(map[string]CheckResult, len(checks))
+	for name, check := range checks {
+		snapshot[name] = check.result()
+	}
+	return snapshot
+}
+
+// Snapshot evaluates the checks in the default registry and returns their
+// results.
+func Snapshot() map[string]CheckResult {
+	return Default().Snapshot()
+}
+
+// result converts check to a CheckResult.
+func (check HealthCheck) result() CheckResult {
+	res := CheckResult{
+		Healthy:             check.Healthy,
+		State:               check.State,
+		Error:               check.err,
+		Message:             check.Message,
+		Severity:            check.Severity,
+		Details:             check.Details,
+		Code:                check.Code,
+		Metadata:            check.Metadata,
+		Generation:          check.Generation,
+		Duration:            time.Duration(check.DurationMs * float64(time.Millisecond)),
+		CheckedAt:           check.LastChecked,
+		ConsecutiveFailures: check.failures,
+		Initializing:        check.Initializing,
+	}
+	if check.LastSuccess != nil {
+		res.LastSuccess = *check.LastSuccess
+	}
+	return res
+}
@@ -0,0 +1,37 @@
+package health
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestSnapshot ensures snapshots carry the error and the consecutive
+// failures of the checks.
+func TestSnapshot(t *testing.T) {
+	registry := NewRegistry()
+	updater := NewStatusUpdater()
+	errFailing := errors.New("failing")
+	registry.Register("check", updater, WithSeverity(Warning), WithMetadata(Metadata{ComponentType: "cache"}))
+	registry.Register("dependent", CheckFunc(func() Result { return Result{} }), WithDependencies("check"))
+
+	res := registry.Snapshot()["check"]
+	if !res.Healthy || res.Error != nil || res.ConsecutiveFailures != 0 || res.CheckedAt.IsZero() || res.LastSuccess.IsZero() {
+		t.Errorf("unexpected healthy result: %+v", res)
+	}
+
+	updater.Update(Result{Error: errFailing, Message: "failing", Code: CodeTimeout})
+	registry.Snapshot()
+	snapshot := registry.Snapshot()
+	res = snapshot["check"]
+	if res.Healthy || res.Error != errFailing || res.Message != "failing" || res.Severity != Warning || res.ConsecutiveFailures != 2 || res.Code != CodeTimeout || res.Metadata == nil || res.Metadata.ComponentType != "cache" {
+		t.Errorf("unexpected failing result: %+v", res)
+	}
+	if res := snapshot["dependent"]; res.Healthy || res.Error == nil {
+		t.Errorf("expected skipped check to carry an error, got %+v", res)
+	}
+
+	registry.RegisterOrReplace("check", NewStatusUpdater())
+	if res := registry.Snapshot()["check"]; res.Generation != 1 {
+		t.Errorf("expected the generation of the replaced check, got %+v", res)
+	}
+}
@@ -0,0 +1,39 @@
+package health
+
+import "github.com/docker/distribution/health/healthapi"
+
+// State is the health of a check or of a service, see healthapi.State.
+type State = healthapi.State
+
+// States of checks and services.
+const (
+	StateHealthy   = healthapi.StateHealthy
+	StateDegraded  = healthapi.StateDegraded
+	StateUnhealthy = healthapi.StateUnhealthy
+)
+
+// State evaluates the checks and returns the state of the service:
+// StateUnhealthy if a critical check fails, StateDegraded if only Warning
+// checks fail or checks are degraded, and StateHealthy otherwise. Status
+// handlers respond with the failure and degraded status codes in the first
+// two states, see WithFailureStatus and WithDegradedStatus.
+func (registry *Registry) State() State {
+	return aggregateState(overallStatus(registry.CheckStatus()))
+}
+
+// CurrentState evaluates the checks in the default registry and returns the
+// state of the service.
+func CurrentState() State {
+	return Default().State()
+}
+
+// aggregateState converts an aggregate status to a State.
+func aggregateState(overall string) State {
+	switch overall {
+	case StatusFail:
+		return StateUnhealthy
+	case StatusWarn:
+		return StateDegraded
+	}
+	return StateHealthy
+}
@@ -0,0 +1,48 @@
+package health
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestStates ensures degraded checks degrade the service without failing it.
+func TestStates(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("db", CheckFunc(func() Result { return Result{} }))
+	if state := registry.State(); state != StateHealthy {
+		t.Errorf("expected %s, got %s", StateHealthy, state)
+	}
+
+	registry.Register("cache", CheckFunc(func() Result {
+		return Result{Degraded: true, Message: "serving from the slow path"}
+	}))
+	check := registry.CheckStatus()["cache"]
+	if !check.Healthy || check.State != StateDegraded {
+		t.Errorf("expected a healthy degraded check, got %+v", check)
+	}
+	if state := registry.State(); state != StateDegraded {
+		t.Errorf("expected %s, got %s", StateDegraded, state)
+	}
+
+	req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request.")
+	}
+	recorder := httptest.NewRecorder()
+	registry.Handler(WithDegradedStatus(http.StatusMultiStatus)).ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusMultiStatus {
+		t.Errorf("expected the degraded status code, got %d", recorder.Code)
+	}
+
+	registry.Register("queue", CheckFunc(func() Result {
+		return Result{Error: errors.New("down"), Degraded: true}
+	}))
+	if check := registry.CheckStatus()["queue"]; check.Healthy || check.State != StateUnhealthy {
+		t.Errorf("expected errors to take precedence over Degraded, got %+v", check)
+	}
+	if state := registry.State(); state != StateUnhealthy {
+		t.Errorf("expected %s, got %s", StateUnhealthy, state)
+	}
+}
@@ -0,0 +1,146 @@
+package health
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"time"
+)
+
+// StatsPath is the path RegisterRoutes mounts StatsHandler at.
+const StatsPath = "/debug/health/stats"
+
+const (
+	// statsBuckets is the number of buckets of the duration histogram.
+	statsBuckets = 96
+	// statsBase is the upper bound of the first bucket, each following bucket
+	// is statsGrowth times wider. The last bucket ends after an hour.
+	statsBase   = 10 * time.Microsecond
+	statsGrowth = 1.25
+)
+
+// CheckStats are the cumulative statistics of the runs of a check since it
+// was registered. The percentiles are estimated from a histogram of the
+// durations, within 25% of the actual value.
+type CheckStats struct {
+	Count  int64   `json:"count"`
+	Errors int64   `json:"errors"`
+	P50Ms  float64 `json:"p50_ms"`
+	P95Ms  float64 `json:"p95_ms"`
+	MaxMs  float64 `json:"max_ms"`
+}
+
+// checkStats is a streaming histogram of the durations of the runs of a
+// check, with exponentially growing buckets.
+type checkStats struct {
+	count   int64
+	errors  int64
+	max     time.Duration
+	buckets [statsBuckets]int64
+}
+
+// bucket returns the index of the bucket counting runs lasting d.
+func bucket(d time.Duration) int {
+	if d <= statsBase {
+		return 0
+	}
+	i := int(math.Ceil(math.Log(float64(d)/float64(statsBase)) / math.Log(statsGrowth)))
+	return min(i, statsBuckets-1)
+}
+
+// bound returns the upper bound of the bucket i.
+func bound(i int) time.Duration {
+	return time.Duration(float64(statsBase) * math.Pow(statsGrowth, float64(i)))
+}
+
+// observeStats adds the run with result res to the statistics of the check.
+func (rc *registeredCheck) observeStats(res Result) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.stats.count++
+	if res.Error != nil {
+		rc.stats.errors++
+	}
+	rc.stats.max = max(rc.stats.max, res.Duration)
+	rc.stats.buckets[bucket(res.Duration)]++
+}
+
+// quantile estimates the duration below which the fraction q of the runs
+// completed.
+func (s *checkStats) quantile(q float64) time.Duration {
+	if s.count == 0 {
+		return 0
+	}
+	rank := int64(math.Ceil(q * float64(s.count)))
+	var seen int64
+	for i, n := range s.buckets {
+		seen += n
+		if seen >= rank {
+			return min(bound(i), s.max)
+		}
+	}
+	return s.max
+}
+
+// snapshot returns the statistics of the check.
+func (rc *registeredCheck) snapshot() CheckStats {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	ms := func(d time.Duration) float64 {
+		return float64(d) / float64(time.Millisecond)
+	}
+	return CheckStats{
+		Count:  rc.stats.count,
+		Errors: rc.stats.errors,
+		P50Ms:  ms(rc.stats.quantile(0.5)),
+		P95Ms:  ms(rc.stats.quantile(0.95)),
+		MaxMs:  ms(rc.stats.max),
+	}
+}
+
+// Stats returns the statistics of the checks of the registry, keyed by check
+// name. Only actual runs are counted, not results reused within the TTL or
+// skipped checks.
+func (registry *Registry) Stats() map[string]CheckStats {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	stats := make(map[string]CheckStats, len(registry.registeredChecks))
+	for name, rc := range registry.registeredChecks {
+		stats[name] = rc.snapshot()
+	}
+	return stats
+}
+
+// Stats returns the statistics of the checks in the default registry.
+func Stats() map[string]CheckStats {
+	return Default().Stats()
+}
+
+// StatsHandler responds with the statistics of the checks as a JSON object
+// keyed by check name, see Registry.Stats.
+func (registry *Registry) StatsHandler(w http.ResponseWriter, r *http.Request) {
+	registry.serveStats(w, r, false)
+}
+
+// serveStats responds with the statistics of the checks. They carry no
+// messages, so there is nothing to redact.
+func (registry *Registry) serveStats(w http.ResponseWriter, r *http.Request, _ bool) {
+	if r.Method != "GET" {
+		http.NotFound(w, r)
+		return
+	}
+
+	p, err := json.Marshal(registry.Stats())
+	if err != nil {
+		registry.log().Printf("error serializing health stats: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, registry.log(), http.StatusOK, "application/json; charset=utf-8", p)
+}
+
+// StatsHandler responds with the statistics of the checks in the default
+// registry.
+func StatsHandler(w http.ResponseWriter, r *http.Request) {
+	Default().StatsHandler(w, r)
+}
@@ -0,0 +1,89 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestStats ensures runs, errors and duration percentiles are counted.
+func TestStats(t *testing.T) {
+	registry := NewRegistry()
+	var (
+		duration time.Duration
+		err      error
+	)
+	registry.Register("check", CheckFunc(func() Result {
+		return Result{Error: err, Duration: duration, CheckedAt: time.Now()}
+	}))
+
+	for i := 1; i <= 100; i++ {
+		duration = time.Duration(i) * time.Millisecond
+		err = nil
+		if i%10 == 0 {
+			err = errors.New("failing")
+		}
+		registry.CheckStatus()
+	}
+
+	stats := registry.Stats()["check"]
+	if stats.Count != 100 || stats.Errors != 10 {
+		t.Errorf("unexpected counts: %+v", stats)
+	}
+	if stats.MaxMs != 100 {
+		t.Errorf("unexpected max: %v", stats.MaxMs)
+	}
+	for _, q := range []struct {
+		name     string
+		got, exp float64
+	}{{"p50", stats.P50Ms, 50}, {"p95", stats.P95Ms, 95}} {
+		if q.got < q.exp || q.got > q.exp*statsGrowth {
+			t.Errorf("%s out of range: %v, expected %v", q.name, q.got, q.exp)
+		}
+	}
+}
+
+// TestStatsBuckets ensures durations are counted in the bucket bounding
+// them.
+func TestStatsBuckets(t *testing.T) {
+	for _, d := range []time.Duration{0, time.Microsecond, statsBase, time.Millisecond, 3 * time.Second, time.Hour, 24 * time.Hour} {
+		i := bucket(d)
+		if d > bound(i) && i != statsBuckets-1 {
+			t.Errorf("%v above the bound of bucket %d: %v", d, i, bound(i))
+		}
+		if i > 0 && d <= bound(i-1) {
+			t.Errorf("%v should be in bucket %d, not %d", d, i-1, i)
+		}
+	}
+	if bound(statsBuckets-1) < time.Hour {
+		t.Errorf("last bucket ends before an hour: %v", bound(statsBuckets-1))
+	}
+}
+
+// TestStatsHandler ensures the statistics are served as JSON.
+func TestStatsHandler(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("check", NewStatusUpdater())
+	registry.CheckStatus()
+
+	req, err := http.NewRequest("GET", "https://fakeurl.com"+StatsPath, nil)
+	if err != nil {
+		t.Errorf("Failed to create request.")
+	}
+	recorder := httptest.NewRecorder()
+	registry.StatsHandler(recorder, req)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("unexpected status code: %d", recorder.Code)
+	}
+
+	var stats map[string]CheckStats
+	if err := json.Unmarshal(recorder.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("error decoding stats: %v", err)
+	}
+	if stats["check"].Count != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
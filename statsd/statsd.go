@@ -0,0 +1,175 @@
+// Package statsd sends the status and duration of health checks to statsd
+// or DogStatsD, for teams not running Prometheus. For every check it emits
+//
+//	health.check.status:1|g|#check:db
+//	health.check.duration:3.2|ms|#check:db
+//
+// with a status of 1 when healthy and 0 otherwise. Metrics are emitted on
+// every run of a check with Hook, or when checks change state with Run.
+//
+//	emitter, err := statsd.New("127.0.0.1:8125", statsd.WithTags("env:prod"))
+//	health.RegisterHook(emitter.Hook())
+package statsd
+
+import (
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/distribution/health"
+)
+
+// DefaultPrefix is the prefix of the metric names without a WithPrefix
+// option.
+const DefaultPrefix = "health."
+
+// Emitter sends metrics about health checks to statsd.
+type Emitter struct {
+	w         io.Writer
+	prefix    string
+	tags      []string
+	checkTags map[string][]string
+	plain     bool
+
+	mu sync.Mutex
+}
+
+// Option configures an Emitter.
+type Option func(*Emitter)
+
+// WithPrefix sets the prefix of the metric names.
+func WithPrefix(prefix string) Option {
+	return func(e *Emitter) {
+		e.prefix = prefix
+	}
+}
+
+// WithTags adds tags, like "env:prod", to all metrics.
+func WithTags(tags ...string) Option {
+	return func(e *Emitter) {
+		e.tags = append(e.tags, tags...)
+	}
+}
+
+// WithCheckTags adds tags to the metrics of the named check.
+func WithCheckTags(name string, tags ...string) Option {
+	return func(e *Emitter) {
+		e.checkTags[name] = append(e.checkTags[name], tags...)
+	}
+}
+
+// WithPlainStatsd emits metrics without tags for statsd servers that do not
+// support them. The name of the check is part of the metric names instead,
+// like health.check.db.status.
+func WithPlainStatsd() Option {
+	return func(e *Emitter) {
+		e.plain = true
+	}
+}
+
+// New returns an Emitter sending metrics over UDP to the statsd server at
+// addr.
+func New(addr string, opts ...Option) (*Emitter, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return NewWithWriter(conn, opts...), nil
+}
+
+// NewWithWriter returns an Emitter writing every metric to w as a separate
+// write.
+func NewWithWriter(w io.Writer, opts ...Option) *Emitter {
+	e := &Emitter{
+		w:         w,
+		prefix:    DefaultPrefix,
+		checkTags: make(map[string][]string),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Close closes the connection of the emitter, if it has one.
+func (e *Emitter) Close() error {
+	if c, ok := e.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Hook returns a health.Hook emitting the status and duration of every run
+// of a check.
+func (e *Emitter) Hook() health.Hook {
+	return func(name string, next health.CheckerWithContext) health.CheckerWithContext {
+		return health.CheckFuncWithContext(func(ctx context.Context) health.Result {
+			start := time.Now()
+			res := next.Check(ctx)
+			duration := res.Duration
+			if res.CheckedAt.IsZero() {
+				duration = time.Since(start)
+			}
+			e.Emit(name, res.Error == nil, duration)
+			return res
+		})
+	}
+}
+
+// Run emits the status and duration of the checks of registry whenever they
+// change between healthy and unhealthy, until ctx is done.
+func (e *Emitter) Run(ctx context.Context, registry *health.Registry) {
+	for change := range registry.Watch(ctx) {
+		e.Emit(change.Name, change.Healthy, change.Duration)
+	}
+}
+
+// Emit sends the status and duration of the named check.
+func (e *Emitter) Emit(name string, healthy bool, duration time.Duration) {
+	status := "0"
+	if healthy {
+		status = "1"
+	}
+	ms := strconv.FormatFloat(float64(duration)/float64(time.Millisecond), 'f', -1, 64)
+	e.send(name, "status", status+"|g")
+	e.send(name, "duration", ms+"|ms")
+}
+
+// send writes a metric of the named check. Errors are ignored, like lost
+// UDP packets.
+func (e *Emitter) send(name, metric, value string) {
+	var line string
+	if e.plain {
+		line = e.prefix + "check." + sanitize(name) + "." + metric + ":" + value
+	} else {
+		line = e.prefix + "check." + metric + ":" + value + "|#" + strings.Join(e.tagsFor(name), ",")
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.w.Write([]byte(line))
+}
+
+// tagsFor returns the tags of the metrics of the named check.
+func (e *Emitter) tagsFor(name string) []string {
+	tags := make([]string, 0, 1+len(e.tags)+len(e.checkTags[name]))
+	tags = append(tags, "check:"+sanitize(name))
+	tags = append(tags, e.tags...)
+	return append(tags, e.checkTags[name]...)
+}
+
+// sanitize replaces the characters of name that have a meaning in the
+// statsd protocol.
+func sanitize(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '|', ',', '#', '@', ' ', '\n':
+			return '_'
+		}
+		return r
+	}, name)
+}
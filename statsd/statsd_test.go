@@ -0,0 +1,89 @@
+package statsd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/distribution/health"
+)
+
+// lines records every write as a line.
+type lines struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *lines) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, string(p))
+	return len(p), nil
+}
+
+func TestHook(t *testing.T) {
+	w := &lines{}
+	emitter := NewWithWriter(w, WithTags("env:prod"), WithCheckTags("db", "team:storage"))
+	registry := health.NewRegistry()
+	registry.RegisterHook(emitter.Hook())
+	registry.Register("db", health.CheckFunc(func() health.Result {
+		return health.Result{Error: errors.New("down"), CheckedAt: time.Now(), Duration: 1500 * time.Microsecond}
+	}))
+	registry.CheckStatus()
+
+	expected := []string{
+		"health.check.status:0|g|#check:db,env:prod,team:storage",
+		"health.check.duration:1.5|ms|#check:db,env:prod,team:storage",
+	}
+	if len(w.lines) != len(expected) {
+		t.Fatalf("expected %q, got %q", expected, w.lines)
+	}
+	for i := range expected {
+		if w.lines[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], w.lines[i])
+		}
+	}
+}
+
+func TestPlainStatsd(t *testing.T) {
+	w := &lines{}
+	NewWithWriter(w, WithPrefix("svc."), WithPlainStatsd()).Emit("db:primary", true, time.Millisecond)
+	if len(w.lines) != 2 || w.lines[0] != "svc.check.db_primary.status:1|g" || w.lines[1] != "svc.check.db_primary.duration:1|ms" {
+		t.Errorf("unexpected metrics: %q", w.lines)
+	}
+}
+
+func TestRun(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error listening: %v", err)
+	}
+	defer conn.Close()
+	emitter, err := New(conn.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer emitter.Close()
+
+	registry := health.NewRegistry()
+	registry.Register("db", health.CheckFunc(func() health.Result { return health.Result{Error: errors.New("down")} }))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go emitter.Run(ctx, registry)
+	// give Run time to start watching
+	time.Sleep(50 * time.Millisecond)
+	registry.CheckStatus()
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	buf := make([]byte, 512)
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("no metric received: %v", err)
+	}
+	if got := string(buf[:n]); got != "health.check.status:0|g|#check:db" {
+		t.Errorf("unexpected metric: %q", got)
+	}
+}
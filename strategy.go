@@ -0,0 +1,131 @@
+package health
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultStrategyInterval is how often the checks of a registry are run in
+// the background with the Background and Hybrid strategies, unless set with
+// WithStrategy.
+const DefaultStrategyInterval = 10 * time.Second
+
+// Strategy is when the checks of a registry are run, see WithStrategy.
+type Strategy int
+
+const (
+	// OnRequest runs the checks whenever their status is requested, the
+	// default.
+	OnRequest Strategy = iota
+	// Background runs all checks on the scheduler of the registry, and once
+	// when they are registered, replaced, enabled or overridden. Their
+	// status only reports the last results, so status handlers respond in
+	// constant time regardless of the cost of the checks. Checks without a
+	// result yet are reported as initializing, with ErrPending.
+	Background
+	// Hybrid runs all checks on the scheduler of the registry, like
+	// Background, but runs the checks whose last result is older than two
+	// intervals when their status is requested.
+	Hybrid
+)
+
+// String returns the name of the strategy.
+func (s Strategy) String() string {
+	switch s {
+	case OnRequest:
+		return "on-request"
+	case Background:
+		return "background"
+	case Hybrid:
+		return "hybrid"
+	}
+	return "unknown"
+}
+
+// WithStrategy sets when the checks of the registry are run. With the
+// Background and Hybrid strategies, all checks are run every interval by the
+// scheduler of the registry until it is closed, DefaultStrategyInterval if
+// interval is not positive.
+func WithStrategy(strategy Strategy, interval time.Duration) RegistryOption {
+	return func(registry *Registry) {
+		if interval <= 0 {
+			interval = DefaultStrategyInterval
+		}
+		registry.strategy, registry.strategyInterval = strategy, interval
+	}
+}
+
+// refreshKey marks the context of the background runs of the checks.
+type refreshKey struct{}
+
+// startRefresh schedules the background runs of the checks of the registry,
+// if its strategy has them.
+func (registry *Registry) startRefresh() {
+	if registry.strategy == OnRequest {
+		return
+	}
+	ctx := context.WithValue(context.Background(), refreshKey{}, true)
+	registry.scheduler.Schedule(ctx, CheckFunc(func() Result {
+		registry.checkStatus(ctx, func(*registeredCheck) bool { return true })
+		return Result{}
+	}), registry.strategyInterval)
+}
+
+// lastStatus returns the last result of the check instead of running it,
+// following the strategy of the registry, or a pending status if the check
+// did not run in the background yet. It returns false if the check has to
+// run.
+func (registry *Registry) lastStatus(ctx context.Context, rc *registeredCheck) (HealthCheck, bool) {
+	if registry.strategy == OnRequest || ctx.Value(refreshKey{}) != nil {
+		return HealthCheck{}, false
+	}
+
+	rc.mu.Lock()
+	status, checkedAt := rc.cached, rc.cachedAt
+	rc.mu.Unlock()
+	if checkedAt.IsZero() {
+		return HealthCheck{
+			Message:      ErrPending.Error(),
+			Severity:     rc.severity,
+			State:        StateUnhealthy,
+			Initializing: true,
+			Metadata:     rc.metadata,
+			sensitive:    rc.sensitive,
+			weight:       rc.weight,
+			err:          ErrPending,
+		}, true
+	}
+	if registry.strategy == Hybrid && registry.clock.Now().Sub(checkedAt) >= 2*registry.strategyInterval {
+		return HealthCheck{}, false
+	}
+	return status, true
+}
+
+// prefetch runs the check registered as name in the background right away,
+// if the strategy of the registry has background runs, so its first result
+// does not wait for the next interval. A run in progress, which may have
+// started before the cached result was invalidated, is waited for rather
+// than joined.
+func (registry *Registry) prefetch(name string, rc *registeredCheck) {
+	if registry.strategy == OnRequest {
+		return
+	}
+	ctx := context.WithValue(context.Background(), refreshKey{}, true)
+	go func() {
+		registry.mu.RLock()
+		hooks := registry.hooks
+		registry.mu.RUnlock()
+		for {
+			if _, disabled := rc.disabledStatus(); disabled {
+				return
+			}
+			f, leader := rc.join()
+			if leader {
+				defer rc.land(f)
+				f.status = registry.execute(ctx, name, rc, hooks)
+				return
+			}
+			<-f.done
+		}
+	}()
+}
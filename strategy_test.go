@@ -0,0 +1,147 @@
+package health
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestStrategy ensures the Background and Hybrid strategies report the
+// results of the checks run by the scheduler.
+func TestStrategy(t *testing.T) {
+	for _, strategy := range []Strategy{Background, Hybrid} {
+		t.Run(strategy.String(), func(t *testing.T) {
+			clock := &fakeClock{now: time.Now()}
+			registry := NewRegistry(WithClock(clock), WithStrategy(strategy, time.Minute))
+			defer registry.Close()
+
+			var runs atomic.Int32
+			registry.Register("check", CheckFunc(func() Result {
+				runs.Add(1)
+				return Result{}
+			}))
+
+			waitForResult(t, registry, "check")
+			if status := registry.CheckStatus()["check"]; runs.Load() != 1 || !status.Healthy {
+				t.Errorf("expected the check to run once when registered, got %d runs and %+v", runs.Load(), status)
+			}
+
+			clock.waitForTimers(t, 1)
+			before := runs.Load()
+			clock.Add(time.Minute)
+			waitFor(t, func() bool { return runs.Load() == before+1 })
+
+			for i := 0; i < 3; i++ {
+				if status := registry.CheckStatus()["check"]; !status.Healthy {
+					t.Errorf("unexpected status: %+v", status)
+				}
+			}
+			if runs.Load() != before+1 {
+				t.Errorf("expected requests to report the background result, got %d runs", runs.Load())
+			}
+		})
+	}
+}
+
+// TestStrategyChanges ensures checks without a result, because they were
+// replaced or overridden, are reported as initializing rather than failing
+// with the Background strategy, until they ran in the background.
+func TestStrategyChanges(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	registry := NewRegistry(WithClock(clock), WithStrategy(Background, time.Minute))
+	defer registry.Close()
+
+	healthy := CheckFunc(func() Result { return Result{} })
+	registry.RegisterOrReplace("check", healthy)
+	if !registry.Healthy() {
+		t.Errorf("expected a registered check to be healthy")
+	}
+	registry.RegisterOrReplace("check", healthy)
+	if !registry.Healthy() {
+		t.Errorf("expected a replaced check to be healthy")
+	}
+	waitForResult(t, registry, "check")
+	if status := registry.CheckStatus()["check"]; !status.Healthy {
+		t.Errorf("expected the replaced check to run in the background, got %+v", status)
+	}
+
+	registry.Replace("failing", CheckFunc(func() Result {
+		return Result{Error: errors.New("down")}
+	}))
+	if err := registry.Override("failing", true, time.Hour, "known issue"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status := registry.CheckStatus()["failing"]; !status.Healthy && status.Status() != StatusWarn {
+		t.Errorf("expected an overridden check to be healthy or initializing, got %+v", status)
+	}
+	waitForResult(t, registry, "failing")
+	if status := registry.CheckStatus()["failing"]; !status.Healthy {
+		t.Errorf("expected a check overridden healthy to be healthy, got %+v", status)
+	}
+}
+
+// TestStrategyPending ensures requests do not wait for the first run of a
+// check with the Background strategy.
+func TestStrategyPending(t *testing.T) {
+	registry := NewRegistry(WithStrategy(Background, time.Minute))
+	defer registry.Close()
+
+	release := make(chan struct{})
+	defer close(release)
+	registry.Register("slow", CheckFunc(func() Result {
+		<-release
+		return Result{}
+	}))
+
+	done := make(chan Status, 1)
+	go func() { done <- registry.CheckStatus() }()
+	select {
+	case status := <-done:
+		check := status["slow"]
+		if check.Healthy || !check.Initializing || !errors.Is(check.err, ErrPending) || check.Status() != StatusWarn {
+			t.Errorf("expected a pending check, got %+v", check)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("status waited for the first run of the check")
+	}
+}
+
+// TestStrategyHybridStale ensures the Hybrid strategy runs checks whose
+// last result is too old.
+func TestStrategyHybridStale(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	registry := NewRegistry(WithClock(clock), WithStrategy(Hybrid, time.Minute))
+	// only requests run the check once the scheduler is closed
+	registry.Close()
+
+	var runs atomic.Int32
+	registry.Register("check", CheckFunc(func() Result {
+		runs.Add(1)
+		return Result{}
+	}))
+	waitForResult(t, registry, "check")
+	registry.CheckStatus()
+	clock.Add(time.Minute)
+	registry.CheckStatus()
+	if runs.Load() != 1 {
+		t.Errorf("expected a fresh result to be reused, got %d runs", runs.Load())
+	}
+	clock.Add(time.Minute)
+	registry.CheckStatus()
+	if runs.Load() != 2 {
+		t.Errorf("expected a stale result to be refreshed, got %d runs", runs.Load())
+	}
+}
+
+// waitForResult waits until the named check has a result cached by a
+// background run.
+func waitForResult(t *testing.T, registry *Registry, name string) {
+	t.Helper()
+	rc := registry.lookup(name)
+	waitFor(t, func() bool {
+		rc.mu.Lock()
+		defer rc.mu.Unlock()
+		return !rc.cachedAt.IsZero()
+	})
+}
@@ -0,0 +1,126 @@
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// StreamPath is the path RegisterRoutes mounts the stream of the status at,
+// see Stream.
+const StreamPath = "/debug/health/stream"
+
+const (
+	// DefaultStreamInterval is how often StreamHandler evaluates the checks.
+	DefaultStreamInterval = 5 * time.Second
+	// DefaultStreamHeartbeat is how often StreamHandler sends the status even
+	// if it did not change.
+	DefaultStreamHeartbeat = 30 * time.Second
+)
+
+// streamHandler serves the status of a registry as Server-Sent Events.
+type streamHandler struct {
+	registry  *Registry
+	interval  time.Duration
+	heartbeat time.Duration
+	handlerConfig
+}
+
+// Stream returns an http.Handler streaming the status of the registry as
+// Server-Sent Events. The checks are evaluated every interval and a "status"
+// event carrying the JSON status document is sent when a check changed its
+// health or message, and at least every heartbeat. The authorizers, the
+// redaction and the kind of checks set by opts apply like they do to
+// Handler.
+func (registry *Registry) Stream(interval, heartbeat time.Duration, opts ...HandlerOption) http.Handler {
+	h := &streamHandler{
+		registry:  registry,
+		interval:  interval,
+		heartbeat: heartbeat,
+		handlerConfig: handlerConfig{
+			include: func(*registeredCheck) bool { return true },
+		},
+	}
+	for _, opt := range opts {
+		opt(&h.handlerConfig)
+	}
+	return h
+}
+
+// StreamHandler streams the status of the registry as Server-Sent Events,
+// using DefaultStreamInterval and DefaultStreamHeartbeat. See Stream.
+func (registry *Registry) StreamHandler(w http.ResponseWriter, r *http.Request) {
+	registry.Stream(DefaultStreamInterval, DefaultStreamHeartbeat).ServeHTTP(w, r)
+}
+
+// StreamHandler streams the status of the default registry as Server-Sent
+// Events. See Registry.Stream.
+func StreamHandler(w http.ResponseWriter, r *http.Request) {
+	Default().StreamHandler(w, r)
+}
+
+// ServeHTTP implements http.Handler
+func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method != "GET" {
+		http.NotFound(w, r)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	ctx := r.Context()
+	t := time.NewTicker(h.interval)
+	defer t.Stop()
+
+	var (
+		last     string
+		lastSent time.Time
+	)
+	for {
+		checks := redact(h.registry.checkStatus(ctx, h.include), h.redact)
+		if ctx.Err() != nil {
+			return
+		}
+		sig := signature(checks)
+		if sig != last || time.Since(lastSent) >= h.heartbeat {
+			p, err := json.Marshal(checks)
+			if err != nil {
+				h.registry.log().Printf("error serializing health status: %v", err)
+				return
+			}
+			if _, err := fmt.Fprintf(w, "event: status\ndata: %s\n\n", p); err != nil {
+				return
+			}
+			flusher.Flush()
+			last, lastSent = sig, time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// signature summarizes the health and messages of the checks, ignoring
+// timings that change on every evaluation.
+func signature(checks Status) string {
+	var sig string
+	for _, name := range sortedNames(checks) {
+		check := checks[name]
+		sig += fmt.Sprintf("%q %s %q\n", name, check.State, check.Message)
+	}
+	return sig
+}
@@ -0,0 +1,72 @@
+package health
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestStreamHandler ensures the stream sends the status on changes only.
+func TestStreamHandler(t *testing.T) {
+	registry := NewRegistry()
+	updater := NewStatusUpdater()
+	registry.Register("check", updater)
+
+	server := httptest.NewServer(registry.Stream(5*time.Millisecond, time.Hour))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("error getting stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("unexpected content type: %q", ct)
+	}
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if line := scanner.Text(); strings.HasPrefix(line, "data: ") {
+				lines <- strings.TrimPrefix(line, "data: ")
+			}
+		}
+		close(lines)
+	}()
+
+	next := func() Status {
+		select {
+		case line := <-lines:
+			var status Status
+			if err := json.Unmarshal([]byte(line), &status); err != nil {
+				t.Fatalf("error decoding status: %v", err)
+			}
+			return status
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for status")
+		}
+		return nil
+	}
+
+	if status := next(); !status["check"].Healthy {
+		t.Errorf("check was expected to be healthy initially")
+	}
+
+	// unchanged status is not sent again
+	select {
+	case line := <-lines:
+		t.Fatalf("unexpected status: %s", line)
+	case <-time.After(30 * time.Millisecond):
+	}
+
+	updater.Update(Result{Error: errors.New("failing")})
+	if status := next(); status["check"].Healthy {
+		t.Errorf("check was expected to be unhealthy after the update")
+	}
+}
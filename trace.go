@@ -0,0 +1,144 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultTraceSize is the number of check executions traced by registries
+// created without a WithTrace option.
+const DefaultTraceSize = 128
+
+// TracePath is the path RegisterRoutes mounts TraceHandler at.
+const TracePath = "/debug/health/trace"
+
+// TraceEntry is an execution of a check.
+type TraceEntry struct {
+	Check string    `json:"check"`
+	Start time.Time `json:"start"`
+	// DurationMs is how long the check took in milliseconds.
+	DurationMs float64 `json:"duration_ms"`
+	// Outcome is the State of the check after the execution.
+	Outcome State  `json:"outcome"`
+	Error   string `json:"error,omitempty"`
+
+	seq       uint64
+	sensitive bool
+}
+
+// WithTrace sets the number of check executions the registry traces, across
+// all checks, see Registry.Trace. Zero disables tracing.
+func WithTrace(n int) RegistryOption {
+	return func(registry *Registry) {
+		registry.traceSize = n
+	}
+}
+
+// traceBuffer is a lock-free ring buffer of the last executions of checks.
+// Executions claim a slot by incrementing next, and may overwrite each other
+// when the buffer wraps faster than they are stored.
+type traceBuffer struct {
+	next  atomic.Uint64
+	slots []atomic.Pointer[TraceEntry]
+}
+
+// newTraceBuffer returns a buffer of size entries, or nil if size is not
+// positive.
+func newTraceBuffer(size int) *traceBuffer {
+	if size <= 0 {
+		return nil
+	}
+	return &traceBuffer{slots: make([]atomic.Pointer[TraceEntry], size)}
+}
+
+// add traces an execution of the named check with result res.
+func (b *traceBuffer) add(name string, sensitive bool, res Result) {
+	if b == nil {
+		return
+	}
+	entry := &TraceEntry{
+		Check:      name,
+		Start:      res.CheckedAt,
+		DurationMs: float64(res.Duration) / float64(time.Millisecond),
+		Outcome:    res.State(),
+		sensitive:  sensitive,
+	}
+	if res.Error != nil {
+		entry.Error = res.Error.Error()
+	}
+	entry.seq = b.next.Add(1) - 1
+	b.slots[entry.seq%uint64(len(b.slots))].Store(entry)
+}
+
+// entries returns the traced executions, oldest first.
+func (b *traceBuffer) entries() []TraceEntry {
+	if b == nil {
+		return nil
+	}
+	entries := make([]TraceEntry, 0, len(b.slots))
+	for i := range b.slots {
+		if entry := b.slots[i].Load(); entry != nil {
+			entries = append(entries, *entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].seq < entries[j].seq
+	})
+	return entries
+}
+
+// Trace returns the last executions of the checks of the registry, oldest
+// first. Like History, only actual runs are traced.
+func (registry *Registry) Trace() []TraceEntry {
+	return registry.trace.entries()
+}
+
+// Trace returns the last executions of the checks in the default registry.
+func Trace() []TraceEntry {
+	return Default().Trace()
+}
+
+// TraceHandler responds with the last executions of the checks as a JSON
+// array, oldest first, restricted to the check named by the "check" query
+// parameter if set. Errors of sensitive checks are redacted.
+func (registry *Registry) TraceHandler(w http.ResponseWriter, r *http.Request) {
+	registry.serveTrace(w, r, false)
+}
+
+// serveTrace responds with the last executions of the checks, redacting the
+// errors of all checks if redactAll is set.
+func (registry *Registry) serveTrace(w http.ResponseWriter, r *http.Request, redactAll bool) {
+	if r.Method != "GET" {
+		http.NotFound(w, r)
+		return
+	}
+
+	wanted := r.URL.Query().Get("check")
+	entries := []TraceEntry{}
+	for _, entry := range registry.Trace() {
+		if wanted != "" && wanted != entry.Check {
+			continue
+		}
+		if (redactAll || entry.sensitive) && entry.Error != "" {
+			entry.Error = RedactedMessage
+		}
+		entries = append(entries, entry)
+	}
+
+	p, err := json.Marshal(entries)
+	if err != nil {
+		registry.log().Printf("error serializing health trace: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, registry.log(), http.StatusOK, "application/json; charset=utf-8", p)
+}
+
+// TraceHandler responds with the last executions of the checks in the
+// default registry.
+func TraceHandler(w http.ResponseWriter, r *http.Request) {
+	Default().TraceHandler(w, r)
+}
@@ -0,0 +1,94 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// TestTrace ensures the last executions across checks are kept in order.
+func TestTrace(t *testing.T) {
+	registry := NewRegistry(WithTrace(3))
+	updater := NewStatusUpdater()
+	registry.Register("check", updater)
+
+	for i := 0; i < 5; i++ {
+		var res Result
+		if i%2 == 1 {
+			res.Error = errors.New("failing " + strconv.Itoa(i))
+		}
+		updater.Update(res)
+		registry.CheckStatus()
+	}
+
+	trace := registry.Trace()
+	if len(trace) != 3 {
+		t.Fatalf("expected 3 entries, got %+v", trace)
+	}
+	for i, entry := range trace {
+		failing := i%2 == 1
+		if entry.Check != "check" || (entry.Outcome == StateUnhealthy) != failing || (entry.Error != "") != failing {
+			t.Errorf("unexpected entry %d: %+v", i, entry)
+		}
+	}
+	if trace[1].Error != "failing 3" {
+		t.Errorf("unexpected error: %q", trace[1].Error)
+	}
+
+	if NewRegistry(WithTrace(0)).Trace() != nil {
+		t.Errorf("expected no trace when disabled")
+	}
+}
+
+// TestTraceConcurrent ensures concurrent executions are traced without
+// losing entries while the buffer has room.
+func TestTraceConcurrent(t *testing.T) {
+	registry := NewRegistry(WithTrace(100))
+	for i := 0; i < 10; i++ {
+		registry.Register("check"+strconv.Itoa(i), NewStatusUpdater())
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			registry.CheckStatus()
+		}()
+	}
+	wg.Wait()
+	if n := len(registry.Trace()); n < 10 || n > 50 {
+		t.Errorf("unexpected number of entries: %d", n)
+	}
+}
+
+// TestTraceHandler ensures the trace is served filtered and redacted.
+func TestTraceHandler(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("database", CheckFunc(func() Result {
+		return Result{Error: errors.New("password rejected")}
+	}), Sensitive())
+	registry.Register("cache", NewStatusUpdater())
+	registry.CheckStatus()
+
+	req, err := http.NewRequest("GET", "https://fakeurl.com"+TracePath+"?check=database", nil)
+	if err != nil {
+		t.Errorf("Failed to create request.")
+	}
+	recorder := httptest.NewRecorder()
+	registry.TraceHandler(recorder, req)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("unexpected status code: %d", recorder.Code)
+	}
+
+	var entries []TraceEntry
+	if err := json.Unmarshal(recorder.Body.Bytes(), &entries); err != nil {
+		t.Fatalf("error decoding trace: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Check != "database" || entries[0].Error != RedactedMessage {
+		t.Errorf("unexpected trace: %+v", entries)
+	}
+}
@@ -0,0 +1,43 @@
+package health
+
+import (
+	"context"
+	"log/slog"
+)
+
+// EnableTransitionLogging logs every change of a check between healthy and
+// unhealthy to logger, with the name of the check, the duration of the run,
+// its error and the number of consecutive failures. A check becoming
+// unhealthy is logged as a warning, recovering as info. A nil logger disables
+// transition logging.
+func (registry *Registry) EnableTransitionLogging(logger *slog.Logger) {
+	registry.watchMu.Lock()
+	defer registry.watchMu.Unlock()
+	registry.transitions = logger
+}
+
+// EnableTransitionLogging logs the changes of the checks in the default
+// registry to logger.
+func EnableTransitionLogging(logger *slog.Logger) {
+	Default().EnableTransitionLogging(logger)
+}
+
+// logTransition logs change to logger.
+func logTransition(logger *slog.Logger, change StatusChange) {
+	attrs := []slog.Attr{
+		slog.String("check", change.Name),
+		slog.Duration("duration", change.Duration),
+		slog.Int("consecutive_failures", change.Failures),
+	}
+	if change.Healthy {
+		logger.LogAttrs(context.Background(), slog.LevelInfo, "health check recovered", attrs...)
+		return
+	}
+	if change.Error != nil {
+		attrs = append(attrs, slog.String("error", change.Error.Error()))
+	}
+	if change.Message != "" {
+		attrs = append(attrs, slog.String("message", change.Message))
+	}
+	logger.LogAttrs(context.Background(), slog.LevelWarn, "health check failing", attrs...)
+}
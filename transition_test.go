@@ -0,0 +1,50 @@
+package health
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestTransitionLogging ensures transitions are logged with the consecutive
+// failure count, and nothing is logged while the status does not change.
+func TestTransitionLogging(t *testing.T) {
+	var buf bytes.Buffer
+	registry := NewRegistry()
+	registry.EnableTransitionLogging(slog.New(slog.NewTextHandler(&buf, nil)))
+	updater := NewStatusUpdater()
+	registry.Register("check", updater)
+
+	registry.CheckStatus()
+	updater.Update(Result{Error: errors.New("connection refused"), Message: "down"})
+	registry.CheckStatus()
+	registry.CheckStatus()
+	registry.CheckStatus()
+	updater.Update(Result{})
+	registry.CheckStatus()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 transitions, got %q", buf.String())
+	}
+	for _, expected := range []string{"level=WARN", `msg="health check failing"`, "check=check", "consecutive_failures=1", `error="connection refused"`, "message=down"} {
+		if !strings.Contains(lines[0], expected) {
+			t.Errorf("expected %s in %q", expected, lines[0])
+		}
+	}
+	for _, expected := range []string{"level=INFO", `msg="health check recovered"`, "consecutive_failures=3"} {
+		if !strings.Contains(lines[1], expected) {
+			t.Errorf("expected %s in %q", expected, lines[1])
+		}
+	}
+
+	buf.Reset()
+	registry.EnableTransitionLogging(nil)
+	updater.Update(Result{Error: errors.New("failing")})
+	registry.CheckStatus()
+	if buf.Len() != 0 {
+		t.Errorf("expected no logs after disabling, got %q", buf.String())
+	}
+}
@@ -0,0 +1,241 @@
+// Package upstream polls the health endpoints of other services and reports
+// them as checks, giving a service a consolidated view of the services it
+// depends on.
+//
+// Endpoints serving a health.StatusEnvelope, a health+json response or a
+// bare health.Status, like the handlers of the health package, are
+// understood: failing upstreams fail the check, degraded ones degrade it.
+// For other responses the status code decides.
+//
+//	upstream.Register(health.DefaultRegistry, "billing", "http://billing:8080/debug/health",
+//	  upstream.WithSeverity(health.Warning))
+package upstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/distribution/health"
+)
+
+const (
+	// DefaultPeriod is how often upstreams are polled without a WithPeriod
+	// option.
+	DefaultPeriod = 10 * time.Second
+	// DefaultTimeout is the timeout of a poll without a WithTimeout option.
+	DefaultTimeout = 5 * time.Second
+)
+
+// maxBodySize limits how much of a response is read.
+const maxBodySize = 1 << 20
+
+// upstream is the configuration of a polled upstream.
+type upstream struct {
+	url      string
+	period   time.Duration
+	timeout  time.Duration
+	client   *http.Client
+	checkOpt []health.CheckOption
+}
+
+// Option configures an upstream registered with Register.
+type Option func(*upstream)
+
+// WithPeriod sets how often the upstream is polled.
+func WithPeriod(period time.Duration) Option {
+	return func(u *upstream) {
+		u.period = period
+	}
+}
+
+// WithTimeout sets the timeout of a poll.
+func WithTimeout(timeout time.Duration) Option {
+	return func(u *upstream) {
+		u.timeout = timeout
+	}
+}
+
+// WithClient sets the HTTP client polling the upstream, e.g. to configure
+// TLS. The timeout of the poll applies on top of the client's.
+func WithClient(client *http.Client) Option {
+	return func(u *upstream) {
+		u.client = client
+	}
+}
+
+// WithSeverity sets the severity of the check of the upstream, so failing
+// upstreams the service can do without only degrade it. The default is
+// health.Critical.
+func WithSeverity(severity health.Severity) Option {
+	return func(u *upstream) {
+		u.checkOpt = append(u.checkOpt, health.WithSeverity(severity))
+	}
+}
+
+// WithCheckOptions registers the check of the upstream with opts.
+func WithCheckOptions(opts ...health.CheckOption) Option {
+	return func(u *upstream) {
+		u.checkOpt = append(u.checkOpt, opts...)
+	}
+}
+
+// Register registers a check named name reporting the health of the service
+// whose health endpoint is at url. The endpoint is polled on the scheduler
+// of registry, once before Register returns and then periodically.
+func Register(registry *health.Registry, name, url string, opts ...Option) {
+	u := &upstream{
+		url:     url,
+		period:  DefaultPeriod,
+		timeout: DefaultTimeout,
+		client:  http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	checker := health.PeriodicChecker(health.CheckFunc(func() health.Result {
+		return u.check(context.Background())
+	}), u.period,
+		health.RunImmediately(), health.WithScheduler(registry.Scheduler()))
+	registry.Register(name, checker, u.checkOpt...)
+}
+
+// Checker returns a checker requesting the health endpoint at url on every
+// run, within the context of the run. Only the WithTimeout and WithClient
+// options apply.
+func Checker(url string, opts ...Option) health.CheckerWithContext {
+	u := &upstream{
+		url:     url,
+		timeout: DefaultTimeout,
+		client:  http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return health.CheckFuncWithContext(u.check)
+}
+
+// check polls the upstream once.
+func (u *upstream) check(ctx context.Context) health.Result {
+	ctx, cancel := context.WithTimeout(ctx, u.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", u.url, nil)
+	if err != nil {
+		return failureErr("error creating request", err)
+	}
+	req.Header.Set("Accept", "application/json, "+health.HealthJSONContentType)
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return failureErr("error while checking", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return failureErr("error reading response", err)
+	}
+
+	details := map[string]any{"url": u.url, "status_code": resp.StatusCode}
+	overall, failing := parse(body)
+	switch overall {
+	case "":
+		// unknown formats and statuses are judged by the status code
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			code := health.CodeUnavailable
+			if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+				code = health.CodeAuth
+			}
+			return withDetails(failure(code, "upstream returned "+resp.Status), details)
+		}
+		return health.Result{Details: details}
+	case health.StatusFail:
+		msg := "upstream is failing"
+		if len(failing) > 0 {
+			msg += ": " + strings.Join(failing, ", ")
+		}
+		return withDetails(failure(health.CodeUnavailable, msg), details)
+	case health.StatusWarn:
+		msg := "upstream is degraded"
+		if len(failing) > 0 {
+			msg += ": " + strings.Join(failing, ", ")
+		}
+		return health.Result{Degraded: true, Message: msg, Details: details}
+	}
+	return health.Result{Details: details}
+}
+
+// statusAliases maps the aggregate statuses reported by common health
+// endpoints, like health+json and Spring Boot Actuator, to the ones of the
+// health package.
+var statusAliases = map[string]string{
+	health.StatusPass: health.StatusPass,
+	"up":              health.StatusPass,
+	"ok":              health.StatusPass,
+	health.StatusWarn: health.StatusWarn,
+	health.StatusFail: health.StatusFail,
+	"down":            health.StatusFail,
+	"error":           health.StatusFail,
+}
+
+// parse returns the aggregate status of a health response, and the names of
+// its failing checks if it lists them. The status is empty if the response
+// is not understood.
+func parse(body []byte) (string, []string) {
+	var envelope struct {
+		Status string          `json:"status"`
+		Checks json.RawMessage `json:"checks"`
+	}
+	if err := json.Unmarshal(body, &envelope); err == nil && envelope.Status != "" {
+		status, ok := statusAliases[strings.ToLower(envelope.Status)]
+		if !ok {
+			return "", nil
+		}
+		// health+json responses list their checks in another format
+		var checks health.Status
+		if err := json.Unmarshal(envelope.Checks, &checks); err != nil {
+			checks = nil
+		}
+		return status, failingNames(checks)
+	}
+
+	var checks health.Status
+	if err := json.Unmarshal(body, &checks); err != nil || len(checks) == 0 {
+		return "", nil
+	}
+	return checks.Overall(), failingNames(checks)
+}
+
+// failingNames returns the sorted names of the checks that do not pass,
+// failing or degraded.
+func failingNames(checks health.Status) []string {
+	var names []string
+	for name, check := range checks {
+		if check.Status() != health.StatusPass {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// failure returns a failing Result with msg as error and message, and code.
+func failure(code health.Code, msg string) health.Result {
+	return health.Result{Error: errors.New(msg), Message: msg, Code: code}
+}
+
+// failureErr returns a failing Result wrapping err, with the code of err.
+func failureErr(msg string, err error) health.Result {
+	err = fmt.Errorf("%s: %w", msg, err)
+	return health.Result{Error: err, Message: err.Error(), Code: health.CodeOf(err)}
+}
+
+// withDetails returns res with details.
+func withDetails(res health.Result, details map[string]any) health.Result {
+	res.Details = details
+	return res
+}
@@ -0,0 +1,90 @@
+package upstream
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/docker/distribution/health"
+)
+
+func TestChecker(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		code     int
+		body     string
+		healthy  bool
+		degraded bool
+		message  string
+	}{
+		{name: "envelope pass", code: 200, body: `{"status":"pass","checks":{"db":{"healthy":true}}}`, healthy: true},
+		{name: "envelope fail", code: 503, body: `{"status":"fail","checks":{"db":{"healthy":false},"cache":{"healthy":true}}}`, message: "upstream is failing: db"},
+		{name: "health+json warn", code: 200, body: `{"status":"warn","checks":{"db:latency":[{"status":"warn"}]}}`, healthy: true, degraded: true, message: "upstream is degraded"},
+		{name: "bare status", code: 503, body: `{"db":{"healthy":false,"severity":"critical"},"cache":{"healthy":true}}`, message: "upstream is failing: db"},
+		{name: "bare warning", code: 200, body: `{"cache":{"healthy":false,"severity":"warning"}}`, healthy: true, degraded: true, message: "upstream is degraded: cache"},
+		{name: "bare degraded", code: 200, body: `{"db":{"healthy":true,"state":"degraded"},"cache":{"healthy":true}}`, healthy: true, degraded: true, message: "upstream is degraded: db"},
+		{name: "bare initializing", code: 200, body: `{"db":{"healthy":false,"severity":"critical","initializing":true}}`, healthy: true, degraded: true, message: "upstream is degraded: db"},
+		{name: "spring boot up", code: 200, body: `{"status":"UP"}`, healthy: true},
+		{name: "spring boot down", code: 503, body: `{"status":"DOWN"}`, message: "upstream is failing"},
+		{name: "health+json error", code: 200, body: `{"status":"error"}`, message: "upstream is failing"},
+		{name: "unknown status", code: 503, body: `{"status":"OUT_OF_SERVICE"}`, message: "upstream returned 503 Service Unavailable"},
+		{name: "unknown status ok", code: 200, body: `{"status":"UNKNOWN"}`, healthy: true},
+		{name: "plain ok", code: 200, body: "OK", healthy: true},
+		{name: "plain error", code: 500, body: "oops", message: "upstream returned 500 Internal Server Error"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.code)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			res := Checker(server.URL).Check(context.Background())
+			if (res.Error == nil) != tc.healthy || res.Degraded != tc.degraded || res.Message != tc.message {
+				t.Errorf("unexpected result: %+v", res)
+			}
+			if res.Details["url"] != server.URL {
+				t.Errorf("expected the url in the details, got %v", res.Details)
+			}
+		})
+	}
+}
+
+func TestCheckerCodes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"status":"fail"}`))
+	}))
+	if res := Checker(server.URL).Check(context.Background()); res.Code != health.CodeUnavailable {
+		t.Errorf("expected a failing upstream to be unavailable, got %+v", res)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if res := Checker(server.URL).Check(ctx); !errors.Is(res.Error, context.Canceled) || res.Code != health.CodeCancelled {
+		t.Errorf("expected the context of the run to cancel the request, got %+v", res)
+	}
+
+	server.Close()
+	if res := Checker(server.URL).Check(context.Background()); res.Code != health.CodeConnRefused {
+		t.Errorf("expected the cause of an unreachable upstream to be kept, got %+v", res)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	service := health.NewRegistry()
+	service.Register("db", health.CheckFunc(func() health.Result { return health.Result{} }))
+	server := httptest.NewServer(service.Handler(health.WithEnvelope()))
+	defer server.Close()
+
+	registry := health.NewRegistry()
+	defer registry.Close()
+	Register(registry, "service", server.URL, WithSeverity(health.Warning))
+
+	check := registry.CheckStatus()["service"]
+	if !check.Healthy || check.Severity != health.Warning {
+		t.Errorf("expected the upstream to be polled at registration, got %+v", check)
+	}
+}
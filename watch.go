@@ -0,0 +1,74 @@
+package health
+
+import (
+	"context"
+	"time"
+)
+
+// watchBuffer is the number of changes buffered for a watcher that is not
+// keeping up. Further changes are dropped until it catches up.
+const watchBuffer = 16
+
+// StatusChange describes a check changing between healthy and unhealthy.
+type StatusChange struct {
+	Name    string
+	Healthy bool
+	Message string
+	Time    time.Time
+	// Error is the error of the run that made the check unhealthy.
+	Error error
+	// Duration is the time the run took.
+	Duration time.Duration
+	// Failures is the number of consecutive failures. When the check
+	// recovers it is the number of failures that preceded the recovery.
+	Failures int
+}
+
+// Watch returns a channel receiving a StatusChange every time a check of the
+// registry changes between healthy and unhealthy. Checks are assumed healthy
+// until they first run. Changes are observed whenever the checks are
+// evaluated, e.g. by CheckStatus or a status handler.
+//
+// The channel is closed when ctx is done. Changes are dropped if the receiver
+// falls too far behind.
+func (registry *Registry) Watch(ctx context.Context) <-chan StatusChange {
+	ch := make(chan StatusChange, watchBuffer)
+
+	registry.watchMu.Lock()
+	if registry.watchers == nil {
+		registry.watchers = make(map[chan StatusChange]struct{})
+	}
+	registry.watchers[ch] = struct{}{}
+	registry.watchMu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		registry.watchMu.Lock()
+		defer registry.watchMu.Unlock()
+		delete(registry.watchers, ch)
+		close(ch)
+	}()
+
+	return ch
+}
+
+// Watch returns a channel receiving changes of the checks in the default
+// registry.
+func Watch(ctx context.Context) <-chan StatusChange {
+	return Default().Watch(ctx)
+}
+
+// notify sends change to all watchers without blocking.
+func (registry *Registry) notify(change StatusChange) {
+	registry.watchMu.Lock()
+	defer registry.watchMu.Unlock()
+	if registry.transitions != nil {
+		logTransition(registry.transitions, change)
+	}
+	for ch := range registry.watchers {
+		select {
+		case ch <- change:
+		default:
+		}
+	}
+}
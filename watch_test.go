@@ -0,0 +1,46 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestWatch ensures watchers receive transitions, and only transitions.
+func TestWatch(t *testing.T) {
+	registry := NewRegistry()
+	updater := NewStatusUpdater()
+	registry.Register("check", updater)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := registry.Watch(ctx)
+
+	registry.CheckStatus()
+	updater.Update(Result{Error: errors.New("failing"), Message: "failing"})
+	registry.CheckStatus()
+	registry.CheckStatus()
+	updater.Update(Result{})
+	registry.CheckStatus()
+
+	for _, expected := range []bool{false, true} {
+		select {
+		case change := <-changes:
+			if change.Name != "check" || change.Healthy != expected {
+				t.Errorf("unexpected change: %+v", change)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for change")
+		}
+	}
+
+	cancel()
+	select {
+	case change, ok := <-changes:
+		if ok {
+			t.Errorf("unexpected change: %+v", change)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("channel was not closed")
+	}
+}
@@ -0,0 +1,66 @@
+// Package wirehealth provides a *health.Registry to applications wired with
+// the wire code generator.
+//
+// ProviderSet supplies a *health.Registry holding the checks of the
+// Providers of the application, which wire closes with the cleanup function
+// of the injector, and a Handler serving its status endpoints.
+//
+//	func providers(db *database.Client, cache *cache.Client) wirehealth.Providers {
+//	  return wirehealth.Providers{db, cache}
+//	}
+//
+//	func initServer() (*Server, func(), error) {
+//	  wire.Build(wirehealth.ProviderSet, providers, wire.Value([]health.RegistryOption(nil)), NewServer)
+//	  return nil, nil, nil
+//	}
+package wirehealth
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/docker/distribution/health"
+	"github.com/google/wire"
+)
+
+// ProviderSet provides a *health.Registry, see NewRegistry, and a Handler,
+// see NewHandler.
+var ProviderSet = wire.NewSet(NewRegistry, NewHandler)
+
+// Providers are the check providers of the application, whose checks are
+// registered by NewRegistry.
+type Providers []health.CheckProvider
+
+// Handler serves the status endpoints of a registry.
+type Handler http.Handler
+
+// NewRegistry returns a registry created with opts, holding the checks of
+// providers. It fails if two checks have the same name. The returned cleanup
+// function closes the registry and its scheduler.
+func NewRegistry(providers Providers, opts []health.RegistryOption) (*health.Registry, func(), error) {
+	registry := health.NewRegistry(opts...)
+	for _, provider := range providers {
+		checks := provider.HealthChecks()
+		names := make([]string, 0, len(checks))
+		for name := range checks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if err := registry.TryRegister(name, checks[name]); err != nil {
+				registry.Close()
+				return nil, nil, fmt.Errorf("wirehealth: %w", err)
+			}
+		}
+	}
+	return registry, func() { registry.Close() }, nil
+}
+
+// NewHandler returns a Handler serving the status endpoints of registry, see
+// Registry.RegisterRoutes.
+func NewHandler(registry *health.Registry) Handler {
+	mux := http.NewServeMux()
+	registry.RegisterRoutes(mux)
+	return mux
+}
@@ -0,0 +1,55 @@
+package wirehealth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/docker/distribution/health"
+)
+
+// provider is a library exposing checks.
+type provider map[string]health.Checker
+
+// HealthChecks implements the health.CheckProvider interface
+func (p provider) HealthChecks() map[string]health.Checker {
+	return p
+}
+
+func healthy() health.Checker {
+	return health.CheckFunc(func() health.Result { return health.Result{} })
+}
+
+func TestNewRegistry(t *testing.T) {
+	registry, cleanup, err := NewRegistry(Providers{
+		provider{"database": healthy()},
+		provider{"cache": healthy()},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer cleanup()
+
+	if status := registry.CheckStatus(); len(status) != 2 || !status["database"].Healthy || !status["cache"].Healthy {
+		t.Errorf("unexpected status: %+v", status)
+	}
+
+	req, err := http.NewRequest("GET", "https://fakeurl.com"+health.LivezPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to create request.")
+	}
+	recorder := httptest.NewRecorder()
+	NewHandler(registry).ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusOK {
+		t.Errorf("unexpected response from the handler: %d %s", recorder.Code, recorder.Body)
+	}
+
+	_, _, err = NewRegistry(Providers{
+		provider{"database": healthy()},
+		provider{"database": healthy()},
+	}, nil)
+	if !errors.Is(err, health.ErrAlreadyRegistered) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
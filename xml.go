@@ -0,0 +1,78 @@
+package health
+
+import (
+	"encoding/xml"
+	"net/http"
+	"time"
+)
+
+// xmlStatus is the XML document served to clients accepting only XML, such
+// as legacy monitors:
+//
+//	<health status="fail" timestamp="2024-05-01T12:00:00Z" score="0">
+//	  <check name="database" healthy="false" severity="critical" state="unhealthy" status="fail" duration_ms="1.5">
+//	    <message>connection refused</message>
+//	    <last_checked>2024-05-01T12:00:00Z</last_checked>
+//	  </check>
+//	</health>
+type xmlStatus struct {
+	XMLName   xml.Name   `xml:"health"`
+	Status    string     `xml:"status,attr"`
+	Timestamp time.Time  `xml:"timestamp,attr"`
+	Score     float64    `xml:"score,attr"`
+	Checks    []xmlCheck `xml:"check"`
+}
+
+// xmlCheck is a check in an xmlStatus document.
+type xmlCheck struct {
+	Name         string     `xml:"name,attr"`
+	Healthy      bool       `xml:"healthy,attr"`
+	Severity     Severity   `xml:"severity,attr"`
+	State        State      `xml:"state,attr,omitempty"`
+	Code         Code       `xml:"code,attr,omitempty"`
+	Status       string     `xml:"status,attr"`
+	DurationMs   float64    `xml:"duration_ms,attr"`
+	Initializing bool       `xml:"initializing,attr,omitempty"`
+	Disabled     bool       `xml:"disabled,attr,omitempty"`
+	Message      string     `xml:"message,omitempty"`
+	LastChecked  *time.Time `xml:"last_checked,omitempty"`
+	LastSuccess  *time.Time `xml:"last_success,omitempty"`
+}
+
+// xmlResponse responds with the status of the checks as an XML document.
+func xmlResponse(w http.ResponseWriter, logger Logger, status int, checks Status, overall string, now time.Time) {
+	doc := xmlStatus{
+		Status:    overall,
+		Timestamp: now.UTC(),
+		Score:     score(checks),
+	}
+	for _, name := range sortedNames(checks) {
+		check := checks[name]
+		c := xmlCheck{
+			Name:         name,
+			Healthy:      check.Healthy,
+			Severity:     check.Severity,
+			State:        check.State,
+			Code:         check.Code,
+			Status:       check.Status(),
+			DurationMs:   check.DurationMs,
+			Initializing: check.Initializing,
+			Disabled:     check.Disabled,
+			Message:      check.Message,
+			LastSuccess:  check.LastSuccess,
+		}
+		if !check.LastChecked.IsZero() {
+			lastChecked := check.LastChecked
+			c.LastChecked = &lastChecked
+		}
+		doc.Checks = append(doc.Checks, c)
+	}
+
+	p, err := xml.Marshal(doc)
+	if err != nil {
+		logger.Printf("error serializing health status as XML: %v", err)
+		http.Error(w, "Could not serialize health status", http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, logger, status, "application/xml; charset=utf-8", append([]byte(xml.Header), p...))
+}
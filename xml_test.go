@@ -0,0 +1,55 @@
+package health
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestXMLResponse ensures clients accepting XML are served an XML document.
+func TestXMLResponse(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("database", CheckFunc(func() Result {
+		return Result{Message: "connection <refused>", Error: errors.New("connection refused")}
+	}))
+	registry.Register("cache", NewStatusUpdater(), WithSeverity(Warning))
+
+	for _, accept := range []string{"application/xml", "text/xml"} {
+		req, err := http.NewRequest("GET", "https://fakeurl.com/debug/health", nil)
+		if err != nil {
+			t.Errorf("Failed to create request.")
+		}
+		req.Header.Set("Accept", accept)
+		recorder := httptest.NewRecorder()
+		registry.Handler().ServeHTTP(recorder, req)
+
+		if recorder.Code != http.StatusServiceUnavailable {
+			t.Errorf("unexpected status code: %d", recorder.Code)
+		}
+		if ct := recorder.Header().Get("Content-Type"); ct != "application/xml; charset=utf-8" {
+			t.Errorf("unexpected content type: %q", ct)
+		}
+		if !strings.HasPrefix(recorder.Body.String(), xml.Header) {
+			t.Errorf("expected an XML declaration: %s", recorder.Body.String())
+		}
+
+		var doc xmlStatus
+		if err := xml.Unmarshal(recorder.Body.Bytes(), &doc); err != nil {
+			t.Fatalf("error decoding response: %v", err)
+		}
+		if doc.Status != StatusFail || len(doc.Checks) != 2 {
+			t.Fatalf("unexpected document: %s", recorder.Body.String())
+		}
+		cache, database := doc.Checks[0], doc.Checks[1]
+		if cache.Name != "cache" || !cache.Healthy || cache.Severity != Warning || cache.Status != StatusPass {
+			t.Errorf("unexpected cache check: %+v", cache)
+		}
+		if database.Name != "database" || database.Healthy || database.Message != "connection <refused>" ||
+			database.Status != StatusFail || database.LastChecked == nil || database.LastSuccess != nil {
+			t.Errorf("unexpected database check: %+v", database)
+		}
+	}
+}